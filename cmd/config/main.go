@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+)
+
+const usage = `用法: config [-conf configs/config.yaml] <命令>
+
+命令:
+  print    输出合并环境变量后实际生效的配置，密钥等敏感配置被隐藏
+  check    校验配置，存在问题时逐条输出并以非零状态退出
+
+配置项可通过 APP_ 前缀的环境变量覆盖，如 jwt.access_secret 对应 APP_JWT_ACCESS_SECRET，
+APP_JWT_ACCESS_SECRET_FILE 指定从文件读取
+`
+
+func main() {
+	envConf := flag.String("conf", "configs/config.yaml", "config path, eg: -conf ./configs/config.yaml")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	conf, err := config.NewConfig(*envConf)
+	if err != nil {
+		fatal(err)
+	}
+	switch flag.Arg(0) {
+	case "print":
+		out, err := conf.RedactedYAML()
+		if err != nil {
+			fatal(err)
+		}
+		os.Stdout.Write(out)
+	case "check":
+		if err := conf.Validate(); err != nil {
+			fatal(fmt.Errorf("配置校验失败:\n%w", err))
+		}
+		fmt.Println("配置校验通过")
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "config:", err)
+	os.Exit(1)
+}
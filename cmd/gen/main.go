@@ -18,12 +18,9 @@ func main() {
 		panic(err)
 	}
 	logger := log.NewLog(&conf.Log)
-	db := gormx.NewDB(conf, logger)
-	// 启用 soft_delete 插件
-	//db.Use(soft_delete.New(soft_delete.Config{
-	//	Field: "deleted_at", // 软删除字段名
-	//	Value: 1,            // 删除时的标志位值
-	//}))
+	db, cleanup := gormx.NewDB(conf, logger)
+	defer cleanup()
+	// 软删除使用 gorm.DeletedAt：deleted_at 为 datetime 列时 gen 自动生成该类型，查询默认排除已删除的记录
 	g := gen.NewGenerator(gen.Config{
 		OutPath:      "internal/repository",
 		ModelPkgPath: "internal/model",
@@ -33,7 +30,7 @@ func main() {
 
 	// g.GenerateModel("dict_types")
 	// g.GenerateModel("dict_data")
-	g.ApplyBasic(g.GenerateModel("sys_menus"), model.DictType{}, model.DictDatum{}, model.Role{}, model.RoleMenus{}, model.User{}, model.UserRoles{})
+	g.ApplyBasic(g.GenerateModel("sys_menus"), model.DictType{}, model.DictDatum{}, model.Role{}, model.RoleMenus{}, model.User{}, model.UserRoles{}, model.UserIdentity{}, model.OAuthClient{}, model.OAuthConsent{}, model.UserOperationLog{})
 	// g.GenerateAllTable()
 	// g.GenerateAllTable()
 	g.Execute()
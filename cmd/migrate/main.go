@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wxlbd/gin-casbin-admin/migrations"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/gormx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"github.com/wxlbd/gin-casbin-admin/pkg/migrate"
+)
+
+const usage = `用法: migrate [-conf configs/config.yaml] <命令>
+
+命令:
+  up              执行所有未执行的迁移
+  down [n]        回滚最近执行的 n 个迁移，默认为 1
+  status          查看迁移执行状态
+  to <version>    迁移到指定版本，高于该版本的迁移会被回滚，0 表示回滚全部
+  force <version> 不执行迁移，只将迁移历史设为指定版本：已有表结构的数据库用于建立基线，
+                  迁移中途失败并手动修复后用于修正记录
+`
+
+func main() {
+	envConf := flag.String("conf", "configs/config.yaml", "config path, eg: -conf ./configs/config.yaml")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	conf, err := config.NewConfig(*envConf)
+	if err != nil {
+		panic(err)
+	}
+	logger := log.NewLog(&conf.Log)
+	db, cleanup := gormx.NewDB(conf, logger)
+	defer cleanup()
+	m, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		fatal(err)
+	}
+
+	ctx := context.Background()
+	args := flag.Args()
+	var done []*migrate.Migration
+	switch args[0] {
+	case "up":
+		done, err = m.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+				fatal(fmt.Errorf("无效的回滚数量 %q", args[1]))
+			}
+		}
+		done, err = m.Down(ctx, steps)
+	case "to":
+		if len(args) < 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		version, perr := strconv.ParseInt(args[1], 10, 64)
+		if perr != nil || version < 0 {
+			fatal(fmt.Errorf("无效的版本号 %q", args[1]))
+		}
+		done, err = m.To(ctx, version)
+	case "force":
+		if len(args) < 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		version, perr := strconv.ParseInt(args[1], 10, 64)
+		if perr != nil || version < 0 {
+			fatal(fmt.Errorf("无效的版本号 %q", args[1]))
+		}
+		if err := m.Force(ctx, version); err != nil {
+			fatal(err)
+		}
+		fmt.Printf("已将迁移历史设为版本 %d，未执行任何迁移\n", version)
+		return
+	case "status":
+		if err := printStatus(ctx, m); err != nil {
+			fatal(err)
+		}
+		return
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	for _, mig := range done {
+		fmt.Printf("%06d_%s\n", mig.Version, mig.Name)
+	}
+	if err != nil {
+		fatal(err)
+	}
+	version, err := m.Version(ctx)
+	if err != nil {
+		fatal(err)
+	}
+	fmt.Printf("当前版本: %d，共执行 %d 个迁移\n", version, len(done))
+}
+
+func printStatus(ctx context.Context, m *migrate.Migrator) error {
+	list, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "版本\t名称\t状态\t执行时间")
+	for _, s := range list {
+		state, appliedAt := "未执行", ""
+		if s.Applied {
+			state, appliedAt = "已执行", s.AppliedAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "%06d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "migrate:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/wxlbd/gin-casbin-admin/internal/repository"
+	"github.com/wxlbd/gin-casbin-admin/internal/service"
+	"github.com/wxlbd/gin-casbin-admin/pkg/casbinx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/gormx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+)
+
+// 初始化全新安装所需的数据，需先执行 migrate up 创建表结构，可重复执行
+func main() {
+	envConf := flag.String("conf", "configs/config.yaml", "config path, eg: -conf ./configs/config.yaml")
+	username := flag.String("admin-username", "admin", "管理员用户名")
+	password := flag.String("admin-password", "", "管理员密码，为空时随机生成，也可通过环境变量 SEED_ADMIN_PASSWORD 指定")
+	reset := flag.Bool("reset-admin-password", false, "管理员已存在时重置其密码并启用账号")
+	flag.Parse()
+	if *password == "" {
+		*password = os.Getenv("SEED_ADMIN_PASSWORD")
+	}
+
+	conf, err := config.NewConfig(*envConf)
+	if err != nil {
+		panic(err)
+	}
+	logger := log.NewLog(&conf.Log)
+	db, cleanup := gormx.NewDB(conf, logger)
+	defer cleanup()
+	enforcer, err := casbinx.New(db)
+	if err != nil {
+		fatal(err)
+	}
+
+	seeder := service.NewSeeder(repository.NewRepository(db), enforcer)
+	result, err := seeder.Seed(context.Background(), service.SeedOptions{
+		AdminUsername:      *username,
+		AdminPassword:      *password,
+		ResetAdminPassword: *reset,
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	fmt.Printf("超级管理员角色: %s\n", createdOrKept(result.RoleCreated))
+	fmt.Printf("菜单: 新建 %d 个，角色菜单: 新建 %d 个，权限策略: 新增 %d 条\n", result.MenusCreated, result.RoleMenusCreated, result.PoliciesAdded)
+	fmt.Printf("字典类型: 新建 %d 个，字典数据: 新建 %d 个\n", result.DictTypesCreated, result.DictDataCreated)
+	switch {
+	case result.AdminCreated:
+		fmt.Printf("管理员 %s: 已创建\n", *username)
+	case result.AdminPasswordReset:
+		fmt.Printf("管理员 %s: 已重置密码\n", *username)
+	default:
+		fmt.Printf("管理员 %s: 已存在，未修改密码\n", *username)
+		if *password != "" {
+			fmt.Println("指定的管理员密码未生效，如需修改请使用 -reset-admin-password")
+		}
+	}
+	// 仅在随机生成时输出密码，指定的密码不回显
+	if result.AdminPassword != "" && *password == "" {
+		fmt.Printf("管理员密码: %s\n请登录后立即修改\n", result.AdminPassword)
+	}
+	if result.PoliciesAdded > 0 {
+		fmt.Println("权限策略已写入数据库，运行中的服务需要重启后生效")
+	}
+}
+
+func createdOrKept(created bool) string {
+	if created {
+		return "已创建"
+	}
+	return "已存在"
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "seed:", err)
+	os.Exit(1)
+}
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/wxlbd/gin-casbin-admin/cmd/server/wire"
 	_ "github.com/wxlbd/gin-casbin-admin/docs" // 导入 swagger docs
 	"github.com/wxlbd/gin-casbin-admin/pkg/config"
 	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"github.com/wxlbd/gin-casbin-admin/pkg/otelx"
 	"go.uber.org/zap"
 )
 
@@ -36,15 +42,55 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	if err := conf.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "配置校验失败:\n%v\n", err)
+		os.Exit(1)
+	}
 	logger := log.NewLog(&conf.Log)
 
-	app, cleanup, err := wire.NewWire(conf, logger)
-	defer cleanup()
+	// 配置文件修改或收到 SIGHUP 信号时重新加载可在运行时生效的配置
+	watcher := config.NewWatcher(config.Path(*envConf), conf, logger.Logger)
+	watcher.Subscribe(func(prev, next *config.Config) {
+		if prev.Log.LogLevel != next.Log.LogLevel {
+			logger.SetLevel(next.Log.LogLevel)
+			logger.Info("日志级别已修改", zap.String("level", next.Log.LogLevel))
+		}
+	})
+	watcher.Start()
+
+	// 链路追踪在创建依赖前设置，启动阶段的查询同样被记录
+	shutdownTracing, err := otelx.New(conf)
 	if err != nil {
+		watcher.Stop()
 		panic(err)
 	}
-	logger.Info("server start", zap.String("host", fmt.Sprintf("http://%s:%d", conf.Server.Host, conf.Server.Port)))
-	if err = app.Run(fmt.Sprintf("%s:%d", conf.Server.Host, conf.Server.Port)); err != nil {
+
+	app, cleanup, err := wire.NewWire(conf, watcher, logger)
+	if err != nil {
+		watcher.Stop()
 		panic(err)
 	}
+
+	// 收到 SIGINT 或 SIGTERM 后优雅停机，停机期间再次收到信号时直接退出
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	err = app.Run(ctx)
+
+	// HTTP 服务停止后依次停止后台任务，再关闭数据库和 Redis 连接
+	watcher.Stop()
+	cleanup()
+	// 导出剩余的 span
+	tracingCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(tracingCtx); err != nil {
+		logger.Error("关闭链路追踪失败", zap.Error(err))
+	}
+	cancel()
+	if err != nil {
+		logger.Fatal("server stopped with error", zap.Error(err))
+	}
+	logger.Info("server exited")
+	_ = logger.Sync()
 }
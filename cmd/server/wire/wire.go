@@ -4,7 +4,6 @@
 package wire
 
 import (
-	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 	"github.com/wxlbd/gin-casbin-admin/internal/handler"
 	"github.com/wxlbd/gin-casbin-admin/internal/repository"
@@ -13,18 +12,26 @@ import (
 	"github.com/wxlbd/gin-casbin-admin/pkg/casbinx"
 	"github.com/wxlbd/gin-casbin-admin/pkg/config"
 	"github.com/wxlbd/gin-casbin-admin/pkg/gormx"
+	httpx "github.com/wxlbd/gin-casbin-admin/pkg/http"
 	"github.com/wxlbd/gin-casbin-admin/pkg/jwtx"
 	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"github.com/wxlbd/gin-casbin-admin/pkg/mailx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/oidcx"
 	"github.com/wxlbd/gin-casbin-admin/pkg/redisx"
 )
 
-var ServerSet = wire.NewSet(server.NewServerHTTP)
+var ServerSet = wire.NewSet(
+	server.NewServerHTTP,
+	httpx.NewState,
+	httpx.NewServer,
+)
 
 var RepositorySet = wire.NewSet(
 	repository.NewRepository,
 )
 
 var ServiceSet = wire.NewSet(
+	service.NewAuthenticators,
 	service.NewService,
 )
 
@@ -32,12 +39,14 @@ var HandlerSet = wire.NewSet(
 	handler.NewHandler,
 )
 
-func NewWire(cfg *config.Config, logger *log.Logger) (*gin.Engine, func(), error) {
+func NewWire(cfg *config.Config, watcher *config.Watcher, logger *log.Logger) (*httpx.Server, func(), error) {
 	panic(wire.Build(
 		casbinx.New,
 		gormx.NewDB,
 		redisx.New,
 		jwtx.New,
+		mailx.New,
+		oidcx.New,
 		ServerSet,
 		RepositorySet,
 		ServiceSet,
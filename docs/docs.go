@@ -2715,6 +2715,10 @@ const docTemplate = `{
                 "data": {},
                 "message": {
                     "type": "string"
+                },
+                "requestId": {
+                    "description": "RequestID 请求ID，反馈问题时据此查找对应的日志",
+                    "type": "string"
                 }
             }
         },
@@ -2,5 +2,11 @@ package dto
 
 type CaptchaResponse struct {
 	CaptchaId    string `json:"captcha_id"`    // 验证码ID
-	CaptchaImage string `json:"captcha_image"` // Base64编码的验证码图片
+	CaptchaImage string `json:"captcha_image"` // Base64编码的验证码图片，audio 类型为 Base64 编码的音频
+	CaptchaType  string `json:"captcha_type"`  // 验证码类型：digit、string、math、audio、slider
+	Required     bool   `json:"required"`      // 当前登录是否需要验证码，不需要时不返回验证码内容
+
+	// 以下字段仅滑块验证码返回
+	SliderImage string `json:"slider_image,omitempty"` // Base64编码的滑块图片
+	SliderY     int    `json:"slider_y,omitempty"`     // 滑块在背景图中的纵向位置
 }
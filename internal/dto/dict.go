@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"gorm.io/gorm"
 )
 
 // DictType DTOs
@@ -25,6 +26,8 @@ type DictTypeResponse struct {
 	Remark    string    `json:"remark"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// DeletedAt 删除时间，仅回收站列表返回
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 // DictData DTOs
@@ -48,6 +51,16 @@ type DictDataResponse struct {
 	Remark    string    `json:"remark"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// DeletedAt 删除时间，仅回收站列表返回
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// DictItemResponse 前端下拉框等场景使用的字典项
+type DictItemResponse struct {
+	Label  string `json:"label"`
+	Value  string `json:"value"`
+	Sort   int32  `json:"sort"`
+	Remark string `json:"remark"`
 }
 
 // 转换函数
@@ -61,6 +74,7 @@ func ToDictTypeResponse(dict *model.DictType) *DictTypeResponse {
 		Remark:    dict.Remark,
 		CreatedAt: dict.CreatedAt,
 		UpdatedAt: dict.UpdatedAt,
+		DeletedAt: deletedTime(dict.DeletedAt),
 	}
 }
 
@@ -75,5 +89,97 @@ func ToDictDataResponse(data *model.DictDatum) *DictDataResponse {
 		Remark:    data.Remark,
 		CreatedAt: data.CreatedAt,
 		UpdatedAt: data.UpdatedAt,
+		DeletedAt: deletedTime(data.DeletedAt),
+	}
+}
+
+// deletedTime 未删除时返回 nil
+func deletedTime(t gorm.DeletedAt) *time.Time {
+	if !t.Valid {
+		return nil
 	}
+	return &t.Time
+}
+
+func ToDictItemResponse(list []*model.DictDatum) []*DictItemResponse {
+	items := make([]*DictItemResponse, len(list))
+	for i, data := range list {
+		items[i] = &DictItemResponse{
+			Label:  data.Label,
+			Value:  data.Value,
+			Sort:   data.Sort,
+			Remark: data.Remark,
+		}
+	}
+	return items
+}
+
+// DictBundle 字典包，用于在不同环境之间同步字典类型及其数据
+type DictBundle struct {
+	Version    int               `json:"version" yaml:"version"`
+	ExportedAt time.Time         `json:"exported_at" yaml:"exported_at"`
+	Types      []*DictBundleType `json:"types" yaml:"types"`
+}
+
+type DictBundleType struct {
+	Code   string            `json:"code" yaml:"code"`
+	Name   string            `json:"name" yaml:"name"`
+	Status int32             `json:"status" yaml:"status"`
+	Sort   int32             `json:"sort" yaml:"sort"`
+	Remark string            `json:"remark" yaml:"remark"`
+	Data   []*DictBundleData `json:"data" yaml:"data"`
+}
+
+type DictBundleData struct {
+	Label  string `json:"label" yaml:"label"`
+	Value  string `json:"value" yaml:"value"`
+	Status int32  `json:"status" yaml:"status"`
+	Sort   int32  `json:"sort" yaml:"sort"`
+	Remark string `json:"remark" yaml:"remark"`
+}
+
+// 字典包导入时每条记录的处理方式
+const (
+	DictDiffCreate    = "create"
+	DictDiffUpdate    = "update"
+	DictDiffUnchanged = "unchanged"
+	// DictDiffRestore 从回收站恢复，Changes 为恢复后需要更新的字段
+	DictDiffRestore = "restore"
+)
+
+// DictFieldChange 字段变更
+type DictFieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// DictTypeDiff 字典类型的变更，Data 只包含新增和修改的字典数据
+type DictTypeDiff struct {
+	Code    string             `json:"code"`
+	Name    string             `json:"name"`
+	Action  string             `json:"action"`
+	Changes []*DictFieldChange `json:"changes,omitempty"`
+	Data    []*DictDataDiff    `json:"data,omitempty"`
+}
+
+type DictDataDiff struct {
+	Value   string             `json:"value"`
+	Label   string             `json:"label"`
+	Action  string             `json:"action"`
+	Changes []*DictFieldChange `json:"changes,omitempty"`
+}
+
+// DictBundleImportResponse 字典包导入结果，预览模式下为将要执行的变更
+type DictBundleImportResponse struct {
+	DryRun        bool            `json:"dry_run"`
+	TypeCreated   int             `json:"type_created"`
+	TypeUpdated   int             `json:"type_updated"`
+	TypeUnchanged int             `json:"type_unchanged"`
+	TypeRestored  int             `json:"type_restored"`
+	DataCreated   int             `json:"data_created"`
+	DataUpdated   int             `json:"data_updated"`
+	DataUnchanged int             `json:"data_unchanged"`
+	DataRestored  int             `json:"data_restored"`
+	Types         []*DictTypeDiff `json:"types"`
 }
@@ -0,0 +1,8 @@
+package dto
+
+// ExportRequest 列表导出参数，与各列表的筛选条件一起使用
+type ExportRequest struct {
+	Format  string `form:"format" binding:"omitempty,oneof=xlsx csv jsonl"` // 导出格式，默认 xlsx
+	Columns string `form:"columns"`                                         // 导出的列，多个以逗号分隔，默认全部列
+	Lang    string `form:"lang"`                                            // 表头语言，默认取 Accept-Language
+}
@@ -0,0 +1,24 @@
+package dto
+
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusFail        = "fail"
+	HealthStatusDraining    = "draining"
+	HealthStatusUnavailable = "unavailable"
+)
+
+// ReadinessResponse 就绪检查结果
+type ReadinessResponse struct {
+	// Status ok 可以接收流量；draining 服务正在停止；unavailable 存在不可用的依赖
+	Status string `json:"status"`
+	// Checks 各依赖的检查结果，键为 database、redis、casbin，停止期间不再检查依赖
+	Checks map[string]*DependencyCheck `json:"checks,omitempty"`
+}
+
+// DependencyCheck 单个依赖的检查结果
+type DependencyCheck struct {
+	Status  string `json:"status"`  // ok 或 fail
+	Latency string `json:"latency"` // 检查耗时
+	// Reason 失败原因：timeout 检查超时；no_policy 未加载任何权限策略；check_failed 其他错误，详细错误只记录在日志中
+	Reason string `json:"reason,omitempty"`
+}
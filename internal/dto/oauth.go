@@ -0,0 +1,192 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
+)
+
+// OAuthAuthorizeRequest 授权码模式的授权请求，参数与 RFC 6749 一致
+type OAuthAuthorizeRequest struct {
+	ResponseType string `json:"response_type" form:"response_type" binding:"required,eq=code"`
+	ClientID     string `json:"client_id" form:"client_id" binding:"required"`
+	RedirectURI  string `json:"redirect_uri" form:"redirect_uri" binding:"required"`
+	// Scope 申请的权限范围，多个以空格分隔，为空时申请客户端允许的全部范围
+	Scope string `json:"scope" form:"scope"`
+	State string `json:"state" form:"state"`
+	// CodeChallenge PKCE 挑战码，CodeChallengeMethod 支持 S256 和 plain
+	CodeChallenge       string `json:"code_challenge" form:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method" form:"code_challenge_method" binding:"omitempty,oneof=S256 plain"`
+}
+
+// OAuthApproveRequest 用户确认或拒绝授权
+type OAuthApproveRequest struct {
+	OAuthAuthorizeRequest
+	Approve bool `json:"approve"`
+}
+
+// OAuthScope 权限范围及其对应的菜单名称
+type OAuthScope struct {
+	Code  string `json:"code"`
+	Title string `json:"title"`
+}
+
+// OAuthAuthorizeResponse 授权确认页所需信息
+type OAuthAuthorizeResponse struct {
+	ClientID   string        `json:"client_id"`
+	ClientName string        `json:"client_name"`
+	Scopes     []*OAuthScope `json:"scopes"`
+	// Consented 用户已授权过相同范围或应用免确认，前端可直接提交授权
+	Consented bool `json:"consented"`
+}
+
+// OAuthApproveResponse 授权结果，前端跳转到该地址将授权码或错误返回给客户端
+type OAuthApproveResponse struct {
+	RedirectURL string `json:"redirect_url"`
+}
+
+// OAuthTokenRequest 令牌请求，客户端凭证也可以通过 HTTP Basic 认证传递
+type OAuthTokenRequest struct {
+	GrantType    string `form:"grant_type" binding:"required"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	Scope        string `form:"scope"`
+}
+
+// OAuthTokenResponse 令牌响应，格式遵循 RFC 6749 第 5.1 节
+type OAuthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+// OAuthError 令牌接口错误，格式遵循 RFC 6749 第 5.2 节
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+	Status      int    `json:"-"`
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+// OAuthUserInfoResponse 令牌对应的用户信息
+type OAuthUserInfoResponse struct {
+	Sub      string `json:"sub"`
+	Username string `json:"username,omitempty"`
+	Nickname string `json:"nickname,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Avatar   string `json:"avatar,omitempty"`
+	ClientID string `json:"client_id"`
+	Scope    string `json:"scope"`
+}
+
+// OAuthClientRequest 创建/更新客户端应用请求
+type OAuthClientRequest struct {
+	Name         string   `json:"name" binding:"required"`
+	RedirectURIs []string `json:"redirect_uris" binding:"dive,url"`
+	GrantTypes   []string `json:"grant_types" binding:"required,min=1,dive,oneof=authorization_code client_credentials"`
+	Scopes       []string `json:"scopes"`
+	SkipConsent  bool     `json:"skip_consent"`
+	Status       int8     `json:"status"`
+	Remark       string   `json:"remark"`
+}
+
+func (req *OAuthClientRequest) ToModel(id uint64) *model.OAuthClient {
+	status := req.Status
+	if status == 0 {
+		status = 1
+	}
+	return &model.OAuthClient{
+		ID:           id,
+		Name:         req.Name,
+		RedirectURIs: strings.Join(req.RedirectURIs, " "),
+		GrantTypes:   strings.Join(req.GrantTypes, " "),
+		Scopes:       strings.Join(req.Scopes, " "),
+		SkipConsent:  req.SkipConsent,
+		Status:       status,
+		Remark:       req.Remark,
+	}
+}
+
+// OAuthClientListRequest 客户端应用列表请求
+type OAuthClientListRequest struct {
+	*types.PageParam
+	Name     string `form:"name"`
+	ClientID string `form:"client_id"`
+	Status   int8   `form:"status"`
+}
+
+func (r *OAuthClientListRequest) ToModel() *model.OAuthClientQuery {
+	if r.PageParam == nil {
+		r.PageParam = &types.PageParam{}
+	}
+	r.Normalize()
+	return &model.OAuthClientQuery{
+		PageParam: r.PageParam,
+		Name:      r.Name,
+		ClientID:  r.ClientID,
+		Status:    r.Status,
+	}
+}
+
+// OAuthClientResponse 客户端应用信息，不包含密钥
+type OAuthClientResponse struct {
+	ID           uint64   `json:"id"`
+	ClientID     string   `json:"client_id"`
+	Name         string   `json:"name"`
+	RedirectURIs []string `json:"redirect_uris"`
+	GrantTypes   []string `json:"grant_types"`
+	Scopes       []string `json:"scopes"`
+	SkipConsent  bool     `json:"skip_consent"`
+	Status       int8     `json:"status"`
+	Remark       string   `json:"remark"`
+	Created      string   `json:"created"`
+	Updated      string   `json:"updated"`
+}
+
+func ToOAuthClientResponse(client *model.OAuthClient) *OAuthClientResponse {
+	if client == nil {
+		return nil
+	}
+	return &OAuthClientResponse{
+		ID:           client.ID,
+		ClientID:     client.ClientID,
+		Name:         client.Name,
+		RedirectURIs: strings.Fields(client.RedirectURIs),
+		GrantTypes:   strings.Fields(client.GrantTypes),
+		Scopes:       strings.Fields(client.Scopes),
+		SkipConsent:  client.SkipConsent,
+		Status:       client.Status,
+		Remark:       client.Remark,
+		Created:      client.CreatedAt.Format(time.DateTime),
+		Updated:      client.UpdatedAt.Format(time.DateTime),
+	}
+}
+
+// OAuthClientListResponse 客户端应用列表响应
+type OAuthClientListResponse struct {
+	List  []*OAuthClientResponse `json:"list"`
+	Total int64                  `json:"total"`
+}
+
+func ToOAuthClientListResponse(clients []*model.OAuthClient, total int64) *OAuthClientListResponse {
+	list := make([]*OAuthClientResponse, 0, len(clients))
+	for _, client := range clients {
+		list = append(list, ToOAuthClientResponse(client))
+	}
+	return &OAuthClientListResponse{List: list, Total: total}
+}
+
+// OAuthClientSecretResponse 客户端密钥，仅在创建和重置时返回一次
+type OAuthClientSecretResponse struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
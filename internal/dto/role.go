@@ -11,7 +11,7 @@ func ToRoleResponse(role *model.Role) *RoleResponse {
 	if role == nil {
 		return nil
 	}
-	return &RoleResponse{
+	resp := &RoleResponse{
 		ID:      role.ID,
 		Name:    role.Name,
 		Code:    role.Code,
@@ -21,6 +21,10 @@ func ToRoleResponse(role *model.Role) *RoleResponse {
 		Created: role.CreatedAt.Format(time.DateTime),
 		Updated: role.UpdatedAt.Format(time.DateTime),
 	}
+	if role.DeletedAt.Valid {
+		resp.Deleted = role.DeletedAt.Time.Format(time.DateTime)
+	}
+	return resp
 }
 
 func ToRoleList(roles []*model.Role) []*RoleResponse {
@@ -52,15 +56,21 @@ type RoleListRequest struct {
 	Name   string `form:"name"`
 	Code   string `form:"code"`
 	Status int8   `form:"status"`
+	// SkipCount 不统计总数，用于导出等逐页读取全部数据的场景
+	SkipCount bool `form:"-"`
 }
 
 func (r *RoleListRequest) ToModel() *model.RoleQuery {
+	if r.PageParam == nil {
+		r.PageParam = &types.PageParam{}
+	}
 	r.Normalize()
 	return &model.RoleQuery{
 		PageParam: r.PageParam,
 		Name:      r.Name,
 		Code:      r.Code,
 		Status:    r.Status,
+		SkipCount: r.SkipCount,
 	}
 }
 
@@ -77,6 +87,8 @@ type RoleResponse struct {
 	Remark  string `json:"remark"`
 	Created string `json:"created"`
 	Updated string `json:"updated"`
+	// Deleted 删除时间，仅回收站列表返回
+	Deleted string `json:"deleted,omitempty"`
 }
 
 // RoleListResponse 角色列表响应
@@ -60,6 +60,8 @@ type SysMenuResponse struct {
 	Status          int32     `json:"status"`
 	CreatedAt       time.Time `json:"createdAt"`
 	UpdatedAt       time.Time `json:"updatedAt"`
+	// DeletedAt 删除时间，仅回收站列表返回
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
 }
 
 // SysMenuListRequest 菜单列表请求
@@ -99,6 +101,7 @@ func ToSysMenuResponse(menu *model.SysMenu) *SysMenuResponse {
 		Status:          menu.Status,
 		CreatedAt:       menu.CreatedAt,
 		UpdatedAt:       menu.UpdatedAt,
+		DeletedAt:       deletedTime(menu.DeletedAt),
 	}
 }
 
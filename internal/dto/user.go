@@ -42,6 +42,8 @@ type UserResponse struct {
 	UpdatedBy uint64 `json:"updated_by"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
+	// DeletedAt 删除时间，仅回收站列表返回
+	DeletedAt string `json:"deleted_at,omitempty"`
 }
 
 // ToModel 转换方法
@@ -74,7 +76,7 @@ func ToUserResponse(m *model.User) *UserResponse {
 	if m == nil {
 		return nil
 	}
-	return &UserResponse{
+	resp := &UserResponse{
 		ID:       m.ID,
 		Username: m.Username,
 		UserBase: UserBase{
@@ -92,6 +94,10 @@ func ToUserResponse(m *model.User) *UserResponse {
 		CreatedAt: m.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt: m.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
+	if m.DeletedAt.Valid {
+		resp.DeletedAt = m.DeletedAt.Time.Format("2006-01-02 15:04:05")
+	}
+	return resp
 }
 
 // ToUserResponseList 将用户列表转换为响应 DTO 列表
@@ -118,8 +124,8 @@ func ToUserListResponse(users []*model.User, total int64) *UserListResponse {
 type LoginRequest struct {
 	Username    string `json:"username" binding:"required"`
 	Password    string `json:"password" binding:"required"`
-	CaptchaId   string `json:"captcha_id" binding:"required"`   // 验证码ID
-	CaptchaCode string `json:"captcha_code" binding:"required"` // 验证码值
+	CaptchaId   string `json:"captcha_id"`   // 验证码ID，按验证码策略不需要时可为空
+	CaptchaCode string `json:"captcha_code"` // 验证码值，滑块验证码为滑块的横向偏移量
 }
 
 // RefreshTokenRequest 刷新令牌请求
@@ -138,6 +144,10 @@ type UserListRequest struct {
 }
 
 func (req *UserListRequest) ToModel() *model.UserQuery {
+	if req.PageParam == nil {
+		req.PageParam = &types.PageParam{}
+	}
+	req.Normalize()
 	return &model.UserQuery{
 		Page:     req.Page,
 		PageSize: req.PageSize,
@@ -149,12 +159,89 @@ func (req *UserListRequest) ToModel() *model.UserQuery {
 	}
 }
 
+// UserExportRequest 用户导出请求，筛选条件与用户列表一致
+type UserExportRequest struct {
+	Username string `form:"username"`
+	Nickname string `form:"nickname"`
+	Phone    string `form:"phone"`
+	Email    string `form:"email"`
+	Status   int8   `form:"status"`
+}
+
+func (req *UserExportRequest) ToModel() *model.UserQuery {
+	return &model.UserQuery{
+		Username: req.Username,
+		Nickname: req.Nickname,
+		Phone:    req.Phone,
+		Email:    req.Email,
+		Status:   req.Status,
+	}
+}
+
+// UserImportError 用户导入的行级错误
+type UserImportError struct {
+	Row      int    `json:"row"` // 表格中的行号，表头为第 1 行
+	Username string `json:"username"`
+	Message  string `json:"message"`
+}
+
+// UserImportResponse 用户导入结果
+type UserImportResponse struct {
+	DryRun  bool               `json:"dry_run"`
+	Total   int                `json:"total"`
+	Success int                `json:"success"` // 试运行时为校验通过的行数
+	Failed  int                `json:"failed"`
+	Errors  []*UserImportError `json:"errors"`
+	// ReportID 存在错误时生成的错误报告，可通过导入报告接口下载
+	ReportID string `json:"report_id,omitempty"`
+}
+
 // ResetPasswordRequest 修改密码请求
 type ResetPasswordRequest struct {
 	ID       uint64
 	Password string `json:"password" binding:"required,min=6"`
 }
 
+// UpdateProfileRequest 修改个人资料请求
+type UpdateProfileRequest struct {
+	Nickname string `json:"nickname" binding:"max=30"`
+	Phone    string `json:"phone" binding:"omitempty,max=11"`
+	Email    string `json:"email" binding:"omitempty,email,max=50"`
+	Signed   string `json:"signed" binding:"max=255"`
+}
+
+func (req *UpdateProfileRequest) ToModel(id uint64) *model.User {
+	return &model.User{
+		ID:       id,
+		Nickname: req.Nickname,
+		Phone:    req.Phone,
+		Email:    req.Email,
+		Signed:   req.Signed,
+	}
+}
+
+// UpdatePasswordRequest 修改个人密码请求
+type UpdatePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6,nefield=OldPassword"`
+}
+
+// UpdateAvatarRequest 修改头像请求
+type UpdateAvatarRequest struct {
+	Avatar string `json:"avatar" binding:"required,max=255"`
+}
+
+// ForgotPasswordRequest 找回密码请求
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordByTokenRequest 通过重置令牌设置新密码请求
+type ResetPasswordByTokenRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
 // LoginResponse 登录响应
 type LoginResponse struct {
 	AccessToken  string `json:"accessToken"`
@@ -162,6 +249,14 @@ type LoginResponse struct {
 	Expires      string `json:"expires"`
 }
 
+// ImpersonateResponse 模拟登录响应
+type ImpersonateResponse struct {
+	AccessToken string `json:"accessToken"`
+	Expires     string `json:"expires"`
+	UserID      uint64 `json:"userId"`
+	Username    string `json:"username"`
+}
+
 // UserListResponse 用户列表响应
 type UserListResponse struct {
 	List  []*UserResponse `json:"list"`
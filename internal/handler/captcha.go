@@ -2,7 +2,6 @@ package handler
 
 import (
 	"github.com/gin-gonic/gin"
-	"github.com/wxlbd/gin-casbin-admin/internal/dto"
 	"github.com/wxlbd/gin-casbin-admin/pkg/ginx"
 )
 
@@ -18,7 +17,7 @@ func NewCaptchaHandler(svc Service) *CaptchaHandler {
 
 // Generate 生成验证码
 // @Summary 生成验证码
-// @Description 生成图片验证码
+// @Description 按配置生成数字、字符、算术、音频或滑块验证码；验证码策略为登录失败后才需要时，当前 IP 无需验证码则只返回 required=false
 // @Tags 认证管理
 // @Accept json
 // @Produce json
@@ -26,14 +25,11 @@ func NewCaptchaHandler(svc Service) *CaptchaHandler {
 // @Failure 500 {object} ginx.Response "服务器内部错误"
 // @Router /auth/captcha [get]
 func (h *CaptchaHandler) Generate(c *gin.Context) {
-	id, b64s, err := h.svc.Captcha().Generate(c)
+	resp, err := h.svc.Captcha().Generate(c, c.ClientIP())
 	if err != nil {
 		ginx.ServerError(c, err)
 		return
 	}
 
-	ginx.Success(c, &dto.CaptchaResponse{
-		CaptchaId:    id,
-		CaptchaImage: b64s,
-	})
+	ginx.Success(c, resp)
 }
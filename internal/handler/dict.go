@@ -1,6 +1,12 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -8,7 +14,9 @@ import (
 	"github.com/wxlbd/gin-casbin-admin/internal/dto"
 	"github.com/wxlbd/gin-casbin-admin/internal/model"
 	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
+	"github.com/wxlbd/gin-casbin-admin/pkg/exportx"
 	"github.com/wxlbd/gin-casbin-admin/pkg/ginx"
+	"gopkg.in/yaml.v3"
 )
 
 type DictHandler struct {
@@ -348,20 +356,23 @@ func (h *DictHandler) ListDictData(c *gin.Context) {
 	})
 }
 
+// maxDictBatchTypes 批量获取字典时最多的类型数量
+const maxDictBatchTypes = 50
+
 // GetDictDataByType 根据字典类型获取字典数据
 // @Summary 根据字典类型获取字典数据
-// @Description 根据字典类型编码获取字典数据列表
+// @Description 根据字典类型编码获取启用的字典数据，按排序值升序
 // @Tags 字典管理
 // @Accept json
 // @Produce json
-// @Param type path string true "字典类型编码"
-// @Success 200 {object} ginx.Response{data=[]dto.DictDataResponse} "成功"
+// @Param typeCode path string true "字典类型编码"
+// @Success 200 {object} ginx.Response{data=[]dto.DictItemResponse} "成功"
 // @Failure 400 {object} ginx.Response "请求参数错误"
 // @Failure 500 {object} ginx.Response "服务器内部错误"
 // @Security Bearer
-// @Router /system/dict/data/type/{type} [get]
+// @Router /dict/{typeCode} [get]
 func (h *DictHandler) GetDictDataByType(c *gin.Context) {
-	typeCode := c.Param("type")
+	typeCode := c.Param("typeCode")
 	if typeCode == "" {
 		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "字典类型编码不能为空"))
 		return
@@ -373,10 +384,335 @@ func (h *DictHandler) GetDictDataByType(c *gin.Context) {
 		return
 	}
 
-	var resp []*dto.DictDataResponse
-	for _, item := range list {
-		resp = append(resp, dto.ToDictDataResponse(item))
+	ginx.Success(c, dto.ToDictItemResponse(list))
+}
+
+// GetDictDataByTypes 批量获取字典数据
+// @Summary 批量获取字典数据
+// @Description 一次获取多个字典类型下启用的字典数据，返回以类型编码为键的对象
+// @Tags 字典管理
+// @Accept json
+// @Produce json
+// @Param types query string true "字典类型编码，多个用逗号分隔"
+// @Success 200 {object} ginx.Response{data=map[string][]dto.DictItemResponse} "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 500 {object} ginx.Response "服务器内部错误"
+// @Security Bearer
+// @Router /dict [get]
+func (h *DictHandler) GetDictDataByTypes(c *gin.Context) {
+	var typeCodes []string
+	seen := make(map[string]bool)
+	for _, code := range strings.Split(c.Query("types"), ",") {
+		code = strings.TrimSpace(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		typeCodes = append(typeCodes, code)
+	}
+	if len(typeCodes) == 0 {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "字典类型编码不能为空"))
+		return
+	}
+	if len(typeCodes) > maxDictBatchTypes {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "字典类型数量超出限制"))
+		return
 	}
 
+	data, err := h.svc.GetDictDataByTypes(c, typeCodes)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+
+	resp := make(map[string][]*dto.DictItemResponse, len(typeCodes))
+	for _, code := range typeCodes {
+		resp[code] = dto.ToDictItemResponse(data[code])
+	}
 	ginx.Success(c, resp)
 }
+
+// dictTypeExportColumns 字典类型导出列
+var dictTypeExportColumns = []exportx.Column[*model.DictType]{
+	{Key: "id", Headers: map[string]string{"zh": "字典类型ID", "en": "ID"}, Value: func(d *model.DictType) any { return d.ID }},
+	{Key: "code", Headers: map[string]string{"zh": "类型编码", "en": "Code"}, Value: func(d *model.DictType) any { return d.Code }},
+	{Key: "name", Headers: map[string]string{"zh": "类型名称", "en": "Name"}, Value: func(d *model.DictType) any { return d.Name }},
+	{Key: "status", Headers: map[string]string{"zh": "状态", "en": "Status"}, Value: func(d *model.DictType) any { return d.Status }},
+	{Key: "sort", Headers: map[string]string{"zh": "排序", "en": "Sort"}, Value: func(d *model.DictType) any { return d.Sort }},
+	{Key: "remark", Headers: map[string]string{"zh": "备注", "en": "Remark"}, Value: func(d *model.DictType) any { return d.Remark }},
+	{Key: "created_at", Headers: map[string]string{"zh": "创建时间", "en": "Created At"}, Value: func(d *model.DictType) any { return formatTime(d.CreatedAt) }},
+}
+
+// dictDataExportColumns 字典数据导出列
+var dictDataExportColumns = []exportx.Column[*model.DictDatum]{
+	{Key: "id", Headers: map[string]string{"zh": "字典数据ID", "en": "ID"}, Value: func(d *model.DictDatum) any { return d.ID }},
+	{Key: "type_code", Headers: map[string]string{"zh": "类型编码", "en": "Type Code"}, Value: func(d *model.DictDatum) any { return d.TypeCode }},
+	{Key: "label", Headers: map[string]string{"zh": "字典标签", "en": "Label"}, Value: func(d *model.DictDatum) any { return d.Label }},
+	{Key: "value", Headers: map[string]string{"zh": "字典值", "en": "Value"}, Value: func(d *model.DictDatum) any { return d.Value }},
+	{Key: "status", Headers: map[string]string{"zh": "状态", "en": "Status"}, Value: func(d *model.DictDatum) any { return d.Status }},
+	{Key: "sort", Headers: map[string]string{"zh": "排序", "en": "Sort"}, Value: func(d *model.DictDatum) any { return d.Sort }},
+	{Key: "remark", Headers: map[string]string{"zh": "备注", "en": "Remark"}, Value: func(d *model.DictDatum) any { return d.Remark }},
+	{Key: "created_at", Headers: map[string]string{"zh": "创建时间", "en": "Created At"}, Value: func(d *model.DictDatum) any { return formatTime(d.CreatedAt) }},
+}
+
+// ExportDictType 导出字典类型
+// @Summary 导出字典类型
+// @Description 按与字典类型列表相同的筛选条件导出，支持 xlsx、csv 和 jsonl 格式，可选择导出列
+// @Tags 字典管理
+// @Produce application/octet-stream
+// @Param data query model.DictTypeQuery true "筛选条件"
+// @Param export query dto.ExportRequest true "导出参数"
+// @Success 200 {file} file "字典类型表格"
+// @Security Bearer
+// @Router /system/dict-type/export [get]
+func (h *DictHandler) ExportDictType(c *gin.Context) {
+	var query model.DictTypeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+	query.SkipCount = true
+	export(c, "dict-types", dictTypeExportColumns, func(ctx context.Context, page, size int) ([]*model.DictType, error) {
+		query.Page, query.PageSize = page, size
+		list, _, err := h.svc.ListDictType(ctx, &query)
+		return list, err
+	})
+}
+
+// ExportDictData 导出字典数据
+// @Summary 导出字典数据
+// @Description 按与字典数据列表相同的筛选条件导出，支持 xlsx、csv 和 jsonl 格式，可选择导出列
+// @Tags 字典管理
+// @Produce application/octet-stream
+// @Param data query model.DictDataQuery true "筛选条件"
+// @Param export query dto.ExportRequest true "导出参数"
+// @Success 200 {file} file "字典数据表格"
+// @Security Bearer
+// @Router /system/dict-data/export [get]
+func (h *DictHandler) ExportDictData(c *gin.Context) {
+	var query model.DictDataQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+	query.SkipCount = true
+	export(c, "dict-data", dictDataExportColumns, func(ctx context.Context, page, size int) ([]*model.DictDatum, error) {
+		query.Page, query.PageSize = page, size
+		list, _, err := h.svc.ListDictData(ctx, &query)
+		return list, err
+	})
+}
+
+// 字典包文件格式
+const (
+	dictBundleJSON = "json"
+	dictBundleYAML = "yaml"
+)
+
+// ExportBundle 导出字典包
+// @Summary 导出字典包
+// @Description 导出选中的字典类型及其全部数据，用于同步到其他环境
+// @Tags 字典管理
+// @Produce application/octet-stream
+// @Param codes query string true "字典类型编码，多个用逗号分隔"
+// @Param format query string false "文件格式：json（默认）、yaml"
+// @Success 200 {file} file "字典包"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 404 {object} ginx.Response "字典类型不存在"
+// @Security Bearer
+// @Router /system/dict-type/export/bundle [get]
+func (h *DictHandler) ExportBundle(c *gin.Context) {
+	var codes []string
+	for _, code := range strings.Split(c.Query("codes"), ",") {
+		if code = strings.TrimSpace(code); code != "" && !slices.Contains(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "请选择要导出的字典类型"))
+		return
+	}
+	format, err := parseDictBundleFormat(c.DefaultQuery("format", dictBundleJSON))
+	if err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+
+	bundle, err := h.svc.ExportBundle(c, codes)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+
+	var data []byte
+	contentType := "application/json; charset=utf-8"
+	if format == dictBundleYAML {
+		data, err = yaml.Marshal(bundle)
+		contentType = "application/yaml; charset=utf-8"
+	} else {
+		data, err = json.MarshalIndent(bundle, "", "  ")
+	}
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	filename := fmt.Sprintf("dict-bundle-%s.%s", bundle.ExportedAt.Format("20060102150405"), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// ImportBundle 导入字典包
+// @Summary 导入字典包
+// @Description 按类型编码、字典值新增或更新字典，不删除字典包中没有的数据；dry_run=true 时只返回变更预览
+// @Tags 字典管理
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "字典包文件（.json、.yaml、.yml）"
+// @Param dry_run query bool false "只预览变更不写入"
+// @Success 200 {object} ginx.Response{data=dto.DictBundleImportResponse} "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Security Bearer
+// @Router /system/dict-type/import/bundle [post]
+func (h *DictHandler) ImportBundle(c *gin.Context) {
+	fh, err := c.FormFile("file")
+	if err != nil {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "请上传文件"))
+		return
+	}
+	if fh.Size > maxImportFileSize {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "文件不能超过10MB"))
+		return
+	}
+	format, err := parseDictBundleFormat(strings.TrimPrefix(filepath.Ext(fh.Filename), "."))
+	if err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	f, err := fh.Open()
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	defer f.Close()
+
+	var bundle dto.DictBundle
+	if format == dictBundleYAML {
+		err = yaml.NewDecoder(f).Decode(&bundle)
+	} else {
+		err = json.NewDecoder(f).Decode(&bundle)
+	}
+	if err != nil {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "字典包格式错误: "+err.Error()))
+		return
+	}
+
+	resp, err := h.svc.ImportBundle(c, &bundle, dryRun)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	ginx.Success(c, resp)
+}
+
+func parseDictBundleFormat(s string) (string, error) {
+	switch strings.ToLower(s) {
+	case dictBundleJSON:
+		return dictBundleJSON, nil
+	case dictBundleYAML, "yml":
+		return dictBundleYAML, nil
+	default:
+		return "", errors.WithMsg(errors.InvalidParam, "仅支持 json 和 yaml 格式")
+	}
+}
+
+// DictTypeRecycle 回收站列表
+// @Summary 字典类型回收站列表
+// @Description 分页查询已删除的字典类型，最近删除的在前
+// @Tags 字典管理
+// @Produce json
+// @Param pageNum query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Success 200 {object} ginx.Response{data=ginx.ListData{list=[]dto.DictTypeResponse,total=int64}} "成功"
+// @Failure 500 {object} ginx.Response "服务器内部错误"
+// @Security Bearer
+// @Router /system/dict-type/recycle [get]
+func (h *DictHandler) DictTypeRecycle(c *gin.Context) {
+	listDeleted(c, h.svc.ListDeletedDictType, dto.ToDictTypeResponse)
+}
+
+// DictTypeRestore 从回收站恢复
+// @Summary 恢复字典类型
+// @Description 从回收站恢复指定ID的字典类型
+// @Tags 字典管理
+// @Produce json
+// @Param ids path string true "字典类型ID列表(多个用逗号分隔)"
+// @Success 200 {object} ginx.Response "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 404 {object} ginx.Response "回收站中不存在"
+// @Security Bearer
+// @Router /system/dict-type/recycle/{ids} [put]
+func (h *DictHandler) DictTypeRestore(c *gin.Context) {
+	recycleAction(c, h.svc.RestoreDictType)
+}
+
+// DictTypePurge 彻底删除
+// @Summary 彻底删除字典类型
+// @Description 彻底删除回收站中指定ID的字典类型，删除后无法恢复
+// @Tags 字典管理
+// @Produce json
+// @Param ids path string true "字典类型ID列表(多个用逗号分隔)"
+// @Success 200 {object} ginx.Response "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 404 {object} ginx.Response "回收站中不存在"
+// @Security Bearer
+// @Router /system/dict-type/recycle/{ids} [delete]
+func (h *DictHandler) DictTypePurge(c *gin.Context) {
+	recycleAction(c, h.svc.PurgeDictType)
+}
+
+// DictDataRecycle 回收站列表
+// @Summary 字典数据回收站列表
+// @Description 分页查询已删除的字典数据，最近删除的在前
+// @Tags 字典管理
+// @Produce json
+// @Param pageNum query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Success 200 {object} ginx.Response{data=ginx.ListData{list=[]dto.DictDataResponse,total=int64}} "成功"
+// @Failure 500 {object} ginx.Response "服务器内部错误"
+// @Security Bearer
+// @Router /system/dict-data/recycle [get]
+func (h *DictHandler) DictDataRecycle(c *gin.Context) {
+	listDeleted(c, h.svc.ListDeletedDictData, dto.ToDictDataResponse)
+}
+
+// DictDataRestore 从回收站恢复
+// @Summary 恢复字典数据
+// @Description 从回收站恢复指定ID的字典数据
+// @Tags 字典管理
+// @Produce json
+// @Param ids path string true "字典数据ID列表(多个用逗号分隔)"
+// @Success 200 {object} ginx.Response "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 404 {object} ginx.Response "回收站中不存在"
+// @Security Bearer
+// @Router /system/dict-data/recycle/{ids} [put]
+func (h *DictHandler) DictDataRestore(c *gin.Context) {
+	recycleAction(c, h.svc.RestoreDictData)
+}
+
+// DictDataPurge 彻底删除
+// @Summary 彻底删除字典数据
+// @Description 彻底删除回收站中指定ID的字典数据，删除后无法恢复
+// @Tags 字典管理
+// @Produce json
+// @Param ids path string true "字典数据ID列表(多个用逗号分隔)"
+// @Success 200 {object} ginx.Response "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 404 {object} ginx.Response "回收站中不存在"
+// @Security Bearer
+// @Router /system/dict-data/recycle/{ids} [delete]
+func (h *DictHandler) DictDataPurge(c *gin.Context) {
+	recycleAction(c, h.svc.PurgeDictData)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wxlbd/gin-casbin-admin/internal/dto"
+	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
+	"github.com/wxlbd/gin-casbin-admin/pkg/exportx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/ginx"
+	httpx "github.com/wxlbd/gin-casbin-admin/pkg/http"
+)
+
+// exportWriteTimeout 导出时单次写出数据的超时，每次写出后重新计时，不受服务端 write_timeout 限制
+const exportWriteTimeout = time.Minute
+
+// export 按导出参数选择格式、列和表头语言，以附件形式流式输出列表数据
+func export[T any](c *gin.Context, name string, columns []exportx.Column[T], fetch exportx.FetchFunc[T]) {
+	var req dto.ExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+	format, err := exportx.ParseFormat(req.Format)
+	if err != nil {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "不支持的导出格式"))
+		return
+	}
+	var keys []string
+	if req.Columns != "" {
+		keys = strings.Split(req.Columns, ",")
+	}
+	columns, err = exportx.Select(columns, keys)
+	if err != nil {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "导出列不存在"))
+		return
+	}
+	lang := req.Lang
+	if lang == "" {
+		lang = acceptLanguage(c.GetHeader("Accept-Language"))
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s"`, name, time.Now().Format("20060102150405"), format))
+	if err := exportx.Export(c, httpx.StreamWriter(c.Writer, exportWriteTimeout), exportx.Options{Format: format, Lang: lang}, columns, fetch); err != nil {
+		// 数据已开始写出后无法再返回错误响应，只能中断下载
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "")
+			c.Header("Content-Disposition", "")
+		}
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
+
+// acceptLanguage 取 Accept-Language 中的第一个语言
+func acceptLanguage(header string) string {
+	lang, _, _ := strings.Cut(header, ",")
+	lang, _, _ = strings.Cut(lang, ";")
+	return strings.TrimSpace(lang)
+}
+
+// formatTime 导出时间列，零值输出为空
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.DateTime)
+}
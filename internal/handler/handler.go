@@ -2,6 +2,7 @@ package handler
 
 import (
 	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/log"
 )
 
 type Handler struct {
@@ -10,16 +11,22 @@ type Handler struct {
 	dict    *DictHandler
 	captcha *CaptchaHandler
 	sysMenu *SysMenuHandler
+	oidc    *OIDCHandler
+	oauth   *OAuthHandler
+	health  *HealthHandler
 	cfg     *config.Config
 }
 
-func NewHandler(svc Service, cfg *config.Config) *Handler {
+func NewHandler(svc Service, cfg *config.Config, logger *log.Logger) *Handler {
 	return &Handler{
 		user:    NewUserHandler(svc, cfg),
 		role:    NewRoleHandler(svc),
 		dict:    NewDictHandler(svc.Dict()),
 		captcha: NewCaptchaHandler(svc),
 		sysMenu: NewSysMenuHandler(svc),
+		oidc:    NewOIDCHandler(svc, cfg, logger),
+		oauth:   NewOAuthHandler(svc),
+		health:  NewHealthHandler(svc),
 		cfg:     cfg,
 	}
 }
@@ -43,3 +50,15 @@ func (h *Handler) Captcha() *CaptchaHandler {
 func (h *Handler) SysMenu() *SysMenuHandler {
 	return h.sysMenu
 }
+
+func (h *Handler) OIDC() *OIDCHandler {
+	return h.oidc
+}
+
+func (h *Handler) OAuth() *OAuthHandler {
+	return h.oauth
+}
+
+func (h *Handler) Health() *HealthHandler {
+	return h.health
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wxlbd/gin-casbin-admin/internal/dto"
+	"github.com/wxlbd/gin-casbin-admin/pkg/ginx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/version"
+)
+
+// HealthHandler 供负载均衡和 Kubernetes 使用的探针接口，不在 /api 下且无需登录
+type HealthHandler struct {
+	svc Service
+}
+
+func NewHealthHandler(svc Service) *HealthHandler {
+	return &HealthHandler{
+		svc: svc,
+	}
+}
+
+// Live 存活检查，进程能处理请求即返回成功，不检查外部依赖，避免依赖故障时进程被反复重启
+func (h *HealthHandler) Live(c *gin.Context) {
+	ginx.Success(c, gin.H{"status": dto.HealthStatusOK})
+}
+
+// Ready 就绪检查，数据库、Redis 或权限策略不可用以及服务停止期间返回 503，负载均衡据此摘除实例
+func (h *HealthHandler) Ready(c *gin.Context) {
+	resp := h.svc.Health().Ready(c)
+	if resp.Status != dto.HealthStatusOK {
+		c.JSON(http.StatusServiceUnavailable, ginx.Response{
+			Code:      http.StatusServiceUnavailable,
+			Message:   "服务未就绪",
+			Data:      resp,
+			RequestID: ginx.RequestID(c),
+		})
+		return
+	}
+	ginx.Success(c, resp)
+}
+
+// Version 构建版本信息
+func (h *HealthHandler) Version(c *gin.Context) {
+	ginx.Success(c, version.Get())
+}
@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wxlbd/gin-casbin-admin/internal/dto"
+	cerrors "github.com/wxlbd/gin-casbin-admin/pkg/errors"
+	"github.com/wxlbd/gin-casbin-admin/pkg/ginx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/jwtx"
+)
+
+type OAuthHandler struct {
+	svc Service
+}
+
+func NewOAuthHandler(svc Service) *OAuthHandler {
+	return &OAuthHandler{
+		svc: svc,
+	}
+}
+
+// Authorize 获取授权确认信息
+// @Summary 获取授权确认信息
+// @Description 第三方应用将用户重定向到前端授权页，前端携带登录令牌调用该接口校验授权请求并展示应用名称和申请的权限范围
+// @Tags OAuth2授权
+// @Produce json
+// @Param data query dto.OAuthAuthorizeRequest true "授权请求"
+// @Success 200 {object} ginx.Response{data=dto.OAuthAuthorizeResponse} "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Security Bearer
+// @Router /oauth/authorize [get]
+func (h *OAuthHandler) Authorize(c *gin.Context) {
+	var req dto.OAuthAuthorizeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+	resp, err := h.svc.OAuth().Authorize(c, c.GetUint64("user_id"), &req)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	ginx.Success(c, resp)
+}
+
+// Approve 确认授权
+// @Summary 确认或拒绝授权
+// @Description 用户确认后签发授权码，返回前端需要跳转的客户端回调地址
+// @Tags OAuth2授权
+// @Accept json
+// @Produce json
+// @Param data body dto.OAuthApproveRequest true "授权确认"
+// @Success 200 {object} ginx.Response{data=dto.OAuthApproveResponse} "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 403 {object} ginx.Response "模拟登录期间不能授权"
+// @Security Bearer
+// @Router /oauth/authorize [post]
+func (h *OAuthHandler) Approve(c *gin.Context) {
+	var req dto.OAuthApproveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+	// 模拟登录期间不能代替被模拟用户授权第三方应用
+	if _, ok := c.Get("impersonator_id"); ok {
+		ginx.ServerError(c, cerrors.WithMsg(cerrors.Forbidden, "模拟登录期间不能授权第三方应用"))
+		return
+	}
+	redirectURL, err := h.svc.OAuth().Approve(c, c.GetUint64("user_id"), &req)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	ginx.Success(c, &dto.OAuthApproveResponse{RedirectURL: redirectURL})
+}
+
+// Token 令牌端点
+// @Summary 获取访问令牌
+// @Description 支持 authorization_code 和 client_credentials 授权类型，客户端凭证可通过 HTTP Basic 认证或表单传递，响应格式遵循 RFC 6749
+// @Tags OAuth2授权
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param data formData dto.OAuthTokenRequest true "令牌请求"
+// @Success 200 {object} dto.OAuthTokenResponse "成功"
+// @Failure 400 {object} dto.OAuthError "请求错误"
+// @Failure 401 {object} dto.OAuthError "客户端认证失败"
+// @Router /oauth/token [post]
+func (h *OAuthHandler) Token(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	var req dto.OAuthTokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &dto.OAuthError{Code: "invalid_request", Description: err.Error()})
+		return
+	}
+	if id, secret, ok := c.Request.BasicAuth(); ok {
+		req.ClientID, req.ClientSecret = id, secret
+	}
+
+	resp, err := h.svc.OAuth().Token(c, &req)
+	if err != nil {
+		var oauthErr *dto.OAuthError
+		if !errors.As(err, &oauthErr) {
+			oauthErr = &dto.OAuthError{Code: "server_error", Status: http.StatusInternalServerError}
+		}
+		if oauthErr.Code == "invalid_client" {
+			c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+		}
+		c.JSON(oauthErr.Status, oauthErr)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// UserInfo 获取令牌对应的用户信息
+// @Summary 获取用户信息
+// @Description 使用签发给第三方应用的访问令牌获取用户信息和授权范围
+// @Tags OAuth2授权
+// @Produce json
+// @Success 200 {object} dto.OAuthUserInfoResponse "成功"
+// @Failure 401 {object} dto.OAuthError "令牌无效"
+// @Security Bearer
+// @Router /oauth/userinfo [get]
+func (h *OAuthHandler) UserInfo(c *gin.Context) {
+	claims := c.MustGet("oauth_claims").(*jwtx.Claims)
+	resp, err := h.svc.OAuth().UserInfo(c, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &dto.OAuthError{Code: "server_error"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateClient 创建应用
+// @Summary 创建应用
+// @Description 注册接入单点登录的第三方应用，客户端密钥仅在创建时返回一次
+// @Tags 应用管理
+// @Accept json
+// @Produce json
+// @Param data body dto.OAuthClientRequest true "应用信息"
+// @Success 200 {object} ginx.Response{data=dto.OAuthClientSecretResponse} "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Security Bearer
+// @Router /system/oauth-client [post]
+func (h *OAuthHandler) CreateClient(c *gin.Context) {
+	var req dto.OAuthClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+	resp, err := h.svc.OAuth().CreateClient(c, &req)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	ginx.Success(c, resp)
+}
+
+// UpdateClient 更新应用
+// @Summary 更新应用
+// @Tags 应用管理
+// @Accept json
+// @Produce json
+// @Param id path int true "应用ID"
+// @Param data body dto.OAuthClientRequest true "应用信息"
+// @Success 200 {object} ginx.Response "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Security Bearer
+// @Router /system/oauth-client/{id} [put]
+func (h *OAuthHandler) UpdateClient(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		ginx.Error(c, 400, "参数错误")
+		return
+	}
+	var req dto.OAuthClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+	if err := h.svc.OAuth().UpdateClient(c, id, &req); err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	ginx.Success(c, nil)
+}
+
+// DeleteClient 删除应用
+// @Summary 删除应用
+// @Description 删除应用及用户对其的授权记录
+// @Tags 应用管理
+// @Produce json
+// @Param ids path string true "应用ID列表(多个用逗号分隔)"
+// @Success 200 {object} ginx.Response "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Security Bearer
+// @Router /system/oauth-client/{ids} [delete]
+func (h *OAuthHandler) DeleteClient(c *gin.Context) {
+	var ids []uint64
+	for _, s := range strings.Split(c.Param("ids"), ",") {
+		id, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			ginx.Error(c, 400, "参数错误")
+			return
+		}
+		ids = append(ids, id)
+	}
+	if err := h.svc.OAuth().DeleteClients(c, ids...); err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	ginx.Success(c, nil)
+}
+
+// GetClient 获取应用详情
+// @Summary 获取应用详情
+// @Tags 应用管理
+// @Produce json
+// @Param id path int true "应用ID"
+// @Success 200 {object} ginx.Response{data=dto.OAuthClientResponse} "成功"
+// @Failure 404 {object} ginx.Response "应用不存在"
+// @Security Bearer
+// @Router /system/oauth-client/{id} [get]
+func (h *OAuthHandler) GetClient(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		ginx.Error(c, 400, "参数错误")
+		return
+	}
+	client, err := h.svc.OAuth().GetClient(c, id)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	ginx.Success(c, dto.ToOAuthClientResponse(client))
+}
+
+// ListClient 获取应用列表
+// @Summary 获取应用列表
+// @Tags 应用管理
+// @Produce json
+// @Param data query dto.OAuthClientListRequest true "查询参数"
+// @Success 200 {object} ginx.Response{data=dto.OAuthClientListResponse} "成功"
+// @Security Bearer
+// @Router /system/oauth-client [get]
+func (h *OAuthHandler) ListClient(c *gin.Context) {
+	var req dto.OAuthClientListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+	clients, total, err := h.svc.OAuth().ListClients(c, req.ToModel())
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	ginx.Success(c, dto.ToOAuthClientListResponse(clients, total))
+}
+
+// ResetClientSecret 重置应用密钥
+// @Summary 重置应用密钥
+// @Description 重新生成客户端密钥，旧密钥立即失效，新密钥仅返回一次
+// @Tags 应用管理
+// @Produce json
+// @Param id path int true "应用ID"
+// @Success 200 {object} ginx.Response{data=dto.OAuthClientSecretResponse} "成功"
+// @Security Bearer
+// @Router /system/oauth-client/{id}/secret [put]
+func (h *OAuthHandler) ResetClientSecret(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		ginx.Error(c, 400, "参数错误")
+		return
+	}
+	resp, err := h.svc.OAuth().ResetClientSecret(c, id)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	ginx.Success(c, resp)
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wxlbd/gin-casbin-admin/internal/dto"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
+	"github.com/wxlbd/gin-casbin-admin/pkg/ginx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"github.com/wxlbd/gin-casbin-admin/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// oidcStateCookie 保存发起登录时的 state，回调时必须与查询参数一致，
+// 防止攻击者把自己的登录结果注入到受害者的浏览器
+const oidcStateCookie = "oidc_state"
+
+// 重定向到前端时 URL 片段中的错误码，详细原因只记录在服务端日志
+const (
+	oidcErrAccessDenied = "access_denied"
+	oidcErrInvalidState = "invalid_state"
+	oidcErrLoginFailed  = "login_failed"
+)
+
+type OIDCHandler struct {
+	svc    Service
+	cfg    *config.Config
+	logger *log.Logger
+}
+
+func NewOIDCHandler(svc Service, cfg *config.Config, logger *log.Logger) *OIDCHandler {
+	return &OIDCHandler{
+		svc:    svc,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Login 发起单点登录
+// @Summary 发起单点登录
+// @Description 生成授权码 + PKCE 登录请求并重定向到身份提供方，state 同时写入 HttpOnly Cookie 用于回调时校验
+// @Tags 认证管理
+// @Success 302 "重定向到身份提供方"
+// @Failure 200 {object} ginx.Response "未启用单点登录或身份提供方不可用"
+// @Router /auth/oidc/login [get]
+func (h *OIDCHandler) Login(c *gin.Context) {
+	authURL, state, err := h.svc.OIDC().AuthURL(c)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	h.setStateCookie(c, state, 0)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback 单点登录回调
+// @Summary 单点登录回调
+// @Description 身份提供方授权完成后回调，校验 ID Token 并签发系统令牌。配置了 success_redirect 时令牌以 URL 片段形式重定向到前端，失败时片段中的 error 为 access_denied、invalid_state 或 login_failed；否则直接返回 JSON
+// @Tags 认证管理
+// @Produce json
+// @Param code query string true "授权码"
+// @Param state query string true "登录状态"
+// @Success 200 {object} ginx.Response{data=dto.LoginResponse} "成功"
+// @Success 302 "重定向到前端"
+// @Failure 401 {object} ginx.Response "认证失败"
+// @Router /auth/oidc/callback [get]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	var (
+		accessToken, refreshToken string
+		err                       error
+	)
+	code := oidcErrLoginFailed
+	state := c.Query("state")
+	cookie, _ := c.Cookie(oidcStateCookie)
+	// state 只能使用一次，校验前即清除
+	h.setStateCookie(c, "", -1)
+	if msg := c.Query("error"); msg != "" {
+		code = oidcErrAccessDenied
+		err = errors.WithMsg(errors.Unauthorized, "身份提供方拒绝授权: "+msg)
+	} else if cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(state)) != 1 {
+		code = oidcErrInvalidState
+		err = errors.WithMsg(errors.TokenInvalid, "登录状态与当前浏览器不匹配")
+	} else {
+		accessToken, refreshToken, err = h.svc.OIDC().Callback(c, c.Query("code"), state)
+	}
+	metrics.RecordLogin("oidc", err)
+	if err != nil {
+		h.logger.WithContext(c).Warn("单点登录回调失败", zap.String("code", code), zap.Error(err))
+	}
+	expires := time.Now().Add(h.svc.User().AccessExpire()).Format("2006/01/02 15:04:05")
+
+	redirect := h.cfg.OIDC.SuccessRedirect
+	if redirect == "" {
+		if err != nil {
+			ginx.ServerError(c, err)
+			return
+		}
+		ginx.Success(c, &dto.LoginResponse{
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+			Expires:      expires,
+		})
+		return
+	}
+
+	// 令牌放在 URL 片段中，不会被浏览器发送到服务器或记录在访问日志
+	fragment := url.Values{}
+	if err != nil {
+		fragment.Set("error", code)
+	} else {
+		fragment.Set("access_token", accessToken)
+		fragment.Set("refresh_token", refreshToken)
+		fragment.Set("expires", expires)
+	}
+	c.Redirect(http.StatusFound, redirect+"#"+fragment.Encode())
+}
+
+// setStateCookie 写入或清除（maxAge < 0）state Cookie。身份提供方回调是跨站的顶层 GET 跳转，
+// 因此使用 SameSite=Lax，Path 限定为回调接口所在路径
+func (h *OIDCHandler) setStateCookie(c *gin.Context, state string, maxAge int) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     path.Dir(c.Request.URL.Path),
+		MaxAge:   maxAge,
+		Secure:   c.Request.TLS != nil || strings.HasPrefix(h.cfg.OIDC.RedirectURL, "https://"),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
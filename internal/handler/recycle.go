@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
+	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
+	"github.com/wxlbd/gin-casbin-admin/pkg/ginx"
+)
+
+// listDeleted 分页查询回收站，toResp 将模型转换为响应对象
+func listDeleted[M, R any](c *gin.Context, list func(context.Context, *types.PageParam) ([]M, int64, error), toResp func(M) R) {
+	var page types.PageParam
+	if err := c.ShouldBindQuery(&page); err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+	page.Normalize()
+	items, total, err := list(c, &page)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	resp := make([]R, 0, len(items))
+	for _, item := range items {
+		resp = append(resp, toResp(item))
+	}
+	ginx.Success(c, ginx.ListData{
+		List:  resp,
+		Total: total,
+	})
+}
+
+// recycleAction 解析路径中逗号分隔的 ids 并执行恢复或彻底删除
+func recycleAction[T int64 | uint64](c *gin.Context, action func(context.Context, ...T) error) {
+	ids, err := parseIDs[T](c.Param("ids"))
+	if err != nil {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "无效的ID"))
+		return
+	}
+	if err := action(c, ids...); err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	ginx.Success(c, nil)
+}
+
+func parseIDs[T int64 | uint64](s string) ([]T, error) {
+	parts := strings.Split(s, ",")
+	ids := make([]T, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 63)
+		if err != nil || id == 0 {
+			return nil, strconv.ErrSyntax
+		}
+		ids = append(ids, T(id))
+	}
+	return ids, nil
+}
@@ -1,13 +1,16 @@
 package handler
 
 import (
+	"context"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/wxlbd/gin-casbin-admin/internal/dto"
 	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
 	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
+	"github.com/wxlbd/gin-casbin-admin/pkg/exportx"
 	"github.com/wxlbd/gin-casbin-admin/pkg/ginx"
 )
 
@@ -271,3 +274,83 @@ func (h *RoleHandler) GetAllRoles(ctx *gin.Context) {
 	}
 	ginx.Success(ctx, roleList)
 }
+
+// roleExportColumns 角色导出列
+var roleExportColumns = []exportx.Column[*model.Role]{
+	{Key: "id", Headers: map[string]string{"zh": "角色ID", "en": "ID"}, Value: func(r *model.Role) any { return r.ID }},
+	{Key: "name", Headers: map[string]string{"zh": "角色名称", "en": "Name"}, Value: func(r *model.Role) any { return r.Name }},
+	{Key: "code", Headers: map[string]string{"zh": "角色编码", "en": "Code"}, Value: func(r *model.Role) any { return r.Code }},
+	{Key: "status", Headers: map[string]string{"zh": "状态", "en": "Status"}, Value: func(r *model.Role) any { return r.Status }},
+	{Key: "sort", Headers: map[string]string{"zh": "排序", "en": "Sort"}, Value: func(r *model.Role) any { return r.Sort }},
+	{Key: "remark", Headers: map[string]string{"zh": "备注", "en": "Remark"}, Value: func(r *model.Role) any { return r.Remark }},
+	{Key: "created_at", Headers: map[string]string{"zh": "创建时间", "en": "Created At"}, Value: func(r *model.Role) any { return formatTime(r.CreatedAt) }},
+}
+
+// Export 导出角色
+// @Summary 导出角色
+// @Description 按与角色列表相同的筛选条件导出角色，支持 xlsx、csv 和 jsonl 格式，可选择导出列
+// @Tags 角色管理
+// @Produce application/octet-stream
+// @Param data query dto.RoleListRequest true "筛选条件"
+// @Param export query dto.ExportRequest true "导出参数"
+// @Success 200 {file} file "角色表格"
+// @Security Bearer
+// @Router /system/role/export [get]
+func (h *RoleHandler) Export(c *gin.Context) {
+	var req dto.RoleListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+	req.SkipCount = true
+	export(c, "roles", roleExportColumns, func(ctx context.Context, page, size int) ([]*model.Role, error) {
+		req.PageParam = &types.PageParam{Page: page, PageSize: size}
+		roles, _, err := h.svc.Role().List(ctx, &req)
+		return roles, err
+	})
+}
+
+// Recycle 回收站列表
+// @Summary 角色回收站列表
+// @Description 分页查询已删除的角色，最近删除的在前
+// @Tags 角色管理
+// @Produce json
+// @Param pageNum query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Success 200 {object} ginx.Response{data=ginx.ListData{list=[]dto.RoleResponse,total=int64}} "成功"
+// @Failure 500 {object} ginx.Response "服务器内部错误"
+// @Security Bearer
+// @Router /system/role/recycle [get]
+func (h *RoleHandler) Recycle(c *gin.Context) {
+	listDeleted(c, h.svc.Role().ListDeleted, dto.ToRoleResponse)
+}
+
+// Restore 从回收站恢复
+// @Summary 恢复角色
+// @Description 从回收站恢复指定ID的角色
+// @Tags 角色管理
+// @Produce json
+// @Param ids path string true "角色ID列表(多个用逗号分隔)"
+// @Success 200 {object} ginx.Response "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 404 {object} ginx.Response "回收站中不存在"
+// @Security Bearer
+// @Router /system/role/recycle/{ids} [put]
+func (h *RoleHandler) Restore(c *gin.Context) {
+	recycleAction(c, h.svc.Role().Restore)
+}
+
+// Purge 彻底删除
+// @Summary 彻底删除角色
+// @Description 彻底删除回收站中指定ID的角色，删除后无法恢复
+// @Tags 角色管理
+// @Produce json
+// @Param ids path string true "角色ID列表(多个用逗号分隔)"
+// @Success 200 {object} ginx.Response "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 404 {object} ginx.Response "回收站中不存在"
+// @Security Bearer
+// @Router /system/role/recycle/{ids} [delete]
+func (h *RoleHandler) Purge(c *gin.Context) {
+	recycleAction(c, h.svc.Role().Purge)
+}
@@ -4,6 +4,11 @@ import (
 	"context"
 	"github.com/wxlbd/gin-casbin-admin/internal/dto"
 	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
+	"github.com/wxlbd/gin-casbin-admin/pkg/jwtx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/sheetx"
+	"io"
+	"time"
 )
 
 type DictService interface {
@@ -20,7 +25,27 @@ type DictService interface {
 	DeleteDictData(ctx context.Context, ids ...int64) error
 	GetDictData(ctx context.Context, id int64) (*model.DictDatum, error)
 	ListDictData(ctx context.Context, query *model.DictDataQuery) ([]*model.DictDatum, int64, error)
+	// GetDictDataByType 获取字典类型下启用的字典数据，按排序值升序，结果带缓存
 	GetDictDataByType(ctx context.Context, typeCode string) ([]*model.DictDatum, error)
+	// GetDictDataByTypes 批量获取多个字典类型下启用的字典数据，键为类型编码
+	GetDictDataByTypes(ctx context.Context, typeCodes []string) (map[string][]*model.DictDatum, error)
+	// ExportBundle 导出字典类型及其全部数据
+	ExportBundle(ctx context.Context, codes []string) (*dto.DictBundle, error)
+	// ImportBundle 导入字典包，dryRun 为 true 时只返回变更预览
+	ImportBundle(ctx context.Context, bundle *dto.DictBundle, dryRun bool) (*dto.DictBundleImportResponse, error)
+
+	// ListDeletedDictType 分页查询回收站中的字典类型
+	ListDeletedDictType(ctx context.Context, page *types.PageParam) ([]*model.DictType, int64, error)
+	// RestoreDictType 从回收站恢复字典类型
+	RestoreDictType(ctx context.Context, ids ...int64) error
+	// PurgeDictType 彻底删除回收站中的字典类型
+	PurgeDictType(ctx context.Context, ids ...int64) error
+	// ListDeletedDictData 分页查询回收站中的字典数据
+	ListDeletedDictData(ctx context.Context, page *types.PageParam) ([]*model.DictDatum, int64, error)
+	// RestoreDictData 从回收站恢复字典数据
+	RestoreDictData(ctx context.Context, ids ...int64) error
+	// PurgeDictData 彻底删除回收站中的字典数据
+	PurgeDictData(ctx context.Context, ids ...int64) error
 }
 
 type RoleService interface {
@@ -33,6 +58,14 @@ type RoleService interface {
 	// GetAllRoles 获取所有角色
 	GetAllRoles(ctx context.Context) ([]*model.Role, error)
 	GetRoleMenus(c context.Context, id uint64) ([]*model.SysMenu, error)
+	// ListDeleted 分页查询回收站中的角色
+	ListDeleted(ctx context.Context, page *types.PageParam) ([]*model.Role, int64, error)
+	// Restore 从回收站恢复角色
+	Restore(ctx context.Context, ids ...uint64) error
+	// Purge 彻底删除回收站中的角色
+	Purge(ctx context.Context, ids ...uint64) error
+	// PermissionCode 接口对应的按钮权限标识，route 为路由模板，没有对应的按钮菜单时返回空字符串
+	PermissionCode(ctx context.Context, method, route string) string
 }
 
 type UserService interface {
@@ -42,18 +75,84 @@ type UserService interface {
 	FindByID(ctx context.Context, id uint64) (*model.User, error)
 	FindByUsername(ctx context.Context, username string) (*model.User, error)
 	List(ctx context.Context, query *model.UserQuery) ([]*model.User, int64, error)
-	UpdatePassword(ctx context.Context, id uint64, oldPassword, newPassword string) error
+	// UpdatePassword 修改密码，校验旧密码后吊销该用户其他会话，并为当前会话签发新令牌
+	UpdatePassword(ctx context.Context, id uint64, oldPassword, newPassword string) (accessToken, refreshToken string, err error)
+	// UpdateProfile 修改个人资料
+	UpdateProfile(ctx context.Context, user *model.User) error
+	// UpdateAvatar 修改头像
+	UpdateAvatar(ctx context.Context, id uint64, avatar string) error
+	// GetBackendSetting 获取后台设置，未保存过时返回默认值
+	GetBackendSetting(ctx context.Context, id uint64) (*types.BackendSetting, error)
+	// UpdateBackendSetting 按 JSON 合并补丁部分更新后台设置
+	UpdateBackendSetting(ctx context.Context, id uint64, patch []byte) (*types.BackendSetting, error)
 	AssignRoles(ctx context.Context, userID uint64, roleIds []uint64) error
 	Login(ctx context.Context, username, password string) (accessToken, refreshToken string, err error)
 	RefreshToken(ctx context.Context, refreshToken string) (newAccessToken, newRefreshToken string, err error)
+	// AccessExpire 访问令牌有效期，随配置重新加载更新
+	AccessExpire() time.Duration
 	Logout(ctx context.Context, token string) error
 	GetUserRoles(ctx context.Context, userID uint64) ([]*model.Role, error)
 	ResetPassword(ctx context.Context, id uint64, newPassword string) error
+	// Impersonate 以目标用户身份签发短期模拟令牌
+	Impersonate(ctx context.Context, impersonatorID, userID uint64) (accessToken string, expiresAt time.Time, err error)
+	// ExitImpersonation 结束服务端的模拟记录、作废模拟令牌并为发起模拟的管理员重新签发令牌
+	ExitImpersonation(ctx context.Context, token string) (accessToken, refreshToken string, err error)
+	// RecordOperation 写入操作日志
+	RecordOperation(ctx context.Context, log *model.UserOperationLog) error
+	// GetRoleCodes 批量获取用户的角色编码
+	GetRoleCodes(ctx context.Context, users []*model.User) (map[uint64][]string, error)
+	// Import 从表格批量创建用户，dryRun 时仅校验不写入，存在错误行时生成错误报告
+	Import(ctx context.Context, operatorID uint64, format sheetx.Format, r io.Reader, dryRun bool) (*dto.UserImportResponse, error)
+	// ImportReport 获取导入错误报告，仅导入人可以获取
+	ImportReport(ctx context.Context, operatorID uint64, id string) (sheetx.Format, []byte, error)
+	// ListDeleted 分页查询回收站中的用户
+	ListDeleted(ctx context.Context, page *types.PageParam) ([]*model.User, int64, error)
+	// Restore 从回收站恢复用户
+	Restore(ctx context.Context, ids ...uint64) error
+	// Purge 彻底删除回收站中的用户
+	Purge(ctx context.Context, ids ...uint64) error
+}
+
+type PasswordResetService interface {
+	// Forgot 向邮箱发送密码重置链接，邮箱不存在时同样返回成功
+	Forgot(ctx context.Context, email string) error
+	// Reset 使用一次性重置令牌设置新密码
+	Reset(ctx context.Context, token, newPassword string) error
+}
+
+type OIDCService interface {
+	// AuthURL 生成跳转到身份提供方的授权地址，state 需绑定到发起登录的浏览器
+	AuthURL(ctx context.Context) (authURL, state string, err error)
+	// Callback 完成授权码流程，关联本地用户并签发访问令牌和刷新令牌
+	Callback(ctx context.Context, code, state string) (accessToken, refreshToken string, err error)
+}
+
+type OAuthService interface {
+	// Authorize 校验授权请求，返回授权确认页所需的应用和权限范围信息
+	Authorize(ctx context.Context, userID uint64, req *dto.OAuthAuthorizeRequest) (*dto.OAuthAuthorizeResponse, error)
+	// Approve 用户确认或拒绝授权，返回携带授权码或错误的客户端回调地址
+	Approve(ctx context.Context, userID uint64, req *dto.OAuthApproveRequest) (string, error)
+	// Token 令牌端点，错误类型为 *dto.OAuthError
+	Token(ctx context.Context, req *dto.OAuthTokenRequest) (*dto.OAuthTokenResponse, error)
+	UserInfo(ctx context.Context, claims *jwtx.Claims) (*dto.OAuthUserInfoResponse, error)
+
+	CreateClient(ctx context.Context, req *dto.OAuthClientRequest) (*dto.OAuthClientSecretResponse, error)
+	UpdateClient(ctx context.Context, id uint64, req *dto.OAuthClientRequest) error
+	DeleteClients(ctx context.Context, ids ...uint64) error
+	GetClient(ctx context.Context, id uint64) (*model.OAuthClient, error)
+	ListClients(ctx context.Context, query *model.OAuthClientQuery) ([]*model.OAuthClient, int64, error)
+	// ResetClientSecret 重新生成客户端密钥
+	ResetClientSecret(ctx context.Context, id uint64) (*dto.OAuthClientSecretResponse, error)
 }
 
 type CaptchaService interface {
-	Generate(ctx context.Context) (id, b64s string, err error)
+	// Generate 生成验证码，按验证码策略当前 IP 无需验证码时只返回 Required=false
+	Generate(ctx context.Context, ip string) (*dto.CaptchaResponse, error)
 	Verify(ctx context.Context, id, answer string) bool
+	// Required 判断该 IP 登录是否需要验证码
+	Required(ctx context.Context, ip string) (bool, error)
+	// RecordLoginFailure 记录 IP 的登录失败次数，时间窗口结束后自动清除
+	RecordLoginFailure(ctx context.Context, ip string) error
 }
 
 type SysMenuService interface {
@@ -64,6 +163,17 @@ type SysMenuService interface {
 	GetMenuTree(ctx context.Context) ([]*model.SysMenuTree, error)
 	GetUserMenuTree(ctx context.Context, userID uint64) ([]*model.SysMenuTree, error)
 	GetAllMenus(ctx context.Context) ([]*model.SysMenu, error)
+	// ListDeleted 分页查询回收站中的菜单
+	ListDeleted(ctx context.Context, page *types.PageParam) ([]*model.SysMenu, int64, error)
+	// Restore 从回收站恢复菜单
+	Restore(ctx context.Context, ids ...int64) error
+	// Purge 彻底删除回收站中的菜单
+	Purge(ctx context.Context, ids ...int64) error
+}
+
+type HealthService interface {
+	// Ready 检查服务能否接收流量，返回各依赖的检查结果
+	Ready(ctx context.Context) *dto.ReadinessResponse
 }
 
 type Service interface {
@@ -72,4 +182,8 @@ type Service interface {
 	Dict() DictService
 	Captcha() CaptchaService
 	SysMenu() SysMenuService
+	PasswordReset() PasswordResetService
+	OIDC() OIDCService
+	OAuth() OAuthService
+	Health() HealthService
 }
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"strconv"
 	"strings"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/wxlbd/gin-casbin-admin/internal/dto"
 	"github.com/wxlbd/gin-casbin-admin/internal/model"
 	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
+	"github.com/wxlbd/gin-casbin-admin/pkg/exportx"
 	"github.com/wxlbd/gin-casbin-admin/pkg/ginx"
 )
 
@@ -245,3 +247,92 @@ func (h *SysMenuHandler) GetUserMenuTree(c *gin.Context) {
 	}
 	ginx.Success(c, tree)
 }
+
+// sysMenuExportColumns 菜单导出列
+var sysMenuExportColumns = []exportx.Column[*model.SysMenu]{
+	{Key: "id", Headers: map[string]string{"zh": "菜单ID", "en": "ID"}, Value: func(m *model.SysMenu) any { return m.ID }},
+	{Key: "parent_id", Headers: map[string]string{"zh": "父菜单ID", "en": "Parent ID"}, Value: func(m *model.SysMenu) any { return m.ParentID }},
+	{Key: "menu_type", Headers: map[string]string{"zh": "菜单类型", "en": "Type"}, Value: func(m *model.SysMenu) any { return m.MenuType }},
+	{Key: "title", Headers: map[string]string{"zh": "菜单名称", "en": "Title"}, Value: func(m *model.SysMenu) any { return m.Title }},
+	{Key: "name", Headers: map[string]string{"zh": "路由名称", "en": "Route Name"}, Value: func(m *model.SysMenu) any { return m.Name }},
+	{Key: "path", Headers: map[string]string{"zh": "路由路径", "en": "Path"}, Value: func(m *model.SysMenu) any { return m.Path }},
+	{Key: "component", Headers: map[string]string{"zh": "组件路径", "en": "Component"}, Value: func(m *model.SysMenu) any { return m.Component }},
+	{Key: "auths", Headers: map[string]string{"zh": "权限标识", "en": "Auths"}, Value: func(m *model.SysMenu) any { return m.Auths }},
+	{Key: "rank", Headers: map[string]string{"zh": "排序", "en": "Rank"}, Value: func(m *model.SysMenu) any { return m.Rank }},
+	{Key: "status", Headers: map[string]string{"zh": "状态", "en": "Status"}, Value: func(m *model.SysMenu) any { return m.Status }},
+	{Key: "created_at", Headers: map[string]string{"zh": "创建时间", "en": "Created At"}, Value: func(m *model.SysMenu) any { return formatTime(m.CreatedAt) }},
+}
+
+// Export 导出菜单
+// @Summary 导出菜单
+// @Description 按与菜单列表相同的筛选条件导出菜单，支持 xlsx、csv 和 jsonl 格式，可选择导出列
+// @Tags 菜单管理
+// @Produce application/octet-stream
+// @Param data query dto.SysMenuListRequest true "筛选条件"
+// @Param export query dto.ExportRequest true "导出参数"
+// @Success 200 {file} file "菜单表格"
+// @Security Bearer
+// @Router /system/menu/export [get]
+func (h *SysMenuHandler) Export(c *gin.Context) {
+	var req dto.SysMenuListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+	export(c, "menus", sysMenuExportColumns, func(ctx context.Context, page, size int) ([]*model.SysMenu, error) {
+		list, _, err := h.svc.SysMenu().List(ctx, &model.SysMenuQuery{
+			Title:     req.Title,
+			Status:    req.Status,
+			MenuType:  req.MenuType,
+			Page:      page,
+			PageSize:  size,
+			SkipCount: true,
+		})
+		return list, err
+	})
+}
+
+// Recycle 回收站列表
+// @Summary 菜单回收站列表
+// @Description 分页查询已删除的菜单，最近删除的在前
+// @Tags 系统菜单
+// @Produce json
+// @Param pageNum query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Success 200 {object} ginx.Response{data=ginx.ListData{list=[]dto.SysMenuResponse,total=int64}} "成功"
+// @Failure 500 {object} ginx.Response "服务器内部错误"
+// @Security Bearer
+// @Router /system/menu/recycle [get]
+func (h *SysMenuHandler) Recycle(c *gin.Context) {
+	listDeleted(c, h.svc.SysMenu().ListDeleted, dto.ToSysMenuResponse)
+}
+
+// Restore 从回收站恢复
+// @Summary 恢复菜单
+// @Description 从回收站恢复指定ID的菜单
+// @Tags 系统菜单
+// @Produce json
+// @Param ids path string true "菜单ID列表(多个用逗号分隔)"
+// @Success 200 {object} ginx.Response "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 404 {object} ginx.Response "回收站中不存在"
+// @Security Bearer
+// @Router /system/menu/recycle/{ids} [put]
+func (h *SysMenuHandler) Restore(c *gin.Context) {
+	recycleAction(c, h.svc.SysMenu().Restore)
+}
+
+// Purge 彻底删除
+// @Summary 彻底删除菜单
+// @Description 彻底删除回收站中指定ID的菜单，删除后无法恢复
+// @Tags 系统菜单
+// @Produce json
+// @Param ids path string true "菜单ID列表(多个用逗号分隔)"
+// @Success 200 {object} ginx.Response "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 404 {object} ginx.Response "回收站中不存在"
+// @Security Bearer
+// @Router /system/menu/recycle/{ids} [delete]
+func (h *SysMenuHandler) Purge(c *gin.Context) {
+	recycleAction(c, h.svc.SysMenu().Purge)
+}
@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -11,9 +14,15 @@ import (
 	"github.com/wxlbd/gin-casbin-admin/internal/dto"
 	"github.com/wxlbd/gin-casbin-admin/internal/model"
 	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/exportx"
 	"github.com/wxlbd/gin-casbin-admin/pkg/ginx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/metrics"
+	"github.com/wxlbd/gin-casbin-admin/pkg/sheetx"
 )
 
+// maxImportFileSize 导入文件大小上限
+const maxImportFileSize = 10 << 20
+
 type UserHandler struct {
 	svc Service
 	cfg *config.Config
@@ -45,22 +54,31 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// 验证验证码
-	if !h.svc.Captcha().Verify(c, req.CaptchaId, req.CaptchaCode) {
+	// 按验证码策略验证验证码
+	ip := c.ClientIP()
+	required, err := h.svc.Captcha().Required(c, ip)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	if required && !h.svc.Captcha().Verify(c, req.CaptchaId, req.CaptchaCode) {
 		ginx.Error(c, 400, "验证码错误")
 		return
 	}
 
 	accessToken, refreshToken, err := h.svc.User().Login(c, req.Username, req.Password)
+	metrics.RecordLogin("password", err)
 	if err != nil {
+		_ = h.svc.Captcha().RecordLoginFailure(c, ip)
 		ginx.ServerError(c, err)
 		return
 	}
+	// 登录成功不清除失败次数，否则掌握一个有效账号即可穿插登录绕过验证码
 
 	ginx.Success(c, &dto.LoginResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
-		Expires:      time.Now().Add(h.cfg.JWT.AccessExpire).Format("2006/01/02 15:04:05"),
+		Expires:      time.Now().Add(h.svc.User().AccessExpire()).Format("2006/01/02 15:04:05"),
 	})
 }
 
@@ -92,7 +110,7 @@ func (h *UserHandler) RefreshToken(c *gin.Context) {
 	ginx.Success(c, &dto.LoginResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
-		Expires:      time.Now().Add(h.cfg.JWT.AccessExpire).Format("2006/01/02 15:04:05"),
+		Expires:      time.Now().Add(h.svc.User().AccessExpire()).Format("2006/01/02 15:04:05"),
 	})
 }
 
@@ -114,6 +132,58 @@ func (h *UserHandler) Logout(c *gin.Context) {
 	ginx.Success(c, nil)
 }
 
+// ForgotPassword 找回密码
+// @Summary 找回密码
+// @Description 向账号绑定的邮箱发送密码重置链接，为防止账号枚举，邮箱不存在时同样返回成功
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Param data body dto.ForgotPasswordRequest true "邮箱"
+// @Success 200 {object} ginx.Response "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 500 {object} ginx.Response "服务器内部错误"
+// @Router /auth/password/forgot [post]
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+
+	if err := h.svc.PasswordReset().Forgot(c, req.Email); err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+
+	ginx.Success(c, nil)
+}
+
+// ResetPasswordByToken 通过重置令牌设置新密码
+// @Summary 重置密码
+// @Description 使用邮件中的一次性令牌设置新密码，成功后该用户所有已登录会话失效
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Param data body dto.ResetPasswordByTokenRequest true "重置令牌和新密码"
+// @Success 200 {object} ginx.Response "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 500 {object} ginx.Response "服务器内部错误"
+// @Router /auth/password/reset [post]
+func (h *UserHandler) ResetPasswordByToken(c *gin.Context) {
+	var req dto.ResetPasswordByTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+
+	if err := h.svc.PasswordReset().Reset(c, req.Token, req.Password); err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+
+	ginx.Success(c, nil)
+}
+
 // Create 创建用户
 // @Summary 创建用户
 // @Description 创建一个新的用户
@@ -231,7 +301,6 @@ func (h *UserHandler) List(c *gin.Context) {
 		ginx.ParamError(c, err)
 		return
 	}
-	req.Normalize()
 	users, total, err := h.svc.User().List(c, req.ToModel())
 	if err != nil {
 		ginx.ServerError(c, err)
@@ -326,6 +395,163 @@ func (h *UserHandler) Current(c *gin.Context) {
 	ginx.Success(c, dto.ToUserResponse(user))
 }
 
+// UpdateProfile 修改个人资料
+// @Summary 修改个人资料
+// @Description 当前登录用户修改自己的昵称、手机、邮箱和签名
+// @Tags 个人中心
+// @Accept json
+// @Produce json
+// @Param data body dto.UpdateProfileRequest true "个人资料"
+// @Success 200 {object} ginx.Response "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 500 {object} ginx.Response "服务器内部错误"
+// @Security Bearer
+// @Router /user/profile [put]
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
+	id := c.GetUint64("user_id")
+	if id == 0 {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "无效的用户ID"))
+		return
+	}
+	var req dto.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+
+	if err := h.svc.User().UpdateProfile(c, req.ToModel(id)); err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+
+	ginx.Success(c, nil)
+}
+
+// UpdatePassword 修改个人密码
+// @Summary 修改个人密码
+// @Description 校验旧密码后修改密码，其他会话的令牌将失效，并为当前会话返回新令牌
+// @Tags 个人中心
+// @Accept json
+// @Produce json
+// @Param data body dto.UpdatePasswordRequest true "密码信息"
+// @Success 200 {object} ginx.Response{data=dto.LoginResponse} "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 401 {object} ginx.Response "旧密码错误"
+// @Failure 500 {object} ginx.Response "服务器内部错误"
+// @Security Bearer
+// @Router /user/profile/password [put]
+func (h *UserHandler) UpdatePassword(c *gin.Context) {
+	id := c.GetUint64("user_id")
+	if id == 0 {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "无效的用户ID"))
+		return
+	}
+	var req dto.UpdatePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+
+	accessToken, refreshToken, err := h.svc.User().UpdatePassword(c, id, req.OldPassword, req.NewPassword)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+
+	ginx.Success(c, &dto.LoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		Expires:      time.Now().Add(h.svc.User().AccessExpire()).Format("2006/01/02 15:04:05"),
+	})
+}
+
+// UpdateAvatar 修改头像
+// @Summary 修改头像
+// @Description 当前登录用户修改自己的头像地址
+// @Tags 个人中心
+// @Accept json
+// @Produce json
+// @Param data body dto.UpdateAvatarRequest true "头像地址"
+// @Success 200 {object} ginx.Response "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 500 {object} ginx.Response "服务器内部错误"
+// @Security Bearer
+// @Router /user/profile/avatar [put]
+func (h *UserHandler) UpdateAvatar(c *gin.Context) {
+	id := c.GetUint64("user_id")
+	if id == 0 {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "无效的用户ID"))
+		return
+	}
+	var req dto.UpdateAvatarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+
+	if err := h.svc.User().UpdateAvatar(c, id, req.Avatar); err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+
+	ginx.Success(c, nil)
+}
+
+// GetSettings 获取个人后台设置
+// @Summary 获取个人后台设置
+// @Description 获取当前用户的后台界面设置，未保存过时返回系统默认值
+// @Tags 个人中心
+// @Accept json
+// @Produce json
+// @Success 200 {object} ginx.Response{data=types.BackendSetting} "成功"
+// @Failure 500 {object} ginx.Response "服务器内部错误"
+// @Security Bearer
+// @Router /user/profile/settings [get]
+func (h *UserHandler) GetSettings(c *gin.Context) {
+	id := c.GetUint64("user_id")
+	if id == 0 {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "无效的用户ID"))
+		return
+	}
+	setting, err := h.svc.User().GetBackendSetting(c, id)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	ginx.Success(c, setting)
+}
+
+// UpdateSettings 修改个人后台设置
+// @Summary 修改个人后台设置
+// @Description 以 JSON 合并补丁方式部分更新当前用户的后台界面设置，值为 null 的字段将被重置
+// @Tags 个人中心
+// @Accept json
+// @Produce json
+// @Param data body types.BackendSetting true "需要修改的设置项"
+// @Success 200 {object} ginx.Response{data=types.BackendSetting} "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 500 {object} ginx.Response "服务器内部错误"
+// @Security Bearer
+// @Router /user/profile/settings [put]
+func (h *UserHandler) UpdateSettings(c *gin.Context) {
+	id := c.GetUint64("user_id")
+	if id == 0 {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "无效的用户ID"))
+		return
+	}
+	patch, err := c.GetRawData()
+	if err != nil || len(patch) == 0 {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "设置内容不能为空"))
+		return
+	}
+	setting, err := h.svc.User().UpdateBackendSetting(c, id, patch)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	ginx.Success(c, setting)
+}
+
 // Detail 获取当前用户信息
 func (h *UserHandler) Detail(c *gin.Context) {
 	param := c.Param("id")
@@ -382,3 +608,233 @@ func (h *UserHandler) GerUserRoles(ctx *gin.Context) {
 	}
 	ginx.Success(ctx, roles)
 }
+
+// Impersonate 模拟登录
+// @Summary 模拟登录
+// @Description 以目标用户身份签发短期访问令牌，用于排查用户看到的菜单和权限；模拟期间的请求会在操作日志中标记发起模拟的管理员，令牌不可刷新
+// @Tags 用户管理
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} ginx.Response{data=dto.ImpersonateResponse} "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 403 {object} ginx.Response "没有权限"
+// @Security Bearer
+// @Router /system/user/{id}/impersonate [post]
+func (h *UserHandler) Impersonate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "无效的用户ID"))
+		return
+	}
+	// 模拟期间不允许再次发起模拟
+	if _, ok := c.Get("impersonator_id"); ok {
+		ginx.ServerError(c, errors.WithMsg(errors.Forbidden, "模拟登录期间不能再次模拟"))
+		return
+	}
+
+	accessToken, expiresAt, err := h.svc.User().Impersonate(c, c.GetUint64("user_id"), id)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	user, err := h.svc.User().FindByID(c, id)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+
+	ginx.Success(c, &dto.ImpersonateResponse{
+		AccessToken: accessToken,
+		Expires:     expiresAt.Format("2006/01/02 15:04:05"),
+		UserID:      user.ID,
+		Username:    user.Username,
+	})
+}
+
+// ExitImpersonation 退出模拟登录
+// @Summary 退出模拟登录
+// @Description 校验并清除发起模拟时写入的服务端模拟记录，作废当前模拟令牌，并为发起模拟的管理员重新签发访问令牌和刷新令牌
+// @Tags 用户管理
+// @Produce json
+// @Success 200 {object} ginx.Response{data=dto.LoginResponse} "成功"
+// @Failure 400 {object} ginx.Response "当前未处于模拟登录状态"
+// @Security Bearer
+// @Router /user/impersonate/exit [post]
+func (h *UserHandler) ExitImpersonation(c *gin.Context) {
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	accessToken, refreshToken, err := h.svc.User().ExitImpersonation(c, token)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+
+	ginx.Success(c, &dto.LoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		Expires:      time.Now().Add(h.svc.User().AccessExpire()).Format("2006/01/02 15:04:05"),
+	})
+}
+
+// userExportRow 用户导出行
+type userExportRow struct {
+	*model.User
+	Roles []string
+}
+
+// userExportColumns 用户导出列，表头与导入支持的表头一致
+var userExportColumns = []exportx.Column[*userExportRow]{
+	{Key: "username", Headers: map[string]string{"zh": "用户名", "en": "username"}, Value: func(u *userExportRow) any { return u.Username }},
+	{Key: "nickname", Headers: map[string]string{"zh": "昵称", "en": "nickname"}, Value: func(u *userExportRow) any { return u.Nickname }},
+	{Key: "phone", Headers: map[string]string{"zh": "手机", "en": "phone"}, Value: func(u *userExportRow) any { return u.Phone }},
+	{Key: "email", Headers: map[string]string{"zh": "邮箱", "en": "email"}, Value: func(u *userExportRow) any { return u.Email }},
+	{Key: "status", Headers: map[string]string{"zh": "状态", "en": "status"}, Value: func(u *userExportRow) any { return u.Status }},
+	{Key: "roles", Headers: map[string]string{"zh": "角色", "en": "roles"}, Value: func(u *userExportRow) any { return strings.Join(u.Roles, ",") }},
+	{Key: "remark", Headers: map[string]string{"zh": "备注", "en": "remark"}, Value: func(u *userExportRow) any { return u.Remark }},
+	{Key: "login_time", Headers: map[string]string{"zh": "最后登录时间", "en": "login_time"}, Value: func(u *userExportRow) any { return formatTime(u.LoginTime) }},
+	{Key: "created_at", Headers: map[string]string{"zh": "创建时间", "en": "created_at"}, Value: func(u *userExportRow) any { return formatTime(u.CreatedAt) }},
+}
+
+// Export 导出用户
+// @Summary 导出用户
+// @Description 按与用户列表相同的筛选条件导出用户，支持 xlsx、csv 和 jsonl 格式，可选择导出列
+// @Tags 用户管理
+// @Produce application/octet-stream
+// @Param data query dto.UserExportRequest true "筛选条件"
+// @Param export query dto.ExportRequest true "导出参数"
+// @Success 200 {file} file "用户表格"
+// @Security Bearer
+// @Router /system/user/export [get]
+func (h *UserHandler) Export(c *gin.Context) {
+	var req dto.UserExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ginx.ParamError(c, err)
+		return
+	}
+	query := req.ToModel()
+	query.SkipCount = true
+	export(c, "users", userExportColumns, func(ctx context.Context, page, size int) ([]*userExportRow, error) {
+		query.Page, query.PageSize = page, size
+		users, _, err := h.svc.User().List(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		roles, err := h.svc.User().GetRoleCodes(ctx, users)
+		if err != nil {
+			return nil, err
+		}
+		rows := make([]*userExportRow, 0, len(users))
+		for _, u := range users {
+			rows = append(rows, &userExportRow{User: u, Roles: roles[u.ID]})
+		}
+		return rows, nil
+	})
+}
+
+// Import 导入用户
+// @Summary 导入用户
+// @Description 上传 xlsx 或 csv 文件批量创建用户，表头支持：用户名、密码、昵称、手机、邮箱、状态、角色（角色编码，多个以逗号分隔，需要分配角色权限，超级管理员角色只能由超级管理员分配）、备注，单次最多 500 行。校验失败的行不会导入，可通过返回的 report_id 下载错误报告
+// @Tags 用户管理
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "用户表格"
+// @Param dry_run query bool false "仅校验不导入"
+// @Success 200 {object} ginx.Response{data=dto.UserImportResponse} "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Security Bearer
+// @Router /system/user/import [post]
+func (h *UserHandler) Import(c *gin.Context) {
+	fh, err := c.FormFile("file")
+	if err != nil {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "请上传文件"))
+		return
+	}
+	if fh.Size > maxImportFileSize {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "文件不能超过10MB"))
+		return
+	}
+	format, err := sheetx.FormatFromFilename(fh.Filename)
+	if err != nil {
+		ginx.ParamError(c, errors.WithMsg(errors.InvalidParam, "仅支持 xlsx 和 csv 文件"))
+		return
+	}
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	f, err := fh.Open()
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	defer f.Close()
+
+	resp, err := h.svc.User().Import(c, c.GetUint64("user_id"), format, f, dryRun)
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	ginx.Success(c, resp)
+}
+
+// ImportReport 下载导入错误报告
+// @Summary 下载导入错误报告
+// @Description 下载导入失败的行及错误原因，报告保留 30 分钟且仅导入人可以下载
+// @Tags 用户管理
+// @Produce application/octet-stream
+// @Param id path string true "报告ID"
+// @Success 200 {file} file "错误报告"
+// @Failure 404 {object} ginx.Response "报告不存在或已过期"
+// @Security Bearer
+// @Router /system/user/import/report/{id} [get]
+func (h *UserHandler) ImportReport(c *gin.Context) {
+	format, data, err := h.svc.User().ImportReport(c, c.GetUint64("user_id"), c.Param("id"))
+	if err != nil {
+		ginx.ServerError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="user-import-report.%s"`, format))
+	c.Data(http.StatusOK, format.ContentType(), data)
+}
+
+// Recycle 回收站列表
+// @Summary 用户回收站列表
+// @Description 分页查询已删除的用户，最近删除的在前
+// @Tags 用户管理
+// @Produce json
+// @Param pageNum query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Success 200 {object} ginx.Response{data=ginx.ListData{list=[]dto.UserResponse,total=int64}} "成功"
+// @Failure 500 {object} ginx.Response "服务器内部错误"
+// @Security Bearer
+// @Router /system/user/recycle [get]
+func (h *UserHandler) Recycle(c *gin.Context) {
+	listDeleted(c, h.svc.User().ListDeleted, dto.ToUserResponse)
+}
+
+// Restore 从回收站恢复
+// @Summary 恢复用户
+// @Description 从回收站恢复指定ID的用户
+// @Tags 用户管理
+// @Produce json
+// @Param ids path string true "用户ID列表(多个用逗号分隔)"
+// @Success 200 {object} ginx.Response "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 404 {object} ginx.Response "回收站中不存在"
+// @Security Bearer
+// @Router /system/user/recycle/{ids} [put]
+func (h *UserHandler) Restore(c *gin.Context) {
+	recycleAction(c, h.svc.User().Restore)
+}
+
+// Purge 彻底删除
+// @Summary 彻底删除用户
+// @Description 彻底删除回收站中指定ID的用户，删除后无法恢复
+// @Tags 用户管理
+// @Produce json
+// @Param ids path string true "用户ID列表(多个用逗号分隔)"
+// @Success 200 {object} ginx.Response "成功"
+// @Failure 400 {object} ginx.Response "请求参数错误"
+// @Failure 404 {object} ginx.Response "回收站中不存在"
+// @Security Bearer
+// @Router /system/user/recycle/{ids} [delete]
+func (h *UserHandler) Purge(c *gin.Context) {
+	recycleAction(c, h.svc.User().Purge)
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"net/netip"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultAllowCIDRs 未配置网段时允许的本机和内网地址
+var defaultAllowCIDRs = []string{"127.0.0.0/8", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "::1/128", "fc00::/7"}
+
+// AllowCIDRs 只允许来源地址在指定网段内的请求，其余返回 403，cidrs 为空时只允许本机和内网地址。
+// 来源地址取自连接而非 X-Forwarded-For，避免伪造请求头绕过限制。网段在配置校验时已检查，无法解析的忽略
+func AllowCIDRs(cidrs []string) gin.HandlerFunc {
+	if len(cidrs) == 0 {
+		cidrs = defaultAllowCIDRs
+	}
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if prefix, err := netip.ParsePrefix(cidr); err == nil {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return func(c *gin.Context) {
+		if addr, err := netip.ParseAddr(c.RemoteIP()); err == nil {
+			addr = addr.Unmap()
+			for _, prefix := range prefixes {
+				if prefix.Contains(addr) {
+					c.Next()
+					return
+				}
+			}
+		}
+		c.AbortWithStatus(http.StatusForbidden)
+	}
+}
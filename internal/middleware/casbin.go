@@ -8,11 +8,15 @@ import (
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"github.com/wxlbd/gin-casbin-admin/pkg/metrics"
 	"go.uber.org/zap"
 )
 
 func CasbinMiddleware(enforcer *casbin.Enforcer, log *log.Logger, svc handler.Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		end := traceStage(c, "middleware.Casbin")
+		defer end(nil)
+
 		// 获取当前登录用户
 		userID := c.GetUint64("user_id")
 		if userID == 0 {
@@ -27,6 +31,7 @@ func CasbinMiddleware(enforcer *casbin.Enforcer, log *log.Logger, svc handler.Se
 		// 获取用户的角色列表
 		roles, err := svc.User().GetUserRoles(c, userID)
 		if err != nil {
+			end(err)
 			log.WithContext(c).Error("获取用户角色失败", zap.Error(err))
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"code":    500,
@@ -38,6 +43,9 @@ func CasbinMiddleware(enforcer *casbin.Enforcer, log *log.Logger, svc handler.Se
 
 		// 将角色列表存入上下文
 		c.Set("user_roles", roles)
+		// 接口对应的权限标识，用于请求指标
+		permission := svc.Role().PermissionCode(c, c.Request.Method, c.FullPath())
+		c.Set("permission", permission)
 
 		// 检查是否是超级管理员
 		isAdmin := false
@@ -50,12 +58,15 @@ func CasbinMiddleware(enforcer *casbin.Enforcer, log *log.Logger, svc handler.Se
 
 		// 如果是超级管理员，直接放行
 		if isAdmin {
+			metrics.CasbinDecisions.WithLabelValues("allow", permission).Inc()
+			end(nil)
 			c.Next()
 			return
 		}
 
-		// 获取请求的URI和方法
-		obj := c.Request.URL.Path
+		// 以路由模板校验权限，策略中的路径与路由模板完全一致，
+		// 避免 /user/:id 这样的策略按路径匹配到 /user/export、/user/recycle 等静态路由
+		obj := c.FullPath()
 		act := c.Request.Method
 		// 检查权限
 		// 遍历用户角色，检查是否有权限
@@ -63,6 +74,7 @@ func CasbinMiddleware(enforcer *casbin.Enforcer, log *log.Logger, svc handler.Se
 		for _, role := range roles {
 			ok, err := enforcer.Enforce(role.Code, obj, act)
 			if err != nil {
+				end(err)
 				log.WithContext(c).Error("权限检查失败", zap.Error(err))
 				c.JSON(http.StatusInternalServerError, gin.H{
 					"code":    500,
@@ -78,6 +90,7 @@ func CasbinMiddleware(enforcer *casbin.Enforcer, log *log.Logger, svc handler.Se
 		}
 		// 如果没有任何角色有权限，返回403
 		if !hasPermission {
+			metrics.CasbinDecisions.WithLabelValues("deny", permission).Inc()
 			c.JSON(http.StatusForbidden, gin.H{
 				"code":    403,
 				"message": "没有权限",
@@ -85,6 +98,8 @@ func CasbinMiddleware(enforcer *casbin.Enforcer, log *log.Logger, svc handler.Se
 			c.Abort()
 			return
 		}
+		metrics.CasbinDecisions.WithLabelValues("allow", permission).Inc()
+		end(nil)
 		c.Next()
 	}
 }
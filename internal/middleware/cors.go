@@ -1,17 +1,65 @@
 package middleware
 
 import (
-	"github.com/gin-gonic/gin"
 	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/ginx"
 )
 
-func CORSMiddleware() gin.HandlerFunc {
+// corsOrigins 允许跨域访问的来源集合
+type corsOrigins struct {
+	any     bool
+	origins map[string]bool
+}
+
+func newCORSOrigins(list []string) *corsOrigins {
+	o := &corsOrigins{any: len(list) == 0, origins: make(map[string]bool, len(list))}
+	for _, origin := range list {
+		if origin == "*" {
+			o.any = true
+		}
+		o.origins[normalizeOrigin(origin)] = true
+	}
+	return o
+}
+
+func (o *corsOrigins) allow(origin string) bool {
+	return o.any || o.origins[normalizeOrigin(origin)]
+}
+
+func normalizeOrigin(origin string) string {
+	return strings.ToLower(strings.TrimSuffix(origin, "/"))
+}
+
+// CORSMiddleware 跨域中间件，允许的来源随配置重新加载更新，未配置时允许任意来源
+func CORSMiddleware(cfg *config.Config, watcher *config.Watcher) gin.HandlerFunc {
+	var origins atomic.Pointer[corsOrigins]
+	origins.Store(newCORSOrigins(cfg.CORS.AllowOrigins))
+	watcher.Subscribe(func(_, next *config.Config) {
+		origins.Store(newCORSOrigins(next.CORS.AllowOrigins))
+	})
+
 	return func(c *gin.Context) {
 		method := c.Request.Method
-		c.Header("Access-Control-Allow-Origin", c.GetHeader("Origin"))
-		c.Header("Access-Control-Allow-Credentials", "true")
+		origin := c.GetHeader("Origin")
+		allowed := origin == "" || origins.Load().allow(origin)
+		c.Header("Vary", "Origin")
+		if allowed {
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Credentials", "true")
+			// 允许前端读取续期的令牌和请求ID
+			c.Header("Access-Control-Expose-Headers", "New-Access-Token, "+ginx.RequestIDHeader)
+		}
 
 		if method == "OPTIONS" {
+			if !allowed {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
 			c.Header("Access-Control-Allow-Methods", c.GetHeader("Access-Control-Request-Method"))
 			c.Header("Access-Control-Allow-Headers", c.GetHeader("Access-Control-Request-Headers"))
 			c.Header("Access-Control-Max-Age", "7200")
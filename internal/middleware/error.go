@@ -3,6 +3,7 @@ package middleware
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
+	"github.com/wxlbd/gin-casbin-admin/pkg/ginx"
 )
 
 // ErrorHandler 错误处理中间件
@@ -10,8 +11,8 @@ func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
 
-		// 检查是否有错误
-		if len(c.Errors) > 0 {
+		// 检查是否有错误，响应已开始写出（如流式导出中途失败）时无法再返回错误响应
+		if len(c.Errors) > 0 && !c.Writer.Written() {
 			err := c.Errors.Last().Err
 			var e *errors.Error
 
@@ -27,10 +28,7 @@ func ErrorHandler() gin.HandlerFunc {
 			}
 
 			// 返回错误响应
-			c.JSON(e.Status, gin.H{
-				"code":    e.Code,
-				"message": e.Message,
-			})
+			ginx.Error(c, e.Code, e.Message, e.Status)
 			c.Abort()
 		}
 	}
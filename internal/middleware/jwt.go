@@ -3,6 +3,7 @@ package middleware
 import (
 	"strings"
 
+	"github.com/wxlbd/gin-casbin-admin/pkg/gormx"
 	"github.com/wxlbd/gin-casbin-admin/pkg/jwtx"
 
 	"github.com/gin-gonic/gin"
@@ -10,6 +11,9 @@ import (
 
 func JWTAuth(jwt *jwtx.JWT) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		end := traceStage(c, "middleware.JWTAuth")
+		defer end(nil)
+
 		token := c.GetHeader("Authorization")
 		if token == "" {
 			c.JSON(401, gin.H{
@@ -40,6 +44,16 @@ func JWTAuth(jwt *jwtx.JWT) gin.HandlerFunc {
 			return
 		}
 
+		// OAuth2 客户端令牌只能访问开放接口，不能直接调用管理接口
+		if claims.ClientID != "" {
+			c.JSON(401, gin.H{
+				"code":    401,
+				"message": "token已过期或非法token",
+			})
+			c.Abort()
+			return
+		}
+
 		// 检查是否需要续期
 		newToken, needRenew, err := jwt.CheckAndRenewToken(c, token, claims)
 		if err != nil {
@@ -59,6 +73,16 @@ func JWTAuth(jwt *jwtx.JWT) gin.HandlerFunc {
 		// 将用户信息存入上下文
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
+		// 写入请求上下文，数据库回调据此填充 created_by、updated_by；
+		// 模拟登录期间记录实际操作的管理员，而不是被模拟的用户
+		operatorID := claims.UserID
+		if claims.ImpersonatorID != 0 {
+			operatorID = claims.ImpersonatorID
+			c.Set("impersonator_id", claims.ImpersonatorID)
+			c.Set("impersonator_name", claims.ImpersonatorName)
+		}
+		c.Request = c.Request.WithContext(gormx.WithOperator(c.Request.Context(), operatorID))
+		end(nil)
 		c.Next()
 	}
 }
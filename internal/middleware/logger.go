@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"net/http"
 	"strings"
 	"time"
 
@@ -18,10 +19,18 @@ type responseWriter struct {
 }
 
 func (w responseWriter) Write(b []byte) (int, error) {
-	w.body.Write(b)
+	// 文件下载不记录响应体，避免流式导出的数据堆积在内存中
+	if !strings.HasPrefix(w.Header().Get("Content-Disposition"), "attachment") {
+		w.body.Write(b)
+	}
 	return w.ResponseWriter.Write(b)
 }
 
+// Unwrap 返回被包装的 ResponseWriter，http.ResponseController 据此找到底层连接设置写超时
+func (w responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 // RequestLogger 请求日志中间件
 func RequestLogger(logger *log.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -75,6 +84,14 @@ func RequestLogger(logger *log.Logger) gin.HandlerFunc {
 		if username != nil {
 			fields = append(fields, zap.Any("username", username))
 		}
+		// 模拟登录期间的请求标记发起模拟的管理员
+		if impersonatorID, ok := c.Get("impersonator_id"); ok {
+			fields = append(fields,
+				zap.Bool("impersonated", true),
+				zap.Any("impersonator_id", impersonatorID),
+				zap.String("impersonator_name", c.GetString("impersonator_name")),
+			)
+		}
 
 		// 添加请求体（如果不是GET请求）
 		if c.Request.Method != "GET" && len(requestBody) > 0 {
@@ -102,9 +119,9 @@ func RequestLogger(logger *log.Logger) gin.HandlerFunc {
 		}
 		// 记录日志
 		if c.Writer.Status() >= 500 {
-			logger.Error("Request failed", fields...)
+			logger.WithContext(c).Error("Request failed", fields...)
 		} else {
-			logger.Info("Request completed", fields...)
+			logger.WithContext(c).Info("Request completed", fields...)
 		}
 	}
 }
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wxlbd/gin-casbin-admin/pkg/metrics"
+)
+
+// Metrics 请求指标中间件，按路由模板、状态码和权限中间件写入上下文的权限标识统计请求数和耗时，
+// 未匹配路由的请求统一记为 unmatched、非标准的请求方法统一记为 other，避免客户端构造的请求产生大量时间序列
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		labels := []string{metricMethod(c.Request.Method), route, strconv.Itoa(c.Writer.Status()), c.GetString("permission")}
+		metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+	}
+}
+
+// metricMethod 标准 HTTP 方法原样返回，其他方法返回 other
+func metricMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wxlbd/gin-casbin-admin/pkg/jwtx"
+)
+
+// OAuthTokenAuth 校验签发给 OAuth2 客户端的访问令牌，错误格式遵循 RFC 6750
+func OAuthTokenAuth(jwt *jwtx.JWT) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader("Authorization")
+		if !strings.HasPrefix(token, "Bearer ") {
+			c.Header("WWW-Authenticate", `Bearer realm="oauth"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_request"})
+			return
+		}
+
+		claims, err := jwt.ParseToken(c, token[7:], false)
+		if err != nil || claims.ClientID == "" {
+			c.Header("WWW-Authenticate", `Bearer realm="oauth", error="invalid_token"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+			return
+		}
+
+		c.Set("oauth_claims", claims)
+		c.Next()
+	}
+}
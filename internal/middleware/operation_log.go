@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wxlbd/gin-casbin-admin/internal/handler"
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"go.uber.org/zap"
+)
+
+// OperationLog 将模拟登录期间的每个请求写入操作日志，记录被模拟的用户和发起模拟的管理员。
+// 需要在 JWTAuth 之前注册，请求处理完成后才能取到认证信息和权限标识
+func OperationLog(svc handler.Service, logger *log.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		impersonatorID := c.GetUint64("impersonator_id")
+		if impersonatorID == 0 {
+			return
+		}
+		entry := &model.UserOperationLog{
+			Username:         truncate(c.GetString("username"), 20),
+			Method:           c.Request.Method,
+			Router:           truncate(c.Request.URL.Path, 500),
+			ServiceName:      truncate(c.GetString("permission"), 30),
+			IP:               c.ClientIP(),
+			ImpersonatorID:   &impersonatorID,
+			ImpersonatorName: truncate(c.GetString("impersonator_name"), 20),
+		}
+		if err := svc.User().RecordOperation(c, entry); err != nil {
+			logger.WithContext(c).Error("写入操作日志失败", zap.Error(err))
+		}
+	}
+}
+
+// truncate 按字符截断到数据库列的长度
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
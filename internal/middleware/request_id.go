@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wxlbd/gin-casbin-admin/pkg/ginx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/helper/sid"
+	"github.com/wxlbd/gin-casbin-admin/pkg/helper/uuid"
+	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// requestIDPattern 沿用的请求ID只允许常见字符，避免任意内容写入日志和响应头
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// RequestID 请求ID中间件，沿用请求头 X-Request-ID 中的ID，没有或不合法时生成新的ID。
+// ID 写入响应头、ginx.Response 和当前请求的日志实例，同一请求的 zap 和 GORM 日志均带有 request_id 字段
+func RequestID(logger *log.Logger) gin.HandlerFunc {
+	ids := sid.NewSid()
+	return func(c *gin.Context) {
+		id := c.GetHeader(ginx.RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			var err error
+			if id, err = ids.GenString(); err != nil {
+				id = uuid.GenUUID()
+			}
+		}
+		c.Set(ginx.RequestIDKey, id)
+		c.Header(ginx.RequestIDHeader, id)
+		logger.NewContext(c, zap.String("request_id", id))
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("http.request_id", id))
+		c.Next()
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wxlbd/gin-casbin-admin/pkg/otelx"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing 为每个请求创建服务端 span，按 W3C Trace Context 延续上游链路，
+// 探针、指标和文档接口不记录
+func Tracing(serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName, otelgin.WithFilter(func(r *http.Request) bool {
+		switch r.URL.Path {
+		case "/healthz", "/readyz", "/metrics":
+			return false
+		}
+		return !strings.HasPrefix(r.URL.Path, "/swagger")
+	}))
+}
+
+// traceStage 为中间件的处理阶段创建 span，阶段内通过 c 传递的上下文以该 span 为父 span。
+// 返回的 end 结束 span 并恢复请求上下文，需在 c.Next 之前调用，重复调用无影响
+func traceStage(c *gin.Context, name string) (end func(err error)) {
+	req := c.Request
+	ctx, span := otelx.Start(req.Context(), name)
+	c.Request = req.WithContext(ctx)
+	ended := false
+	return func(err error) {
+		if ended {
+			return
+		}
+		ended = true
+		otelx.End(span, err)
+		// 保留阶段内写入请求上下文的值，只替换回原来的 span
+		c.Request = c.Request.WithContext(trace.ContextWithSpan(c.Request.Context(), trace.SpanFromContext(req.Context())))
+	}
+}
@@ -6,22 +6,26 @@ package model
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 const TableNameDictDatum = "dict_data"
 
 // DictDatum mapped from table <dict_data>
 type DictDatum struct {
-	ID        int64     `gorm:"column:id;primaryKey;autoIncrement:true" json:"id"`
-	TypeCode  string    `gorm:"column:type_code;not null;comment:字典类型编码" json:"type_code"`             // 字典类型编码
-	Label     string    `gorm:"column:label;not null;comment:字典key" json:"label"`                      // 字典key
-	Value     string    `gorm:"column:value;not null;comment:字典值" json:"value"`                        // 字典值
-	Status    int32     `gorm:"column:status;not null;default:1;comment:字典状态:1-正常,2-禁用" json:"status"` // 字典状态:1-正常,2-禁用
-	Sort      int32     `gorm:"column:sort;not null;comment:排序" json:"sort"`                           // 排序
-	Remark    string    `gorm:"column:remark;not null;comment:备注" json:"remark"`                       // 备注
-	CreatedAt time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt time.Time `gorm:"column:updated_at" json:"updated_at"`
-	DeletedAt int32     `gorm:"column:deleted_at" json:"deleted_at"`
+	ID        int64          `gorm:"column:id;primaryKey;autoIncrement:true" json:"id"`
+	TypeCode  string         `gorm:"column:type_code;not null;comment:字典类型编码" json:"type_code"`             // 字典类型编码
+	Label     string         `gorm:"column:label;not null;comment:字典key" json:"label"`                      // 字典key
+	Value     string         `gorm:"column:value;not null;comment:字典值" json:"value"`                        // 字典值
+	Status    int32          `gorm:"column:status;not null;default:1;comment:字典状态:1-正常,2-禁用" json:"status"` // 字典状态:1-正常,2-禁用
+	Sort      int32          `gorm:"column:sort;not null;comment:排序" json:"sort"`                           // 排序
+	Remark    string         `gorm:"column:remark;not null;comment:备注" json:"remark"`                       // 备注
+	CreatedBy int64          `gorm:"column:created_by;not null;comment:创建者" json:"created_by"`              // 创建者
+	UpdatedBy int64          `gorm:"column:updated_by;not null;comment:更新者" json:"updated_by"`              // 更新者
+	CreatedAt time.Time      `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"column:updated_at" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;comment:删除时间" json:"deleted_at"` // 删除时间
 }
 
 // TableName DictDatum's table name
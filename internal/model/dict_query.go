@@ -6,6 +6,8 @@ type DictTypeQuery struct {
 	Status   int32  `form:"status"`
 	Page     int    `form:"page,default=1"`
 	PageSize int    `form:"page_size,default=10"`
+	// SkipCount 不统计总数，用于导出等逐页读取全部数据的场景
+	SkipCount bool `form:"-"`
 }
 
 type DictDataQuery struct {
@@ -14,4 +16,6 @@ type DictDataQuery struct {
 	Status   int32  `form:"status"`
 	Page     int    `form:"pageNum,default=1"`
 	PageSize int    `form:"pageSize,default=10"`
+	// SkipCount 不统计总数，用于导出等逐页读取全部数据的场景
+	SkipCount bool `form:"-"`
 }
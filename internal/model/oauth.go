@@ -0,0 +1,84 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
+)
+
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeClientCredentials = "client_credentials"
+)
+
+// OAuthClient 接入本系统登录的 OAuth2 客户端应用
+type OAuthClient struct {
+	ID       uint64 `json:"id" gorm:"primaryKey"`
+	ClientID string `json:"client_id" gorm:"uniqueIndex;size:64"`
+	// ClientSecret 客户端密钥的 bcrypt 哈希
+	ClientSecret string `json:"-" gorm:"size:128"`
+	Name         string `json:"name" gorm:"size:64"`
+	// RedirectURIs 允许的回调地址，多个以空格分隔
+	RedirectURIs string `json:"redirect_uris" gorm:"size:1024"`
+	// GrantTypes 允许的授权类型，多个以空格分隔
+	GrantTypes string `json:"grant_types" gorm:"size:128"`
+	// Scopes 允许申请的权限范围，即菜单权限标识，多个以空格分隔
+	Scopes string `json:"scopes" gorm:"type:text"`
+	// SkipConsent 受信任的内部应用跳过用户授权确认
+	SkipConsent bool      `json:"skip_consent" gorm:"default:false"`
+	Status      int8      `json:"status" gorm:"default:1"` // 1: 正常, 2: 禁用
+	Remark      string    `json:"remark" gorm:"size:255"`
+	CreatedBy   uint64    `json:"created_by" gorm:"default:0"`
+	UpdatedBy   uint64    `json:"updated_by" gorm:"default:0"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (OAuthClient) TableName() string {
+	return "oauth_client"
+}
+
+// AllowsRedirectURI 回调地址必须与注册的地址完全一致
+func (c *OAuthClient) AllowsRedirectURI(uri string) bool {
+	for _, u := range strings.Fields(c.RedirectURIs) {
+		if u == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsGrantType 是否允许指定的授权类型
+func (c *OAuthClient) AllowsGrantType(grantType string) bool {
+	for _, g := range strings.Fields(c.GrantTypes) {
+		if g == grantType {
+			return true
+		}
+	}
+	return false
+}
+
+// OAuthConsent 用户对客户端应用的授权记录
+type OAuthConsent struct {
+	ID       uint64 `json:"id" gorm:"primaryKey"`
+	UserID   uint64 `json:"user_id" gorm:"uniqueIndex:idx_oauth_consent_user_client"`
+	ClientID string `json:"client_id" gorm:"size:64;uniqueIndex:idx_oauth_consent_user_client"`
+	// Scopes 用户已同意的权限范围，多个以空格分隔
+	Scopes    string    `json:"scopes" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (OAuthConsent) TableName() string {
+	return "oauth_consent"
+}
+
+type OAuthClientQuery struct {
+	*types.PageParam
+	Name     string `json:"name"`
+	ClientID string `json:"client_id"`
+	Status   int8   `json:"status"`
+}
@@ -4,18 +4,22 @@ import (
 	"time"
 
 	"github.com/wxlbd/gin-casbin-admin/internal/types"
+	"gorm.io/gorm"
 )
 
 // Role 角色模型
 type Role struct {
-	ID        uint64    `json:"id" gorm:"primaryKey"`
-	Name      string    `json:"name" gorm:"size:64"`
-	Code      string    `json:"code" gorm:"uniqueIndex;size:64"`
-	Status    int8      `json:"status" gorm:"default:1"` // 1: 正常, 2: 禁用
-	Sort      int16     `json:"sort" gorm:"default:0"`   // 排序，值越小越靠前
-	Remark    string    `json:"remark" gorm:"size:255"`  // 备注
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uint64         `json:"id" gorm:"primaryKey"`
+	Name      string         `json:"name" gorm:"size:64"`
+	Code      string         `json:"code" gorm:"uniqueIndex;size:64"`
+	Status    int8           `json:"status" gorm:"default:1"` // 1: 正常, 2: 禁用
+	Sort      int16          `json:"sort" gorm:"default:0"`   // 排序，值越小越靠前
+	Remark    string         `json:"remark" gorm:"size:255"`  // 备注
+	CreatedBy uint64         `json:"created_by" gorm:"default:0"`
+	UpdatedBy uint64         `json:"updated_by" gorm:"default:0"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at"`
 }
 
 // TableName 指定表名
@@ -28,4 +32,6 @@ type RoleQuery struct {
 	Name   string `json:"name"`
 	Code   string `json:"code"`
 	Status int8   `json:"status"`
+	// SkipCount 不统计总数，用于导出等逐页读取全部数据的场景
+	SkipCount bool `json:"-"`
 }
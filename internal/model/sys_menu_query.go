@@ -6,6 +6,8 @@ type SysMenuQuery struct {
 	MenuType int32  `form:"type"`     // 菜单类型
 	Page     int    `form:"page"`     // 页码
 	PageSize int    `form:"pageSize"` // 每页数量
+	// SkipCount 不统计总数，用于导出等逐页读取全部数据的场景
+	SkipCount bool `form:"-"`
 }
 
 // SysMenuTree 菜单树结构
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/wxlbd/gin-casbin-admin/internal/types"
+	"gorm.io/gorm"
 )
 
 // User 用户模型
@@ -26,6 +27,7 @@ type User struct {
 	CreatedAt      time.Time             `json:"created_at"`
 	UpdatedAt      time.Time             `json:"updated_at"`
 	Remark         string                `json:"remark" gorm:"size:255"`
+	DeletedAt      gorm.DeletedAt        `json:"deleted_at"`
 }
 
 // TableName 指定表名
@@ -42,4 +44,6 @@ type UserQuery struct {
 	Page     int    `json:"page"`
 	PageSize int    `json:"size"`
 	OrderBy  string `json:"order_by"`
+	// SkipCount 不统计总数，用于导出等逐页读取全部数据的场景
+	SkipCount bool `json:"-"`
 }
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// UserIdentity 用户外部身份关联，记录用户在外部身份提供方（如 OIDC、LDAP）中的唯一标识
+type UserIdentity struct {
+	ID     uint64 `json:"id" gorm:"primaryKey"`
+	UserID uint64 `json:"user_id" gorm:"index"`
+	// Provider 身份提供方标识，OIDC 为 issuer，LDAP 为 ldap
+	Provider string `json:"provider" gorm:"size:255;uniqueIndex:idx_user_identity_provider_subject"`
+	// Subject 用户在身份提供方中的唯一标识，OIDC 为 sub，LDAP 为用户 DN
+	Subject   string    `json:"subject" gorm:"size:255;uniqueIndex:idx_user_identity_provider_subject"`
+	Email     string    `json:"email" gorm:"size:128"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (UserIdentity) TableName() string {
+	return "user_identity"
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// UserOperationLog 用户操作日志
+type UserOperationLog struct {
+	ID       uint64 `json:"id" gorm:"primaryKey"`
+	Username string `json:"username" gorm:"size:20"`
+	Method   string `json:"method" gorm:"size:20"`
+	Router   string `json:"router" gorm:"size:500"`
+	// ServiceName 业务名称，取接口对应的权限标识
+	ServiceName string `json:"service_name" gorm:"size:30"`
+	IP          string `json:"ip" gorm:"size:45"`
+	// ImpersonatorID、ImpersonatorName 模拟登录期间的操作记录发起模拟的管理员，Username 为被模拟的用户；非模拟操作为空
+	ImpersonatorID   *uint64   `json:"impersonator_id"`
+	ImpersonatorName string    `json:"impersonator_name" gorm:"size:20"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	Remark           string    `json:"remark" gorm:"size:255"`
+}
+
+// TableName 指定表名
+func (UserOperationLog) TableName() string {
+	return "user_operation_log"
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/wxlbd/gin-casbin-admin/internal/model"
 	"github.com/wxlbd/gin-casbin-admin/internal/service"
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
 )
 
 type dictTypeRepository struct {
@@ -55,6 +57,51 @@ func (r *dictTypeRepository) FindByCode(ctx context.Context, code string) (*mode
 	return first, nil
 }
 
+func (r *dictTypeRepository) FindByIDs(ctx context.Context, ids ...int64) ([]*model.DictType, error) {
+	return r.query.WithContext(ctx).DictType.Where(r.query.DictType.ID.In(ids...)).Find()
+}
+
+func (r *dictTypeRepository) FindByCodes(ctx context.Context, codes []string) ([]*model.DictType, error) {
+	return r.query.WithContext(ctx).DictType.Where(r.query.DictType.Code.In(codes...)).Find()
+}
+
+func (r *dictTypeRepository) FindByIDsForUpdate(ctx context.Context, ids ...int64) ([]*model.DictType, error) {
+	return r.query.WithContext(ctx).DictType.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where(r.query.DictType.ID.In(ids...)).Find()
+}
+
+func (r *dictTypeRepository) FindByCodesForUpdate(ctx context.Context, codes []string) ([]*model.DictType, error) {
+	return r.query.WithContext(ctx).DictType.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where(r.query.DictType.Code.In(codes...)).Find()
+}
+
+func (r *dictTypeRepository) FindByCodesWithDeleted(ctx context.Context, codes []string) ([]*model.DictType, error) {
+	return r.query.WithContext(ctx).DictType.Unscoped().Where(r.query.DictType.Code.In(codes...)).Find()
+}
+
+func (r *dictTypeRepository) ListDeleted(ctx context.Context, page *types.PageParam) ([]*model.DictType, int64, error) {
+	return r.query.WithContext(ctx).DictType.Unscoped().
+		Where(r.query.DictType.DeletedAt.IsNotNull()).
+		Order(r.query.DictType.DeletedAt.Desc()).
+		FindByPage(page.GetOffset(), page.PageSize)
+}
+
+func (r *dictTypeRepository) FindDeletedByIDs(ctx context.Context, ids ...int64) ([]*model.DictType, error) {
+	return r.query.WithContext(ctx).DictType.Unscoped().Where(r.query.DictType.ID.In(ids...), r.query.DictType.DeletedAt.IsNotNull()).Find()
+}
+
+func (r *dictTypeRepository) Restore(ctx context.Context, ids ...int64) error {
+	_, err := r.query.WithContext(ctx).DictType.Unscoped().
+		Where(r.query.DictType.ID.In(ids...), r.query.DictType.DeletedAt.IsNotNull()).
+		Update(r.query.DictType.DeletedAt, nil)
+	return err
+}
+
+func (r *dictTypeRepository) Purge(ctx context.Context, ids ...int64) error {
+	_, err := r.query.WithContext(ctx).DictType.Unscoped().Where(r.query.DictType.ID.In(ids...), r.query.DictType.DeletedAt.IsNotNull()).Delete()
+	return err
+}
+
 func (r *dictTypeRepository) List(ctx context.Context, query *model.DictTypeQuery) ([]*model.DictType, int64, error) {
 	q := r.query.WithContext(ctx).DictType
 	if query.Name != "" {
@@ -67,13 +114,17 @@ func (r *dictTypeRepository) List(ctx context.Context, query *model.DictTypeQuer
 		q = q.Where(r.query.DictType.Status.Eq(query.Status))
 	}
 
-	total, err := q.Count()
-	if err != nil {
-		return nil, 0, err
+	var total int64
+	if !query.SkipCount {
+		var err error
+		if total, err = q.Count(); err != nil {
+			return nil, 0, err
+		}
 	}
 
+	// 排序值可能重复，以 ID 兜底保证翻页时行顺序稳定
 	offset := (query.Page - 1) * query.PageSize
-	list, err := q.Order(r.query.DictType.Sort).Offset(offset).Limit(query.PageSize).Find()
+	list, err := q.Order(r.query.DictType.Sort, r.query.DictType.ID).Offset(offset).Limit(query.PageSize).Find()
 	return list, total, err
 }
 
@@ -100,6 +151,87 @@ func (r *dictDataRepository) FindByTypeCode(ctx context.Context, typeCode string
 	return r.query.WithContext(ctx).DictDatum.Where(r.query.DictDatum.TypeCode.Eq(typeCode)).Order(r.query.DictDatum.Sort).Find()
 }
 
+func (r *dictDataRepository) FindByIDs(ctx context.Context, ids ...int64) ([]*model.DictDatum, error) {
+	return r.query.WithContext(ctx).DictDatum.Where(r.query.DictDatum.ID.In(ids...)).Find()
+}
+
+func (r *dictDataRepository) FindByTypeCodeAndValueWithDeleted(ctx context.Context, typeCode, value string) (*model.DictDatum, error) {
+	first, err := r.query.WithContext(ctx).DictDatum.Unscoped().
+		Where(r.query.DictDatum.TypeCode.Eq(typeCode), r.query.DictDatum.Value.Eq(value)).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return first, nil
+}
+
+func (r *dictDataRepository) CountByTypeCodes(ctx context.Context, typeCodes []string) (int64, error) {
+	return r.query.WithContext(ctx).DictDatum.Where(r.query.DictDatum.TypeCode.In(typeCodes...)).Count()
+}
+
+func (r *dictDataRepository) UpdateTypeCode(ctx context.Context, oldCode, newCode string) error {
+	_, err := r.query.WithContext(ctx).DictDatum.Unscoped().
+		Where(r.query.DictDatum.TypeCode.Eq(oldCode)).
+		Update(r.query.DictDatum.TypeCode, newCode)
+	return err
+}
+
+func (r *dictDataRepository) DeleteByTypeCodes(ctx context.Context, typeCodes []string) error {
+	_, err := r.query.WithContext(ctx).DictDatum.Where(r.query.DictDatum.TypeCode.In(typeCodes...)).Delete()
+	return err
+}
+
+func (r *dictDataRepository) PurgeByTypeCodes(ctx context.Context, typeCodes []string) error {
+	_, err := r.query.WithContext(ctx).DictDatum.Unscoped().
+		Where(r.query.DictDatum.TypeCode.In(typeCodes...), r.query.DictDatum.DeletedAt.IsNotNull()).
+		Delete()
+	return err
+}
+
+func (r *dictDataRepository) FindByTypeCodesWithDeleted(ctx context.Context, typeCodes []string) ([]*model.DictDatum, error) {
+	return r.query.WithContext(ctx).DictDatum.Unscoped().Where(r.query.DictDatum.TypeCode.In(typeCodes...)).Find()
+}
+
+func (r *dictDataRepository) ListDeleted(ctx context.Context, page *types.PageParam) ([]*model.DictDatum, int64, error) {
+	return r.query.WithContext(ctx).DictDatum.Unscoped().
+		Where(r.query.DictDatum.DeletedAt.IsNotNull()).
+		Order(r.query.DictDatum.DeletedAt.Desc()).
+		FindByPage(page.GetOffset(), page.PageSize)
+}
+
+func (r *dictDataRepository) FindDeletedByIDs(ctx context.Context, ids ...int64) ([]*model.DictDatum, error) {
+	return r.query.WithContext(ctx).DictDatum.Unscoped().Where(r.query.DictDatum.ID.In(ids...), r.query.DictDatum.DeletedAt.IsNotNull()).Find()
+}
+
+func (r *dictDataRepository) Restore(ctx context.Context, ids ...int64) error {
+	_, err := r.query.WithContext(ctx).DictDatum.Unscoped().
+		Where(r.query.DictDatum.ID.In(ids...), r.query.DictDatum.DeletedAt.IsNotNull()).
+		Update(r.query.DictDatum.DeletedAt, nil)
+	return err
+}
+
+func (r *dictDataRepository) Purge(ctx context.Context, ids ...int64) error {
+	_, err := r.query.WithContext(ctx).DictDatum.Unscoped().Where(r.query.DictDatum.ID.In(ids...), r.query.DictDatum.DeletedAt.IsNotNull()).Delete()
+	return err
+}
+
+func (r *dictDataRepository) FindByTypeCodes(ctx context.Context, typeCodes []string) ([]*model.DictDatum, error) {
+	return r.query.WithContext(ctx).DictDatum.
+		Where(r.query.DictDatum.TypeCode.In(typeCodes...)).
+		Order(r.query.DictDatum.Sort, r.query.DictDatum.ID).
+		Find()
+}
+
+func (r *dictDataRepository) FindEnabledByTypeCodes(ctx context.Context, typeCodes []string) ([]*model.DictDatum, error) {
+	return r.query.WithContext(ctx).DictDatum.
+		Where(r.query.DictDatum.TypeCode.In(typeCodes...), r.query.DictDatum.Status.Eq(1)).
+		Order(r.query.DictDatum.Sort, r.query.DictDatum.ID).
+		Find()
+}
+
 func (r *dictDataRepository) List(ctx context.Context, query *model.DictDataQuery) ([]*model.DictDatum, int64, error) {
 	q := r.query.WithContext(ctx).DictDatum
 	if query.TypeCode != "" {
@@ -112,12 +244,16 @@ func (r *dictDataRepository) List(ctx context.Context, query *model.DictDataQuer
 		q = q.Where(r.query.DictDatum.Status.Eq(query.Status))
 	}
 
-	total, err := q.Count()
-	if err != nil {
-		return nil, 0, err
+	var total int64
+	if !query.SkipCount {
+		var err error
+		if total, err = q.Count(); err != nil {
+			return nil, 0, err
+		}
 	}
 
+	// 排序值可能重复，以 ID 兜底保证翻页时行顺序稳定
 	offset := (query.Page - 1) * query.PageSize
-	list, err := q.Order(r.query.DictDatum.Sort).Offset(offset).Limit(query.PageSize).Find()
+	list, err := q.Order(r.query.DictDatum.Sort, r.query.DictDatum.ID).Offset(offset).Limit(query.PageSize).Find()
 	return list, total, err
 }
@@ -34,9 +34,11 @@ func newDictDatum(db *gorm.DB, opts ...gen.DOOption) dictDatum {
 	_dictDatum.Status = field.NewInt32(tableName, "status")
 	_dictDatum.Sort = field.NewInt32(tableName, "sort")
 	_dictDatum.Remark = field.NewString(tableName, "remark")
+	_dictDatum.CreatedBy = field.NewInt64(tableName, "created_by")
+	_dictDatum.UpdatedBy = field.NewInt64(tableName, "updated_by")
 	_dictDatum.CreatedAt = field.NewTime(tableName, "created_at")
 	_dictDatum.UpdatedAt = field.NewTime(tableName, "updated_at")
-	_dictDatum.DeletedAt = field.NewInt32(tableName, "deleted_at")
+	_dictDatum.DeletedAt = field.NewField(tableName, "deleted_at")
 
 	_dictDatum.fillFieldMap()
 
@@ -54,9 +56,11 @@ type dictDatum struct {
 	Status    field.Int32
 	Sort      field.Int32
 	Remark    field.String
+	CreatedBy field.Int64
+	UpdatedBy field.Int64
 	CreatedAt field.Time
 	UpdatedAt field.Time
-	DeletedAt field.Int32
+	DeletedAt field.Field
 
 	fieldMap map[string]field.Expr
 }
@@ -80,9 +84,11 @@ func (d *dictDatum) updateTableName(table string) *dictDatum {
 	d.Status = field.NewInt32(table, "status")
 	d.Sort = field.NewInt32(table, "sort")
 	d.Remark = field.NewString(table, "remark")
+	d.CreatedBy = field.NewInt64(table, "created_by")
+	d.UpdatedBy = field.NewInt64(table, "updated_by")
 	d.CreatedAt = field.NewTime(table, "created_at")
 	d.UpdatedAt = field.NewTime(table, "updated_at")
-	d.DeletedAt = field.NewInt32(table, "deleted_at")
+	d.DeletedAt = field.NewField(table, "deleted_at")
 
 	d.fillFieldMap()
 
@@ -99,7 +105,7 @@ func (d *dictDatum) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (d *dictDatum) fillFieldMap() {
-	d.fieldMap = make(map[string]field.Expr, 10)
+	d.fieldMap = make(map[string]field.Expr, 12)
 	d.fieldMap["id"] = d.ID
 	d.fieldMap["type_code"] = d.TypeCode
 	d.fieldMap["label"] = d.Label
@@ -107,6 +113,8 @@ func (d *dictDatum) fillFieldMap() {
 	d.fieldMap["status"] = d.Status
 	d.fieldMap["sort"] = d.Sort
 	d.fieldMap["remark"] = d.Remark
+	d.fieldMap["created_by"] = d.CreatedBy
+	d.fieldMap["updated_by"] = d.UpdatedBy
 	d.fieldMap["created_at"] = d.CreatedAt
 	d.fieldMap["updated_at"] = d.UpdatedAt
 	d.fieldMap["deleted_at"] = d.DeletedAt
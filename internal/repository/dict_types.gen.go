@@ -33,9 +33,11 @@ func newDictType(db *gorm.DB, opts ...gen.DOOption) dictType {
 	_dictType.Status = field.NewInt32(tableName, "status")
 	_dictType.Sort = field.NewInt32(tableName, "sort")
 	_dictType.Remark = field.NewString(tableName, "remark")
+	_dictType.CreatedBy = field.NewInt64(tableName, "created_by")
+	_dictType.UpdatedBy = field.NewInt64(tableName, "updated_by")
 	_dictType.CreatedAt = field.NewTime(tableName, "created_at")
 	_dictType.UpdatedAt = field.NewTime(tableName, "updated_at")
-	_dictType.DeletedAt = field.NewInt32(tableName, "deleted_at")
+	_dictType.DeletedAt = field.NewField(tableName, "deleted_at")
 
 	_dictType.fillFieldMap()
 
@@ -52,9 +54,11 @@ type dictType struct {
 	Status    field.Int32
 	Sort      field.Int32
 	Remark    field.String
+	CreatedBy field.Int64
+	UpdatedBy field.Int64
 	CreatedAt field.Time
 	UpdatedAt field.Time
-	DeletedAt field.Int32
+	DeletedAt field.Field
 
 	fieldMap map[string]field.Expr
 }
@@ -77,9 +81,11 @@ func (d *dictType) updateTableName(table string) *dictType {
 	d.Status = field.NewInt32(table, "status")
 	d.Sort = field.NewInt32(table, "sort")
 	d.Remark = field.NewString(table, "remark")
+	d.CreatedBy = field.NewInt64(table, "created_by")
+	d.UpdatedBy = field.NewInt64(table, "updated_by")
 	d.CreatedAt = field.NewTime(table, "created_at")
 	d.UpdatedAt = field.NewTime(table, "updated_at")
-	d.DeletedAt = field.NewInt32(table, "deleted_at")
+	d.DeletedAt = field.NewField(table, "deleted_at")
 
 	d.fillFieldMap()
 
@@ -96,13 +102,15 @@ func (d *dictType) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (d *dictType) fillFieldMap() {
-	d.fieldMap = make(map[string]field.Expr, 9)
+	d.fieldMap = make(map[string]field.Expr, 11)
 	d.fieldMap["id"] = d.ID
 	d.fieldMap["code"] = d.Code
 	d.fieldMap["name"] = d.Name
 	d.fieldMap["status"] = d.Status
 	d.fieldMap["sort"] = d.Sort
 	d.fieldMap["remark"] = d.Remark
+	d.fieldMap["created_by"] = d.CreatedBy
+	d.fieldMap["updated_by"] = d.UpdatedBy
 	d.fieldMap["created_at"] = d.CreatedAt
 	d.fieldMap["updated_at"] = d.UpdatedAt
 	d.fieldMap["deleted_at"] = d.DeletedAt
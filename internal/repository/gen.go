@@ -16,64 +16,84 @@ import (
 )
 
 var (
-	Q         = new(Query)
-	DictDatum *dictDatum
-	DictType  *dictType
-	Role      *role
-	RoleMenus *roleMenus
-	SysMenu   *sysMenu
-	User      *user
-	UserRoles *userRoles
+	Q                = new(Query)
+	DictDatum        *dictDatum
+	DictType         *dictType
+	OAuthClient      *oAuthClient
+	OAuthConsent     *oAuthConsent
+	Role             *role
+	RoleMenus        *roleMenus
+	SysMenu          *sysMenu
+	User             *user
+	UserIdentity     *userIdentity
+	UserOperationLog *userOperationLog
+	UserRoles        *userRoles
 )
 
 func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
 	*Q = *Use(db, opts...)
 	DictDatum = &Q.DictDatum
 	DictType = &Q.DictType
+	OAuthClient = &Q.OAuthClient
+	OAuthConsent = &Q.OAuthConsent
 	Role = &Q.Role
 	RoleMenus = &Q.RoleMenus
 	SysMenu = &Q.SysMenu
 	User = &Q.User
+	UserIdentity = &Q.UserIdentity
+	UserOperationLog = &Q.UserOperationLog
 	UserRoles = &Q.UserRoles
 }
 
 func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 	return &Query{
-		db:        db,
-		DictDatum: newDictDatum(db, opts...),
-		DictType:  newDictType(db, opts...),
-		Role:      newRole(db, opts...),
-		RoleMenus: newRoleMenus(db, opts...),
-		SysMenu:   newSysMenu(db, opts...),
-		User:      newUser(db, opts...),
-		UserRoles: newUserRoles(db, opts...),
+		db:               db,
+		DictDatum:        newDictDatum(db, opts...),
+		DictType:         newDictType(db, opts...),
+		OAuthClient:      newOAuthClient(db, opts...),
+		OAuthConsent:     newOAuthConsent(db, opts...),
+		Role:             newRole(db, opts...),
+		RoleMenus:        newRoleMenus(db, opts...),
+		SysMenu:          newSysMenu(db, opts...),
+		User:             newUser(db, opts...),
+		UserIdentity:     newUserIdentity(db, opts...),
+		UserOperationLog: newUserOperationLog(db, opts...),
+		UserRoles:        newUserRoles(db, opts...),
 	}
 }
 
 type Query struct {
 	db *gorm.DB
 
-	DictDatum dictDatum
-	DictType  dictType
-	Role      role
-	RoleMenus roleMenus
-	SysMenu   sysMenu
-	User      user
-	UserRoles userRoles
+	DictDatum        dictDatum
+	DictType         dictType
+	OAuthClient      oAuthClient
+	OAuthConsent     oAuthConsent
+	Role             role
+	RoleMenus        roleMenus
+	SysMenu          sysMenu
+	User             user
+	UserIdentity     userIdentity
+	UserOperationLog userOperationLog
+	UserRoles        userRoles
 }
 
 func (q *Query) Available() bool { return q.db != nil }
 
 func (q *Query) clone(db *gorm.DB) *Query {
 	return &Query{
-		db:        db,
-		DictDatum: q.DictDatum.clone(db),
-		DictType:  q.DictType.clone(db),
-		Role:      q.Role.clone(db),
-		RoleMenus: q.RoleMenus.clone(db),
-		SysMenu:   q.SysMenu.clone(db),
-		User:      q.User.clone(db),
-		UserRoles: q.UserRoles.clone(db),
+		db:               db,
+		DictDatum:        q.DictDatum.clone(db),
+		DictType:         q.DictType.clone(db),
+		OAuthClient:      q.OAuthClient.clone(db),
+		OAuthConsent:     q.OAuthConsent.clone(db),
+		Role:             q.Role.clone(db),
+		RoleMenus:        q.RoleMenus.clone(db),
+		SysMenu:          q.SysMenu.clone(db),
+		User:             q.User.clone(db),
+		UserIdentity:     q.UserIdentity.clone(db),
+		UserOperationLog: q.UserOperationLog.clone(db),
+		UserRoles:        q.UserRoles.clone(db),
 	}
 }
 
@@ -87,36 +107,48 @@ func (q *Query) WriteDB() *Query {
 
 func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 	return &Query{
-		db:        db,
-		DictDatum: q.DictDatum.replaceDB(db),
-		DictType:  q.DictType.replaceDB(db),
-		Role:      q.Role.replaceDB(db),
-		RoleMenus: q.RoleMenus.replaceDB(db),
-		SysMenu:   q.SysMenu.replaceDB(db),
-		User:      q.User.replaceDB(db),
-		UserRoles: q.UserRoles.replaceDB(db),
+		db:               db,
+		DictDatum:        q.DictDatum.replaceDB(db),
+		DictType:         q.DictType.replaceDB(db),
+		OAuthClient:      q.OAuthClient.replaceDB(db),
+		OAuthConsent:     q.OAuthConsent.replaceDB(db),
+		Role:             q.Role.replaceDB(db),
+		RoleMenus:        q.RoleMenus.replaceDB(db),
+		SysMenu:          q.SysMenu.replaceDB(db),
+		User:             q.User.replaceDB(db),
+		UserIdentity:     q.UserIdentity.replaceDB(db),
+		UserOperationLog: q.UserOperationLog.replaceDB(db),
+		UserRoles:        q.UserRoles.replaceDB(db),
 	}
 }
 
 type queryCtx struct {
-	DictDatum IDictDatumDo
-	DictType  IDictTypeDo
-	Role      IRoleDo
-	RoleMenus IRoleMenusDo
-	SysMenu   ISysMenuDo
-	User      IUserDo
-	UserRoles IUserRolesDo
+	DictDatum        IDictDatumDo
+	DictType         IDictTypeDo
+	OAuthClient      IOAuthClientDo
+	OAuthConsent     IOAuthConsentDo
+	Role             IRoleDo
+	RoleMenus        IRoleMenusDo
+	SysMenu          ISysMenuDo
+	User             IUserDo
+	UserIdentity     IUserIdentityDo
+	UserOperationLog IUserOperationLogDo
+	UserRoles        IUserRolesDo
 }
 
 func (q *Query) WithContext(ctx context.Context) *queryCtx {
 	return &queryCtx{
-		DictDatum: q.DictDatum.WithContext(ctx),
-		DictType:  q.DictType.WithContext(ctx),
-		Role:      q.Role.WithContext(ctx),
-		RoleMenus: q.RoleMenus.WithContext(ctx),
-		SysMenu:   q.SysMenu.WithContext(ctx),
-		User:      q.User.WithContext(ctx),
-		UserRoles: q.UserRoles.WithContext(ctx),
+		DictDatum:        q.DictDatum.WithContext(ctx),
+		DictType:         q.DictType.WithContext(ctx),
+		OAuthClient:      q.OAuthClient.WithContext(ctx),
+		OAuthConsent:     q.OAuthConsent.WithContext(ctx),
+		Role:             q.Role.WithContext(ctx),
+		RoleMenus:        q.RoleMenus.WithContext(ctx),
+		SysMenu:          q.SysMenu.WithContext(ctx),
+		User:             q.User.WithContext(ctx),
+		UserIdentity:     q.UserIdentity.WithContext(ctx),
+		UserOperationLog: q.UserOperationLog.WithContext(ctx),
+		UserRoles:        q.UserRoles.WithContext(ctx),
 	}
 }
 
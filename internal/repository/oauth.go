@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/internal/service"
+)
+
+type oauthClientRepository struct {
+	query *Query
+}
+
+func NewOAuthClientRepository(query *Query) service.OAuthClientRepository {
+	return &oauthClientRepository{query: query}
+}
+
+func (r *oauthClientRepository) Create(ctx context.Context, client *model.OAuthClient) error {
+	return r.query.WithContext(ctx).OAuthClient.Create(client)
+}
+
+func (r *oauthClientRepository) Update(ctx context.Context, client *model.OAuthClient) error {
+	c := r.query.OAuthClient
+	_, err := r.query.WithContext(ctx).OAuthClient.
+		Where(c.ID.Eq(client.ID)).
+		Select(c.Name, c.RedirectURIs, c.GrantTypes, c.Scopes_, c.SkipConsent, c.Status, c.Remark).
+		Updates(client)
+	return err
+}
+
+func (r *oauthClientRepository) UpdateSecret(ctx context.Context, id uint64, secret string) error {
+	c := r.query.OAuthClient
+	_, err := r.query.WithContext(ctx).OAuthClient.Where(c.ID.Eq(id)).Update(c.ClientSecret, secret)
+	return err
+}
+
+func (r *oauthClientRepository) Delete(ctx context.Context, ids ...uint64) error {
+	_, err := r.query.WithContext(ctx).OAuthClient.Where(r.query.OAuthClient.ID.In(ids...)).Delete()
+	return err
+}
+
+func (r *oauthClientRepository) FindByID(ctx context.Context, id uint64) (*model.OAuthClient, error) {
+	client, err := r.query.WithContext(ctx).OAuthClient.Where(r.query.OAuthClient.ID.Eq(id)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return client, nil
+}
+
+func (r *oauthClientRepository) FindByClientID(ctx context.Context, clientID string) (*model.OAuthClient, error) {
+	client, err := r.query.WithContext(ctx).OAuthClient.Where(r.query.OAuthClient.ClientID.Eq(clientID)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return client, nil
+}
+
+func (r *oauthClientRepository) List(ctx context.Context, query *model.OAuthClientQuery) ([]*model.OAuthClient, int64, error) {
+	c := r.query.OAuthClient
+	db := r.query.WithContext(ctx).OAuthClient
+	if query.Name != "" {
+		db = db.Where(c.Name.Like("%" + query.Name + "%"))
+	}
+	if query.ClientID != "" {
+		db = db.Where(c.ClientID.Like("%" + query.ClientID + "%"))
+	}
+	if query.Status != 0 {
+		db = db.Where(c.Status.Eq(query.Status))
+	}
+	total, err := db.Count()
+	if err != nil {
+		return nil, 0, err
+	}
+	clients, err := db.Order(c.ID.Desc()).Offset(query.GetOffset()).Limit(query.PageSize).Find()
+	if err != nil {
+		return nil, 0, err
+	}
+	return clients, total, nil
+}
+
+type oauthConsentRepository struct {
+	query *Query
+}
+
+func NewOAuthConsentRepository(query *Query) service.OAuthConsentRepository {
+	return &oauthConsentRepository{query: query}
+}
+
+func (r *oauthConsentRepository) Find(ctx context.Context, userID uint64, clientID string) (*model.OAuthConsent, error) {
+	c := r.query.OAuthConsent
+	consent, err := r.query.WithContext(ctx).OAuthConsent.Where(c.UserID.Eq(userID), c.ClientID.Eq(clientID)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return consent, nil
+}
+
+func (r *oauthConsentRepository) Save(ctx context.Context, consent *model.OAuthConsent) error {
+	return r.query.WithContext(ctx).OAuthConsent.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "client_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"scopes", "updated_at"}),
+	}).Create(consent)
+}
+
+func (r *oauthConsentRepository) DeleteByClientID(ctx context.Context, clientIDs ...string) error {
+	_, err := r.query.WithContext(ctx).OAuthConsent.Where(r.query.OAuthConsent.ClientID.In(clientIDs...)).Delete()
+	return err
+}
+
+func (r *oauthConsentRepository) DeleteByUserIDs(ctx context.Context, userIDs ...uint64) error {
+	_, err := r.query.WithContext(ctx).OAuthConsent.Where(r.query.OAuthConsent.UserID.In(userIDs...)).Delete()
+	return err
+}
@@ -0,0 +1,432 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+)
+
+func newOAuthClient(db *gorm.DB, opts ...gen.DOOption) oAuthClient {
+	_oAuthClient := oAuthClient{}
+
+	_oAuthClient.oAuthClientDo.UseDB(db, opts...)
+	_oAuthClient.oAuthClientDo.UseModel(&model.OAuthClient{})
+
+	tableName := _oAuthClient.oAuthClientDo.TableName()
+	_oAuthClient.ALL = field.NewAsterisk(tableName)
+	_oAuthClient.ID = field.NewUint64(tableName, "id")
+	_oAuthClient.ClientID = field.NewString(tableName, "client_id")
+	_oAuthClient.ClientSecret = field.NewString(tableName, "client_secret")
+	_oAuthClient.Name = field.NewString(tableName, "name")
+	_oAuthClient.RedirectURIs = field.NewString(tableName, "redirect_uris")
+	_oAuthClient.GrantTypes = field.NewString(tableName, "grant_types")
+	_oAuthClient.Scopes_ = field.NewString(tableName, "scopes")
+	_oAuthClient.SkipConsent = field.NewBool(tableName, "skip_consent")
+	_oAuthClient.Status = field.NewInt8(tableName, "status")
+	_oAuthClient.Remark = field.NewString(tableName, "remark")
+	_oAuthClient.CreatedBy = field.NewUint64(tableName, "created_by")
+	_oAuthClient.UpdatedBy = field.NewUint64(tableName, "updated_by")
+	_oAuthClient.CreatedAt = field.NewTime(tableName, "created_at")
+	_oAuthClient.UpdatedAt = field.NewTime(tableName, "updated_at")
+
+	_oAuthClient.fillFieldMap()
+
+	return _oAuthClient
+}
+
+type oAuthClient struct {
+	oAuthClientDo
+
+	ALL          field.Asterisk
+	ID           field.Uint64
+	ClientID     field.String
+	ClientSecret field.String
+	Name         field.String
+	RedirectURIs field.String
+	GrantTypes   field.String
+	Scopes_      field.String
+	SkipConsent  field.Bool
+	Status       field.Int8
+	Remark       field.String
+	CreatedBy    field.Uint64
+	UpdatedBy    field.Uint64
+	CreatedAt    field.Time
+	UpdatedAt    field.Time
+
+	fieldMap map[string]field.Expr
+}
+
+func (o oAuthClient) Table(newTableName string) *oAuthClient {
+	o.oAuthClientDo.UseTable(newTableName)
+	return o.updateTableName(newTableName)
+}
+
+func (o oAuthClient) As(alias string) *oAuthClient {
+	o.oAuthClientDo.DO = *(o.oAuthClientDo.As(alias).(*gen.DO))
+	return o.updateTableName(alias)
+}
+
+func (o *oAuthClient) updateTableName(table string) *oAuthClient {
+	o.ALL = field.NewAsterisk(table)
+	o.ID = field.NewUint64(table, "id")
+	o.ClientID = field.NewString(table, "client_id")
+	o.ClientSecret = field.NewString(table, "client_secret")
+	o.Name = field.NewString(table, "name")
+	o.RedirectURIs = field.NewString(table, "redirect_uris")
+	o.GrantTypes = field.NewString(table, "grant_types")
+	o.Scopes_ = field.NewString(table, "scopes")
+	o.SkipConsent = field.NewBool(table, "skip_consent")
+	o.Status = field.NewInt8(table, "status")
+	o.Remark = field.NewString(table, "remark")
+	o.CreatedBy = field.NewUint64(table, "created_by")
+	o.UpdatedBy = field.NewUint64(table, "updated_by")
+	o.CreatedAt = field.NewTime(table, "created_at")
+	o.UpdatedAt = field.NewTime(table, "updated_at")
+
+	o.fillFieldMap()
+
+	return o
+}
+
+func (o *oAuthClient) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := o.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (o *oAuthClient) fillFieldMap() {
+	o.fieldMap = make(map[string]field.Expr, 14)
+	o.fieldMap["id"] = o.ID
+	o.fieldMap["client_id"] = o.ClientID
+	o.fieldMap["client_secret"] = o.ClientSecret
+	o.fieldMap["name"] = o.Name
+	o.fieldMap["redirect_uris"] = o.RedirectURIs
+	o.fieldMap["grant_types"] = o.GrantTypes
+	o.fieldMap["scopes"] = o.Scopes_
+	o.fieldMap["skip_consent"] = o.SkipConsent
+	o.fieldMap["status"] = o.Status
+	o.fieldMap["remark"] = o.Remark
+	o.fieldMap["created_by"] = o.CreatedBy
+	o.fieldMap["updated_by"] = o.UpdatedBy
+	o.fieldMap["created_at"] = o.CreatedAt
+	o.fieldMap["updated_at"] = o.UpdatedAt
+}
+
+func (o oAuthClient) clone(db *gorm.DB) oAuthClient {
+	o.oAuthClientDo.ReplaceConnPool(db.Statement.ConnPool)
+	return o
+}
+
+func (o oAuthClient) replaceDB(db *gorm.DB) oAuthClient {
+	o.oAuthClientDo.ReplaceDB(db)
+	return o
+}
+
+type oAuthClientDo struct{ gen.DO }
+
+type IOAuthClientDo interface {
+	gen.SubQuery
+	Debug() IOAuthClientDo
+	WithContext(ctx context.Context) IOAuthClientDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IOAuthClientDo
+	WriteDB() IOAuthClientDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IOAuthClientDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IOAuthClientDo
+	Not(conds ...gen.Condition) IOAuthClientDo
+	Or(conds ...gen.Condition) IOAuthClientDo
+	Select(conds ...field.Expr) IOAuthClientDo
+	Where(conds ...gen.Condition) IOAuthClientDo
+	Order(conds ...field.Expr) IOAuthClientDo
+	Distinct(cols ...field.Expr) IOAuthClientDo
+	Omit(cols ...field.Expr) IOAuthClientDo
+	Join(table schema.Tabler, on ...field.Expr) IOAuthClientDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IOAuthClientDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IOAuthClientDo
+	Group(cols ...field.Expr) IOAuthClientDo
+	Having(conds ...gen.Condition) IOAuthClientDo
+	Limit(limit int) IOAuthClientDo
+	Offset(offset int) IOAuthClientDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IOAuthClientDo
+	Unscoped() IOAuthClientDo
+	Create(values ...*model.OAuthClient) error
+	CreateInBatches(values []*model.OAuthClient, batchSize int) error
+	Save(values ...*model.OAuthClient) error
+	First() (*model.OAuthClient, error)
+	Take() (*model.OAuthClient, error)
+	Last() (*model.OAuthClient, error)
+	Find() ([]*model.OAuthClient, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.OAuthClient, err error)
+	FindInBatches(result *[]*model.OAuthClient, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.OAuthClient) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IOAuthClientDo
+	Assign(attrs ...field.AssignExpr) IOAuthClientDo
+	Joins(fields ...field.RelationField) IOAuthClientDo
+	Preload(fields ...field.RelationField) IOAuthClientDo
+	FirstOrInit() (*model.OAuthClient, error)
+	FirstOrCreate() (*model.OAuthClient, error)
+	FindByPage(offset int, limit int) (result []*model.OAuthClient, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IOAuthClientDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (o oAuthClientDo) Debug() IOAuthClientDo {
+	return o.withDO(o.DO.Debug())
+}
+
+func (o oAuthClientDo) WithContext(ctx context.Context) IOAuthClientDo {
+	return o.withDO(o.DO.WithContext(ctx))
+}
+
+func (o oAuthClientDo) ReadDB() IOAuthClientDo {
+	return o.Clauses(dbresolver.Read)
+}
+
+func (o oAuthClientDo) WriteDB() IOAuthClientDo {
+	return o.Clauses(dbresolver.Write)
+}
+
+func (o oAuthClientDo) Session(config *gorm.Session) IOAuthClientDo {
+	return o.withDO(o.DO.Session(config))
+}
+
+func (o oAuthClientDo) Clauses(conds ...clause.Expression) IOAuthClientDo {
+	return o.withDO(o.DO.Clauses(conds...))
+}
+
+func (o oAuthClientDo) Returning(value interface{}, columns ...string) IOAuthClientDo {
+	return o.withDO(o.DO.Returning(value, columns...))
+}
+
+func (o oAuthClientDo) Not(conds ...gen.Condition) IOAuthClientDo {
+	return o.withDO(o.DO.Not(conds...))
+}
+
+func (o oAuthClientDo) Or(conds ...gen.Condition) IOAuthClientDo {
+	return o.withDO(o.DO.Or(conds...))
+}
+
+func (o oAuthClientDo) Select(conds ...field.Expr) IOAuthClientDo {
+	return o.withDO(o.DO.Select(conds...))
+}
+
+func (o oAuthClientDo) Where(conds ...gen.Condition) IOAuthClientDo {
+	return o.withDO(o.DO.Where(conds...))
+}
+
+func (o oAuthClientDo) Order(conds ...field.Expr) IOAuthClientDo {
+	return o.withDO(o.DO.Order(conds...))
+}
+
+func (o oAuthClientDo) Distinct(cols ...field.Expr) IOAuthClientDo {
+	return o.withDO(o.DO.Distinct(cols...))
+}
+
+func (o oAuthClientDo) Omit(cols ...field.Expr) IOAuthClientDo {
+	return o.withDO(o.DO.Omit(cols...))
+}
+
+func (o oAuthClientDo) Join(table schema.Tabler, on ...field.Expr) IOAuthClientDo {
+	return o.withDO(o.DO.Join(table, on...))
+}
+
+func (o oAuthClientDo) LeftJoin(table schema.Tabler, on ...field.Expr) IOAuthClientDo {
+	return o.withDO(o.DO.LeftJoin(table, on...))
+}
+
+func (o oAuthClientDo) RightJoin(table schema.Tabler, on ...field.Expr) IOAuthClientDo {
+	return o.withDO(o.DO.RightJoin(table, on...))
+}
+
+func (o oAuthClientDo) Group(cols ...field.Expr) IOAuthClientDo {
+	return o.withDO(o.DO.Group(cols...))
+}
+
+func (o oAuthClientDo) Having(conds ...gen.Condition) IOAuthClientDo {
+	return o.withDO(o.DO.Having(conds...))
+}
+
+func (o oAuthClientDo) Limit(limit int) IOAuthClientDo {
+	return o.withDO(o.DO.Limit(limit))
+}
+
+func (o oAuthClientDo) Offset(offset int) IOAuthClientDo {
+	return o.withDO(o.DO.Offset(offset))
+}
+
+func (o oAuthClientDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IOAuthClientDo {
+	return o.withDO(o.DO.Scopes(funcs...))
+}
+
+func (o oAuthClientDo) Unscoped() IOAuthClientDo {
+	return o.withDO(o.DO.Unscoped())
+}
+
+func (o oAuthClientDo) Create(values ...*model.OAuthClient) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return o.DO.Create(values)
+}
+
+func (o oAuthClientDo) CreateInBatches(values []*model.OAuthClient, batchSize int) error {
+	return o.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (o oAuthClientDo) Save(values ...*model.OAuthClient) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return o.DO.Save(values)
+}
+
+func (o oAuthClientDo) First() (*model.OAuthClient, error) {
+	if result, err := o.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.OAuthClient), nil
+	}
+}
+
+func (o oAuthClientDo) Take() (*model.OAuthClient, error) {
+	if result, err := o.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.OAuthClient), nil
+	}
+}
+
+func (o oAuthClientDo) Last() (*model.OAuthClient, error) {
+	if result, err := o.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.OAuthClient), nil
+	}
+}
+
+func (o oAuthClientDo) Find() ([]*model.OAuthClient, error) {
+	result, err := o.DO.Find()
+	return result.([]*model.OAuthClient), err
+}
+
+func (o oAuthClientDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.OAuthClient, err error) {
+	buf := make([]*model.OAuthClient, 0, batchSize)
+	err = o.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (o oAuthClientDo) FindInBatches(result *[]*model.OAuthClient, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return o.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (o oAuthClientDo) Attrs(attrs ...field.AssignExpr) IOAuthClientDo {
+	return o.withDO(o.DO.Attrs(attrs...))
+}
+
+func (o oAuthClientDo) Assign(attrs ...field.AssignExpr) IOAuthClientDo {
+	return o.withDO(o.DO.Assign(attrs...))
+}
+
+func (o oAuthClientDo) Joins(fields ...field.RelationField) IOAuthClientDo {
+	for _, _f := range fields {
+		o = *o.withDO(o.DO.Joins(_f))
+	}
+	return &o
+}
+
+func (o oAuthClientDo) Preload(fields ...field.RelationField) IOAuthClientDo {
+	for _, _f := range fields {
+		o = *o.withDO(o.DO.Preload(_f))
+	}
+	return &o
+}
+
+func (o oAuthClientDo) FirstOrInit() (*model.OAuthClient, error) {
+	if result, err := o.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.OAuthClient), nil
+	}
+}
+
+func (o oAuthClientDo) FirstOrCreate() (*model.OAuthClient, error) {
+	if result, err := o.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.OAuthClient), nil
+	}
+}
+
+func (o oAuthClientDo) FindByPage(offset int, limit int) (result []*model.OAuthClient, count int64, err error) {
+	result, err = o.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = o.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (o oAuthClientDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = o.Count()
+	if err != nil {
+		return
+	}
+
+	err = o.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (o oAuthClientDo) Scan(result interface{}) (err error) {
+	return o.DO.Scan(result)
+}
+
+func (o oAuthClientDo) Delete(models ...*model.OAuthClient) (result gen.ResultInfo, err error) {
+	return o.DO.Delete(models)
+}
+
+func (o *oAuthClientDo) withDO(do gen.Dao) *oAuthClientDo {
+	o.DO = *do.(*gen.DO)
+	return o
+}
@@ -0,0 +1,400 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+)
+
+func newOAuthConsent(db *gorm.DB, opts ...gen.DOOption) oAuthConsent {
+	_oAuthConsent := oAuthConsent{}
+
+	_oAuthConsent.oAuthConsentDo.UseDB(db, opts...)
+	_oAuthConsent.oAuthConsentDo.UseModel(&model.OAuthConsent{})
+
+	tableName := _oAuthConsent.oAuthConsentDo.TableName()
+	_oAuthConsent.ALL = field.NewAsterisk(tableName)
+	_oAuthConsent.ID = field.NewUint64(tableName, "id")
+	_oAuthConsent.UserID = field.NewUint64(tableName, "user_id")
+	_oAuthConsent.ClientID = field.NewString(tableName, "client_id")
+	_oAuthConsent.Scopes_ = field.NewString(tableName, "scopes")
+	_oAuthConsent.CreatedAt = field.NewTime(tableName, "created_at")
+	_oAuthConsent.UpdatedAt = field.NewTime(tableName, "updated_at")
+
+	_oAuthConsent.fillFieldMap()
+
+	return _oAuthConsent
+}
+
+type oAuthConsent struct {
+	oAuthConsentDo
+
+	ALL       field.Asterisk
+	ID        field.Uint64
+	UserID    field.Uint64
+	ClientID  field.String
+	Scopes_   field.String
+	CreatedAt field.Time
+	UpdatedAt field.Time
+
+	fieldMap map[string]field.Expr
+}
+
+func (o oAuthConsent) Table(newTableName string) *oAuthConsent {
+	o.oAuthConsentDo.UseTable(newTableName)
+	return o.updateTableName(newTableName)
+}
+
+func (o oAuthConsent) As(alias string) *oAuthConsent {
+	o.oAuthConsentDo.DO = *(o.oAuthConsentDo.As(alias).(*gen.DO))
+	return o.updateTableName(alias)
+}
+
+func (o *oAuthConsent) updateTableName(table string) *oAuthConsent {
+	o.ALL = field.NewAsterisk(table)
+	o.ID = field.NewUint64(table, "id")
+	o.UserID = field.NewUint64(table, "user_id")
+	o.ClientID = field.NewString(table, "client_id")
+	o.Scopes_ = field.NewString(table, "scopes")
+	o.CreatedAt = field.NewTime(table, "created_at")
+	o.UpdatedAt = field.NewTime(table, "updated_at")
+
+	o.fillFieldMap()
+
+	return o
+}
+
+func (o *oAuthConsent) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := o.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (o *oAuthConsent) fillFieldMap() {
+	o.fieldMap = make(map[string]field.Expr, 6)
+	o.fieldMap["id"] = o.ID
+	o.fieldMap["user_id"] = o.UserID
+	o.fieldMap["client_id"] = o.ClientID
+	o.fieldMap["scopes"] = o.Scopes_
+	o.fieldMap["created_at"] = o.CreatedAt
+	o.fieldMap["updated_at"] = o.UpdatedAt
+}
+
+func (o oAuthConsent) clone(db *gorm.DB) oAuthConsent {
+	o.oAuthConsentDo.ReplaceConnPool(db.Statement.ConnPool)
+	return o
+}
+
+func (o oAuthConsent) replaceDB(db *gorm.DB) oAuthConsent {
+	o.oAuthConsentDo.ReplaceDB(db)
+	return o
+}
+
+type oAuthConsentDo struct{ gen.DO }
+
+type IOAuthConsentDo interface {
+	gen.SubQuery
+	Debug() IOAuthConsentDo
+	WithContext(ctx context.Context) IOAuthConsentDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IOAuthConsentDo
+	WriteDB() IOAuthConsentDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IOAuthConsentDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IOAuthConsentDo
+	Not(conds ...gen.Condition) IOAuthConsentDo
+	Or(conds ...gen.Condition) IOAuthConsentDo
+	Select(conds ...field.Expr) IOAuthConsentDo
+	Where(conds ...gen.Condition) IOAuthConsentDo
+	Order(conds ...field.Expr) IOAuthConsentDo
+	Distinct(cols ...field.Expr) IOAuthConsentDo
+	Omit(cols ...field.Expr) IOAuthConsentDo
+	Join(table schema.Tabler, on ...field.Expr) IOAuthConsentDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IOAuthConsentDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IOAuthConsentDo
+	Group(cols ...field.Expr) IOAuthConsentDo
+	Having(conds ...gen.Condition) IOAuthConsentDo
+	Limit(limit int) IOAuthConsentDo
+	Offset(offset int) IOAuthConsentDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IOAuthConsentDo
+	Unscoped() IOAuthConsentDo
+	Create(values ...*model.OAuthConsent) error
+	CreateInBatches(values []*model.OAuthConsent, batchSize int) error
+	Save(values ...*model.OAuthConsent) error
+	First() (*model.OAuthConsent, error)
+	Take() (*model.OAuthConsent, error)
+	Last() (*model.OAuthConsent, error)
+	Find() ([]*model.OAuthConsent, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.OAuthConsent, err error)
+	FindInBatches(result *[]*model.OAuthConsent, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.OAuthConsent) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IOAuthConsentDo
+	Assign(attrs ...field.AssignExpr) IOAuthConsentDo
+	Joins(fields ...field.RelationField) IOAuthConsentDo
+	Preload(fields ...field.RelationField) IOAuthConsentDo
+	FirstOrInit() (*model.OAuthConsent, error)
+	FirstOrCreate() (*model.OAuthConsent, error)
+	FindByPage(offset int, limit int) (result []*model.OAuthConsent, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IOAuthConsentDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (o oAuthConsentDo) Debug() IOAuthConsentDo {
+	return o.withDO(o.DO.Debug())
+}
+
+func (o oAuthConsentDo) WithContext(ctx context.Context) IOAuthConsentDo {
+	return o.withDO(o.DO.WithContext(ctx))
+}
+
+func (o oAuthConsentDo) ReadDB() IOAuthConsentDo {
+	return o.Clauses(dbresolver.Read)
+}
+
+func (o oAuthConsentDo) WriteDB() IOAuthConsentDo {
+	return o.Clauses(dbresolver.Write)
+}
+
+func (o oAuthConsentDo) Session(config *gorm.Session) IOAuthConsentDo {
+	return o.withDO(o.DO.Session(config))
+}
+
+func (o oAuthConsentDo) Clauses(conds ...clause.Expression) IOAuthConsentDo {
+	return o.withDO(o.DO.Clauses(conds...))
+}
+
+func (o oAuthConsentDo) Returning(value interface{}, columns ...string) IOAuthConsentDo {
+	return o.withDO(o.DO.Returning(value, columns...))
+}
+
+func (o oAuthConsentDo) Not(conds ...gen.Condition) IOAuthConsentDo {
+	return o.withDO(o.DO.Not(conds...))
+}
+
+func (o oAuthConsentDo) Or(conds ...gen.Condition) IOAuthConsentDo {
+	return o.withDO(o.DO.Or(conds...))
+}
+
+func (o oAuthConsentDo) Select(conds ...field.Expr) IOAuthConsentDo {
+	return o.withDO(o.DO.Select(conds...))
+}
+
+func (o oAuthConsentDo) Where(conds ...gen.Condition) IOAuthConsentDo {
+	return o.withDO(o.DO.Where(conds...))
+}
+
+func (o oAuthConsentDo) Order(conds ...field.Expr) IOAuthConsentDo {
+	return o.withDO(o.DO.Order(conds...))
+}
+
+func (o oAuthConsentDo) Distinct(cols ...field.Expr) IOAuthConsentDo {
+	return o.withDO(o.DO.Distinct(cols...))
+}
+
+func (o oAuthConsentDo) Omit(cols ...field.Expr) IOAuthConsentDo {
+	return o.withDO(o.DO.Omit(cols...))
+}
+
+func (o oAuthConsentDo) Join(table schema.Tabler, on ...field.Expr) IOAuthConsentDo {
+	return o.withDO(o.DO.Join(table, on...))
+}
+
+func (o oAuthConsentDo) LeftJoin(table schema.Tabler, on ...field.Expr) IOAuthConsentDo {
+	return o.withDO(o.DO.LeftJoin(table, on...))
+}
+
+func (o oAuthConsentDo) RightJoin(table schema.Tabler, on ...field.Expr) IOAuthConsentDo {
+	return o.withDO(o.DO.RightJoin(table, on...))
+}
+
+func (o oAuthConsentDo) Group(cols ...field.Expr) IOAuthConsentDo {
+	return o.withDO(o.DO.Group(cols...))
+}
+
+func (o oAuthConsentDo) Having(conds ...gen.Condition) IOAuthConsentDo {
+	return o.withDO(o.DO.Having(conds...))
+}
+
+func (o oAuthConsentDo) Limit(limit int) IOAuthConsentDo {
+	return o.withDO(o.DO.Limit(limit))
+}
+
+func (o oAuthConsentDo) Offset(offset int) IOAuthConsentDo {
+	return o.withDO(o.DO.Offset(offset))
+}
+
+func (o oAuthConsentDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IOAuthConsentDo {
+	return o.withDO(o.DO.Scopes(funcs...))
+}
+
+func (o oAuthConsentDo) Unscoped() IOAuthConsentDo {
+	return o.withDO(o.DO.Unscoped())
+}
+
+func (o oAuthConsentDo) Create(values ...*model.OAuthConsent) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return o.DO.Create(values)
+}
+
+func (o oAuthConsentDo) CreateInBatches(values []*model.OAuthConsent, batchSize int) error {
+	return o.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (o oAuthConsentDo) Save(values ...*model.OAuthConsent) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return o.DO.Save(values)
+}
+
+func (o oAuthConsentDo) First() (*model.OAuthConsent, error) {
+	if result, err := o.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.OAuthConsent), nil
+	}
+}
+
+func (o oAuthConsentDo) Take() (*model.OAuthConsent, error) {
+	if result, err := o.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.OAuthConsent), nil
+	}
+}
+
+func (o oAuthConsentDo) Last() (*model.OAuthConsent, error) {
+	if result, err := o.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.OAuthConsent), nil
+	}
+}
+
+func (o oAuthConsentDo) Find() ([]*model.OAuthConsent, error) {
+	result, err := o.DO.Find()
+	return result.([]*model.OAuthConsent), err
+}
+
+func (o oAuthConsentDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.OAuthConsent, err error) {
+	buf := make([]*model.OAuthConsent, 0, batchSize)
+	err = o.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (o oAuthConsentDo) FindInBatches(result *[]*model.OAuthConsent, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return o.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (o oAuthConsentDo) Attrs(attrs ...field.AssignExpr) IOAuthConsentDo {
+	return o.withDO(o.DO.Attrs(attrs...))
+}
+
+func (o oAuthConsentDo) Assign(attrs ...field.AssignExpr) IOAuthConsentDo {
+	return o.withDO(o.DO.Assign(attrs...))
+}
+
+func (o oAuthConsentDo) Joins(fields ...field.RelationField) IOAuthConsentDo {
+	for _, _f := range fields {
+		o = *o.withDO(o.DO.Joins(_f))
+	}
+	return &o
+}
+
+func (o oAuthConsentDo) Preload(fields ...field.RelationField) IOAuthConsentDo {
+	for _, _f := range fields {
+		o = *o.withDO(o.DO.Preload(_f))
+	}
+	return &o
+}
+
+func (o oAuthConsentDo) FirstOrInit() (*model.OAuthConsent, error) {
+	if result, err := o.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.OAuthConsent), nil
+	}
+}
+
+func (o oAuthConsentDo) FirstOrCreate() (*model.OAuthConsent, error) {
+	if result, err := o.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.OAuthConsent), nil
+	}
+}
+
+func (o oAuthConsentDo) FindByPage(offset int, limit int) (result []*model.OAuthConsent, count int64, err error) {
+	result, err = o.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = o.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (o oAuthConsentDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = o.Count()
+	if err != nil {
+		return
+	}
+
+	err = o.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (o oAuthConsentDo) Scan(result interface{}) (err error) {
+	return o.DO.Scan(result)
+}
+
+func (o oAuthConsentDo) Delete(models ...*model.OAuthConsent) (result gen.ResultInfo, err error) {
+	return o.DO.Delete(models)
+}
+
+func (o *oAuthConsentDo) withDO(do gen.Dao) *oAuthConsentDo {
+	o.DO = *do.(*gen.DO)
+	return o
+}
@@ -84,3 +84,19 @@ func (r *repository) DictData() service.DictDataRepository {
 func (r *repository) SysMenu() service.SysMenuRepository {
 	return NewSysMenuRepository(r.query)
 }
+
+func (r *repository) UserIdentity() service.UserIdentityRepository {
+	return NewUserIdentityRepository(r.query)
+}
+
+func (r *repository) OAuthClient() service.OAuthClientRepository {
+	return NewOAuthClientRepository(r.query)
+}
+
+func (r *repository) OAuthConsent() service.OAuthConsentRepository {
+	return NewOAuthConsentRepository(r.query)
+}
+
+func (r *repository) UserOperationLog() service.UserOperationLogRepository {
+	return NewUserOperationLogRepository(r.query)
+}
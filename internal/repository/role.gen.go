@@ -33,8 +33,11 @@ func newRole(db *gorm.DB, opts ...gen.DOOption) role {
 	_role.Status = field.NewInt8(tableName, "status")
 	_role.Sort = field.NewInt16(tableName, "sort")
 	_role.Remark = field.NewString(tableName, "remark")
+	_role.CreatedBy = field.NewUint64(tableName, "created_by")
+	_role.UpdatedBy = field.NewUint64(tableName, "updated_by")
 	_role.CreatedAt = field.NewTime(tableName, "created_at")
 	_role.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_role.DeletedAt = field.NewField(tableName, "deleted_at")
 
 	_role.fillFieldMap()
 
@@ -51,8 +54,11 @@ type role struct {
 	Status    field.Int8
 	Sort      field.Int16
 	Remark    field.String
+	CreatedBy field.Uint64
+	UpdatedBy field.Uint64
 	CreatedAt field.Time
 	UpdatedAt field.Time
+	DeletedAt field.Field
 
 	fieldMap map[string]field.Expr
 }
@@ -75,8 +81,11 @@ func (r *role) updateTableName(table string) *role {
 	r.Status = field.NewInt8(table, "status")
 	r.Sort = field.NewInt16(table, "sort")
 	r.Remark = field.NewString(table, "remark")
+	r.CreatedBy = field.NewUint64(table, "created_by")
+	r.UpdatedBy = field.NewUint64(table, "updated_by")
 	r.CreatedAt = field.NewTime(table, "created_at")
 	r.UpdatedAt = field.NewTime(table, "updated_at")
+	r.DeletedAt = field.NewField(table, "deleted_at")
 
 	r.fillFieldMap()
 
@@ -93,15 +102,18 @@ func (r *role) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (r *role) fillFieldMap() {
-	r.fieldMap = make(map[string]field.Expr, 8)
+	r.fieldMap = make(map[string]field.Expr, 11)
 	r.fieldMap["id"] = r.ID
 	r.fieldMap["name"] = r.Name
 	r.fieldMap["code"] = r.Code
 	r.fieldMap["status"] = r.Status
 	r.fieldMap["sort"] = r.Sort
 	r.fieldMap["remark"] = r.Remark
+	r.fieldMap["created_by"] = r.CreatedBy
+	r.fieldMap["updated_by"] = r.UpdatedBy
 	r.fieldMap["created_at"] = r.CreatedAt
 	r.fieldMap["updated_at"] = r.UpdatedAt
+	r.fieldMap["deleted_at"] = r.DeletedAt
 }
 
 func (r role) clone(db *gorm.DB) role {
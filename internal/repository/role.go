@@ -7,6 +7,7 @@ import (
 	"github.com/wxlbd/gin-casbin-admin/internal/service"
 
 	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
 	"gorm.io/gorm"
 )
 
@@ -83,6 +84,40 @@ func (r *roleRepository) FindByCode(ctx context.Context, code string) (*model.Ro
 	return role, nil
 }
 
+func (r *roleRepository) FindByCodeWithDeleted(ctx context.Context, code string) (*model.Role, error) {
+	role, err := r.query.WithContext(ctx).Role.Unscoped().Where(r.query.Role.Code.Eq(code)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+func (r *roleRepository) ListDeleted(ctx context.Context, page *types.PageParam) ([]*model.Role, int64, error) {
+	return r.query.WithContext(ctx).Role.Unscoped().
+		Where(r.query.Role.DeletedAt.IsNotNull()).
+		Order(r.query.Role.DeletedAt.Desc()).
+		FindByPage(page.GetOffset(), page.PageSize)
+}
+
+func (r *roleRepository) FindDeletedByIDs(ctx context.Context, ids ...uint64) ([]*model.Role, error) {
+	return r.query.WithContext(ctx).Role.Unscoped().Where(r.query.Role.ID.In(ids...), r.query.Role.DeletedAt.IsNotNull()).Find()
+}
+
+func (r *roleRepository) Restore(ctx context.Context, ids ...uint64) error {
+	_, err := r.query.WithContext(ctx).Role.Unscoped().
+		Where(r.query.Role.ID.In(ids...), r.query.Role.DeletedAt.IsNotNull()).
+		Update(r.query.Role.DeletedAt, nil)
+	return err
+}
+
+func (r *roleRepository) Purge(ctx context.Context, ids ...uint64) error {
+	_, err := r.query.WithContext(ctx).Role.Unscoped().Where(r.query.Role.ID.In(ids...), r.query.Role.DeletedAt.IsNotNull()).Delete()
+	return err
+}
+
 func (r *roleRepository) List(ctx context.Context, query *model.RoleQuery) ([]*model.Role, int64, error) {
 	db := r.query.WithContext(ctx).Role
 	if query.Name != "" {
@@ -94,11 +129,14 @@ func (r *roleRepository) List(ctx context.Context, query *model.RoleQuery) ([]*m
 	if query.Status != 0 {
 		db = db.Where(r.query.Role.Status.Eq(query.Status))
 	}
-	total, err := db.Count()
-	if err != nil {
-		return nil, 0, err
+	var total int64
+	if !query.SkipCount {
+		var err error
+		if total, err = db.Count(); err != nil {
+			return nil, 0, err
+		}
 	}
-	roles, err := db.Offset(query.GetOffset()).Limit(query.PageSize).Find()
+	roles, err := db.Order(r.query.Role.ID).Offset(query.GetOffset()).Limit(query.PageSize).Find()
 	if err != nil {
 		return nil, 0, err
 	}
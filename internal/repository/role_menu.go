@@ -38,6 +38,16 @@ func (r *roleMenuRepository) DeleteByRoleID(ctx context.Context, roleID uint64)
 	return err
 }
 
+func (r *roleMenuRepository) DeleteByRoleIDs(ctx context.Context, roleIDs ...uint64) error {
+	_, err := r.query.WithContext(ctx).RoleMenus.Where(r.query.RoleMenus.RoleID.In(roleIDs...)).Delete()
+	return err
+}
+
+func (r *roleMenuRepository) DeleteByMenuIDs(ctx context.Context, menuIDs ...uint64) error {
+	_, err := r.query.WithContext(ctx).RoleMenus.Where(r.query.RoleMenus.MenuID.In(menuIDs...)).Delete()
+	return err
+}
+
 func (r *roleMenuRepository) FindMenusByRoleID(ctx context.Context, roleID uint64) ([]*model.SysMenu, error) {
 	menus, err := r.query.WithContext(ctx).SysMenu.LeftJoin(r.query.RoleMenus, r.query.RoleMenus.MenuID.EqCol(r.query.SysMenu.ID)).Where(r.query.RoleMenus.RoleID.Eq(roleID)).Find()
 	if err != nil {
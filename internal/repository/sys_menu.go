@@ -7,6 +7,7 @@ import (
 
 	"github.com/wxlbd/gin-casbin-admin/internal/model"
 	"github.com/wxlbd/gin-casbin-admin/internal/service"
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
 )
 
 type sysMenuRepository struct {
@@ -33,7 +34,7 @@ func (r *sysMenuRepository) Create(ctx context.Context, menu *model.SysMenu) err
 
 func (r *sysMenuRepository) Update(ctx context.Context, menu *model.SysMenu) error {
 	// gorm gen生成的代码使用结构体不能更新零值字段
-	err := r.db.Select("*").Omit("created_at").Updates(menu).Error
+	err := r.db.WithContext(ctx).Select("*").Omit("created_at").Updates(menu).Error
 	if err != nil {
 		return err
 	}
@@ -45,6 +46,29 @@ func (r *sysMenuRepository) Delete(ctx context.Context, ids ...int64) error {
 	return err
 }
 
+func (r *sysMenuRepository) ListDeleted(ctx context.Context, page *types.PageParam) ([]*model.SysMenu, int64, error) {
+	return r.WithContext(ctx).SysMenu.Unscoped().
+		Where(r.SysMenu.DeletedAt.IsNotNull()).
+		Order(r.SysMenu.DeletedAt.Desc()).
+		FindByPage(page.GetOffset(), page.PageSize)
+}
+
+func (r *sysMenuRepository) FindDeletedByIDs(ctx context.Context, ids ...int64) ([]*model.SysMenu, error) {
+	return r.WithContext(ctx).SysMenu.Unscoped().Where(r.SysMenu.ID.In(ids...), r.SysMenu.DeletedAt.IsNotNull()).Find()
+}
+
+func (r *sysMenuRepository) Restore(ctx context.Context, ids ...int64) error {
+	_, err := r.WithContext(ctx).SysMenu.Unscoped().
+		Where(r.SysMenu.ID.In(ids...), r.SysMenu.DeletedAt.IsNotNull()).
+		Update(r.SysMenu.DeletedAt, nil)
+	return err
+}
+
+func (r *sysMenuRepository) Purge(ctx context.Context, ids ...int64) error {
+	_, err := r.WithContext(ctx).SysMenu.Unscoped().Where(r.SysMenu.ID.In(ids...), r.SysMenu.DeletedAt.IsNotNull()).Delete()
+	return err
+}
+
 func (r *sysMenuRepository) Get(ctx context.Context, id int64) (*model.SysMenu, error) {
 	return r.WithContext(ctx).SysMenu.Where(r.SysMenu.ID.Eq(id)).First()
 }
@@ -80,14 +104,17 @@ func (r *sysMenuRepository) List(ctx context.Context, query *model.SysMenuQuery)
 	}
 
 	// 获取总数
-	total, err := q.Count()
-	if err != nil {
-		return nil, 0, err
+	var total int64
+	if !query.SkipCount {
+		var err error
+		if total, err = q.Count(); err != nil {
+			return nil, 0, err
+		}
 	}
 
-	// 分页查询
+	// 分页查询，排序值可能重复，以 ID 兜底保证翻页时行顺序稳定
 	offset := (query.Page - 1) * query.PageSize
-	list, err := q.Order(r.SysMenu.Rank).
+	list, err := q.Order(r.SysMenu.Rank, r.SysMenu.ID).
 		Offset(offset).
 		Limit(query.PageSize).
 		Find()
@@ -100,6 +127,11 @@ func (r *sysMenuRepository) FindAll(ctx context.Context) ([]*model.SysMenu, erro
 	return r.WithContext(ctx).SysMenu.Order(r.SysMenu.Rank).Find()
 }
 
+// FindAllWithDeleted 获取所有菜单，包含回收站中的记录
+func (r *sysMenuRepository) FindAllWithDeleted(ctx context.Context) ([]*model.SysMenu, error) {
+	return r.WithContext(ctx).SysMenu.Unscoped().Order(r.SysMenu.ID).Find()
+}
+
 // FindByRoleIDs 根据角色ID列表获取菜单
 func (r *sysMenuRepository) FindByRoleIDs(ctx context.Context, roleIDs ...uint64) ([]*model.SysMenu, error) {
 	// 1. 先查询角色菜单关系表获取菜单ID
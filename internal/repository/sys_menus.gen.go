@@ -50,8 +50,11 @@ func newSysMenu(db *gorm.DB, opts ...gen.DOOption) sysMenu {
 	_sysMenu.ShowLink = field.NewBool(tableName, "show_link")
 	_sysMenu.ShowParent = field.NewBool(tableName, "show_parent")
 	_sysMenu.Status = field.NewInt32(tableName, "status")
+	_sysMenu.CreatedBy = field.NewInt64(tableName, "created_by")
+	_sysMenu.UpdatedBy = field.NewInt64(tableName, "updated_by")
 	_sysMenu.CreatedAt = field.NewTime(tableName, "created_at")
 	_sysMenu.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_sysMenu.DeletedAt = field.NewField(tableName, "deleted_at")
 
 	_sysMenu.fillFieldMap()
 
@@ -86,8 +89,11 @@ type sysMenu struct {
 	ShowLink        field.Bool   // 是否显示
 	ShowParent      field.Bool   // 是否显示父级菜单
 	Status          field.Int32  // 菜单状态（0停用 1正常）
+	CreatedBy       field.Int64  // 创建者
+	UpdatedBy       field.Int64  // 更新者
 	CreatedAt       field.Time   // 创建时间
 	UpdatedAt       field.Time   // 更新时间
+	DeletedAt       field.Field  // 删除时间
 
 	fieldMap map[string]field.Expr
 }
@@ -127,8 +133,11 @@ func (s *sysMenu) updateTableName(table string) *sysMenu {
 	s.ShowLink = field.NewBool(table, "show_link")
 	s.ShowParent = field.NewBool(table, "show_parent")
 	s.Status = field.NewInt32(table, "status")
+	s.CreatedBy = field.NewInt64(table, "created_by")
+	s.UpdatedBy = field.NewInt64(table, "updated_by")
 	s.CreatedAt = field.NewTime(table, "created_at")
 	s.UpdatedAt = field.NewTime(table, "updated_at")
+	s.DeletedAt = field.NewField(table, "deleted_at")
 
 	s.fillFieldMap()
 
@@ -145,7 +154,7 @@ func (s *sysMenu) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (s *sysMenu) fillFieldMap() {
-	s.fieldMap = make(map[string]field.Expr, 25)
+	s.fieldMap = make(map[string]field.Expr, 28)
 	s.fieldMap["id"] = s.ID
 	s.fieldMap["parent_id"] = s.ParentID
 	s.fieldMap["menu_type"] = s.MenuType
@@ -169,8 +178,11 @@ func (s *sysMenu) fillFieldMap() {
 	s.fieldMap["show_link"] = s.ShowLink
 	s.fieldMap["show_parent"] = s.ShowParent
 	s.fieldMap["status"] = s.Status
+	s.fieldMap["created_by"] = s.CreatedBy
+	s.fieldMap["updated_by"] = s.UpdatedBy
 	s.fieldMap["created_at"] = s.CreatedAt
 	s.fieldMap["updated_at"] = s.UpdatedAt
+	s.fieldMap["deleted_at"] = s.DeletedAt
 }
 
 func (s sysMenu) clone(db *gorm.DB) sysMenu {
@@ -3,11 +3,13 @@ package repository
 import (
 	"context"
 	"errors"
+	"strings"
 	"gorm.io/gorm"
 
 	"github.com/wxlbd/gin-casbin-admin/internal/service"
 
 	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
 )
 
 type userRepository struct {
@@ -38,6 +40,25 @@ func (r *userRepository) Update(ctx context.Context, user *model.User) error {
 	return nil
 }
 
+func (r *userRepository) UpdateProfile(ctx context.Context, user *model.User) error {
+	u := r.query.User
+	_, err := r.query.WithContext(ctx).User.
+		Where(u.ID.Eq(user.ID)).
+		Select(u.Nickname, u.Phone, u.Email, u.Signed).
+		Updates(user)
+	return err
+}
+
+func (r *userRepository) UpdateAvatar(ctx context.Context, id uint64, avatar string) error {
+	_, err := r.query.WithContext(ctx).User.Where(r.query.User.ID.Eq(id)).Update(r.query.User.Avatar, avatar)
+	return err
+}
+
+func (r *userRepository) UpdateBackendSetting(ctx context.Context, id uint64, setting *types.BackendSetting) error {
+	_, err := r.query.WithContext(ctx).User.Where(r.query.User.ID.Eq(id)).Update(r.query.User.BackendSetting, setting)
+	return err
+}
+
 func (r *userRepository) Delete(ctx context.Context, ids ...uint64) error {
 	_, err := r.query.WithContext(ctx).User.Where(r.query.User.ID.In(ids...)).Delete()
 	return err
@@ -58,6 +79,50 @@ func (r *userRepository) FindByUsername(ctx context.Context, username string) (*
 	return user, nil
 }
 
+func (r *userRepository) ListByEmail(ctx context.Context, email string, limit int) ([]*model.User, error) {
+	// PostgreSQL、SQLite 的字符串比较区分大小写，统一转为小写比较
+	q := r.query.User
+	return r.query.WithContext(ctx).User.Where(q.Email.Lower().Eq(strings.ToLower(email))).Order(q.ID).Limit(limit).Find()
+}
+
+func (r *userRepository) FindByUsernameWithDeleted(ctx context.Context, username string) (*model.User, error) {
+	user, err := r.query.WithContext(ctx).User.Unscoped().Where(r.query.User.Username.Eq(username)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *userRepository) FindByUsernames(ctx context.Context, usernames ...string) ([]*model.User, error) {
+	return r.query.WithContext(ctx).User.Unscoped().Where(r.query.User.Username.In(usernames...)).Find()
+}
+
+func (r *userRepository) ListDeleted(ctx context.Context, page *types.PageParam) ([]*model.User, int64, error) {
+	return r.query.WithContext(ctx).User.Unscoped().
+		Where(r.query.User.DeletedAt.IsNotNull()).
+		Order(r.query.User.DeletedAt.Desc()).
+		FindByPage(page.GetOffset(), page.PageSize)
+}
+
+func (r *userRepository) FindDeletedByIDs(ctx context.Context, ids ...uint64) ([]*model.User, error) {
+	return r.query.WithContext(ctx).User.Unscoped().Where(r.query.User.ID.In(ids...), r.query.User.DeletedAt.IsNotNull()).Find()
+}
+
+func (r *userRepository) Restore(ctx context.Context, ids ...uint64) error {
+	_, err := r.query.WithContext(ctx).User.Unscoped().
+		Where(r.query.User.ID.In(ids...), r.query.User.DeletedAt.IsNotNull()).
+		Update(r.query.User.DeletedAt, nil)
+	return err
+}
+
+func (r *userRepository) Purge(ctx context.Context, ids ...uint64) error {
+	_, err := r.query.WithContext(ctx).User.Unscoped().Where(r.query.User.ID.In(ids...), r.query.User.DeletedAt.IsNotNull()).Delete()
+	return err
+}
+
 func (r *userRepository) List(ctx context.Context, query *model.UserQuery) ([]*model.User, int64, error) {
 	q := r.query.WithContext(ctx).User
 	// 构建查询条件
@@ -68,20 +133,29 @@ func (r *userRepository) List(ctx context.Context, query *model.UserQuery) ([]*m
 		if query.Nickname != "" {
 			q = q.Where(r.query.User.Nickname.Like("%" + query.Nickname + "%"))
 		}
+		if query.Phone != "" {
+			q = q.Where(r.query.User.Phone.Like("%" + query.Phone + "%"))
+		}
+		if query.Email != "" {
+			q = q.Where(r.query.User.Email.Like("%" + query.Email + "%"))
+		}
 		if query.Status != 0 {
 			q = q.Where(r.query.User.Status.Eq(query.Status))
 		}
 	}
 
-	// 统计总数
-	total, err := q.Count()
-	if err != nil {
-		return nil, 0, err
+	// 统计总数，导出时逐页查询不需要总数
+	var total int64
+	if !query.SkipCount {
+		var err error
+		if total, err = q.Count(); err != nil {
+			return nil, 0, err
+		}
 	}
 
-	// 分页查询
+	// 分页查询，按主键排序保证翻页时行顺序稳定
 	offset := (query.Page - 1) * query.PageSize
-	users, err := q.Offset(offset).Limit(query.PageSize).Find()
+	users, err := q.Order(r.query.User.ID).Offset(offset).Limit(query.PageSize).Find()
 	if err != nil {
 		return nil, 0, err
 	}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/internal/service"
+)
+
+type userIdentityRepository struct {
+	query *Query
+}
+
+func NewUserIdentityRepository(query *Query) service.UserIdentityRepository {
+	return &userIdentityRepository{query: query}
+}
+
+func (r *userIdentityRepository) Create(ctx context.Context, identity *model.UserIdentity) error {
+	return r.query.WithContext(ctx).UserIdentity.Create(identity)
+}
+
+func (r *userIdentityRepository) FindBySubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error) {
+	q := r.query.UserIdentity
+	identity, err := r.query.WithContext(ctx).UserIdentity.Where(q.Provider.Eq(provider), q.Subject.Eq(subject)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (r *userIdentityRepository) ExistsByUserID(ctx context.Context, userID uint64) (bool, error) {
+	count, err := r.query.WithContext(ctx).UserIdentity.Where(r.query.UserIdentity.UserID.Eq(userID)).Count()
+	return count > 0, err
+}
+
+func (r *userIdentityRepository) DeleteByUserIDs(ctx context.Context, userIDs ...uint64) error {
+	_, err := r.query.WithContext(ctx).UserIdentity.Where(r.query.UserIdentity.UserID.In(userIDs...)).Delete()
+	return err
+}
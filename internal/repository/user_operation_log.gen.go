@@ -0,0 +1,420 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+)
+
+func newUserOperationLog(db *gorm.DB, opts ...gen.DOOption) userOperationLog {
+	_userOperationLog := userOperationLog{}
+
+	_userOperationLog.userOperationLogDo.UseDB(db, opts...)
+	_userOperationLog.userOperationLogDo.UseModel(&model.UserOperationLog{})
+
+	tableName := _userOperationLog.userOperationLogDo.TableName()
+	_userOperationLog.ALL = field.NewAsterisk(tableName)
+	_userOperationLog.ID = field.NewUint64(tableName, "id")
+	_userOperationLog.Username = field.NewString(tableName, "username")
+	_userOperationLog.Method = field.NewString(tableName, "method")
+	_userOperationLog.Router = field.NewString(tableName, "router")
+	_userOperationLog.ServiceName = field.NewString(tableName, "service_name")
+	_userOperationLog.IP = field.NewString(tableName, "ip")
+	_userOperationLog.ImpersonatorID = field.NewUint64(tableName, "impersonator_id")
+	_userOperationLog.ImpersonatorName = field.NewString(tableName, "impersonator_name")
+	_userOperationLog.CreatedAt = field.NewTime(tableName, "created_at")
+	_userOperationLog.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_userOperationLog.Remark = field.NewString(tableName, "remark")
+
+	_userOperationLog.fillFieldMap()
+
+	return _userOperationLog
+}
+
+type userOperationLog struct {
+	userOperationLogDo
+
+	ALL              field.Asterisk
+	ID               field.Uint64
+	Username         field.String
+	Method           field.String
+	Router           field.String
+	ServiceName      field.String
+	IP               field.String
+	ImpersonatorID   field.Uint64
+	ImpersonatorName field.String
+	CreatedAt        field.Time
+	UpdatedAt        field.Time
+	Remark           field.String
+
+	fieldMap map[string]field.Expr
+}
+
+func (u userOperationLog) Table(newTableName string) *userOperationLog {
+	u.userOperationLogDo.UseTable(newTableName)
+	return u.updateTableName(newTableName)
+}
+
+func (u userOperationLog) As(alias string) *userOperationLog {
+	u.userOperationLogDo.DO = *(u.userOperationLogDo.As(alias).(*gen.DO))
+	return u.updateTableName(alias)
+}
+
+func (u *userOperationLog) updateTableName(table string) *userOperationLog {
+	u.ALL = field.NewAsterisk(table)
+	u.ID = field.NewUint64(table, "id")
+	u.Username = field.NewString(table, "username")
+	u.Method = field.NewString(table, "method")
+	u.Router = field.NewString(table, "router")
+	u.ServiceName = field.NewString(table, "service_name")
+	u.IP = field.NewString(table, "ip")
+	u.ImpersonatorID = field.NewUint64(table, "impersonator_id")
+	u.ImpersonatorName = field.NewString(table, "impersonator_name")
+	u.CreatedAt = field.NewTime(table, "created_at")
+	u.UpdatedAt = field.NewTime(table, "updated_at")
+	u.Remark = field.NewString(table, "remark")
+
+	u.fillFieldMap()
+
+	return u
+}
+
+func (u *userOperationLog) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := u.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (u *userOperationLog) fillFieldMap() {
+	u.fieldMap = make(map[string]field.Expr, 11)
+	u.fieldMap["id"] = u.ID
+	u.fieldMap["username"] = u.Username
+	u.fieldMap["method"] = u.Method
+	u.fieldMap["router"] = u.Router
+	u.fieldMap["service_name"] = u.ServiceName
+	u.fieldMap["ip"] = u.IP
+	u.fieldMap["impersonator_id"] = u.ImpersonatorID
+	u.fieldMap["impersonator_name"] = u.ImpersonatorName
+	u.fieldMap["created_at"] = u.CreatedAt
+	u.fieldMap["updated_at"] = u.UpdatedAt
+	u.fieldMap["remark"] = u.Remark
+}
+
+func (u userOperationLog) clone(db *gorm.DB) userOperationLog {
+	u.userOperationLogDo.ReplaceConnPool(db.Statement.ConnPool)
+	return u
+}
+
+func (u userOperationLog) replaceDB(db *gorm.DB) userOperationLog {
+	u.userOperationLogDo.ReplaceDB(db)
+	return u
+}
+
+type userOperationLogDo struct{ gen.DO }
+
+type IUserOperationLogDo interface {
+	gen.SubQuery
+	Debug() IUserOperationLogDo
+	WithContext(ctx context.Context) IUserOperationLogDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IUserOperationLogDo
+	WriteDB() IUserOperationLogDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IUserOperationLogDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IUserOperationLogDo
+	Not(conds ...gen.Condition) IUserOperationLogDo
+	Or(conds ...gen.Condition) IUserOperationLogDo
+	Select(conds ...field.Expr) IUserOperationLogDo
+	Where(conds ...gen.Condition) IUserOperationLogDo
+	Order(conds ...field.Expr) IUserOperationLogDo
+	Distinct(cols ...field.Expr) IUserOperationLogDo
+	Omit(cols ...field.Expr) IUserOperationLogDo
+	Join(table schema.Tabler, on ...field.Expr) IUserOperationLogDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IUserOperationLogDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IUserOperationLogDo
+	Group(cols ...field.Expr) IUserOperationLogDo
+	Having(conds ...gen.Condition) IUserOperationLogDo
+	Limit(limit int) IUserOperationLogDo
+	Offset(offset int) IUserOperationLogDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IUserOperationLogDo
+	Unscoped() IUserOperationLogDo
+	Create(values ...*model.UserOperationLog) error
+	CreateInBatches(values []*model.UserOperationLog, batchSize int) error
+	Save(values ...*model.UserOperationLog) error
+	First() (*model.UserOperationLog, error)
+	Take() (*model.UserOperationLog, error)
+	Last() (*model.UserOperationLog, error)
+	Find() ([]*model.UserOperationLog, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.UserOperationLog, err error)
+	FindInBatches(result *[]*model.UserOperationLog, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.UserOperationLog) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IUserOperationLogDo
+	Assign(attrs ...field.AssignExpr) IUserOperationLogDo
+	Joins(fields ...field.RelationField) IUserOperationLogDo
+	Preload(fields ...field.RelationField) IUserOperationLogDo
+	FirstOrInit() (*model.UserOperationLog, error)
+	FirstOrCreate() (*model.UserOperationLog, error)
+	FindByPage(offset int, limit int) (result []*model.UserOperationLog, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IUserOperationLogDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (u userOperationLogDo) Debug() IUserOperationLogDo {
+	return u.withDO(u.DO.Debug())
+}
+
+func (u userOperationLogDo) WithContext(ctx context.Context) IUserOperationLogDo {
+	return u.withDO(u.DO.WithContext(ctx))
+}
+
+func (u userOperationLogDo) ReadDB() IUserOperationLogDo {
+	return u.Clauses(dbresolver.Read)
+}
+
+func (u userOperationLogDo) WriteDB() IUserOperationLogDo {
+	return u.Clauses(dbresolver.Write)
+}
+
+func (u userOperationLogDo) Session(config *gorm.Session) IUserOperationLogDo {
+	return u.withDO(u.DO.Session(config))
+}
+
+func (u userOperationLogDo) Clauses(conds ...clause.Expression) IUserOperationLogDo {
+	return u.withDO(u.DO.Clauses(conds...))
+}
+
+func (u userOperationLogDo) Returning(value interface{}, columns ...string) IUserOperationLogDo {
+	return u.withDO(u.DO.Returning(value, columns...))
+}
+
+func (u userOperationLogDo) Not(conds ...gen.Condition) IUserOperationLogDo {
+	return u.withDO(u.DO.Not(conds...))
+}
+
+func (u userOperationLogDo) Or(conds ...gen.Condition) IUserOperationLogDo {
+	return u.withDO(u.DO.Or(conds...))
+}
+
+func (u userOperationLogDo) Select(conds ...field.Expr) IUserOperationLogDo {
+	return u.withDO(u.DO.Select(conds...))
+}
+
+func (u userOperationLogDo) Where(conds ...gen.Condition) IUserOperationLogDo {
+	return u.withDO(u.DO.Where(conds...))
+}
+
+func (u userOperationLogDo) Order(conds ...field.Expr) IUserOperationLogDo {
+	return u.withDO(u.DO.Order(conds...))
+}
+
+func (u userOperationLogDo) Distinct(cols ...field.Expr) IUserOperationLogDo {
+	return u.withDO(u.DO.Distinct(cols...))
+}
+
+func (u userOperationLogDo) Omit(cols ...field.Expr) IUserOperationLogDo {
+	return u.withDO(u.DO.Omit(cols...))
+}
+
+func (u userOperationLogDo) Join(table schema.Tabler, on ...field.Expr) IUserOperationLogDo {
+	return u.withDO(u.DO.Join(table, on...))
+}
+
+func (u userOperationLogDo) LeftJoin(table schema.Tabler, on ...field.Expr) IUserOperationLogDo {
+	return u.withDO(u.DO.LeftJoin(table, on...))
+}
+
+func (u userOperationLogDo) RightJoin(table schema.Tabler, on ...field.Expr) IUserOperationLogDo {
+	return u.withDO(u.DO.RightJoin(table, on...))
+}
+
+func (u userOperationLogDo) Group(cols ...field.Expr) IUserOperationLogDo {
+	return u.withDO(u.DO.Group(cols...))
+}
+
+func (u userOperationLogDo) Having(conds ...gen.Condition) IUserOperationLogDo {
+	return u.withDO(u.DO.Having(conds...))
+}
+
+func (u userOperationLogDo) Limit(limit int) IUserOperationLogDo {
+	return u.withDO(u.DO.Limit(limit))
+}
+
+func (u userOperationLogDo) Offset(offset int) IUserOperationLogDo {
+	return u.withDO(u.DO.Offset(offset))
+}
+
+func (u userOperationLogDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IUserOperationLogDo {
+	return u.withDO(u.DO.Scopes(funcs...))
+}
+
+func (u userOperationLogDo) Unscoped() IUserOperationLogDo {
+	return u.withDO(u.DO.Unscoped())
+}
+
+func (u userOperationLogDo) Create(values ...*model.UserOperationLog) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return u.DO.Create(values)
+}
+
+func (u userOperationLogDo) CreateInBatches(values []*model.UserOperationLog, batchSize int) error {
+	return u.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (u userOperationLogDo) Save(values ...*model.UserOperationLog) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return u.DO.Save(values)
+}
+
+func (u userOperationLogDo) First() (*model.UserOperationLog, error) {
+	if result, err := u.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.UserOperationLog), nil
+	}
+}
+
+func (u userOperationLogDo) Take() (*model.UserOperationLog, error) {
+	if result, err := u.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.UserOperationLog), nil
+	}
+}
+
+func (u userOperationLogDo) Last() (*model.UserOperationLog, error) {
+	if result, err := u.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.UserOperationLog), nil
+	}
+}
+
+func (u userOperationLogDo) Find() ([]*model.UserOperationLog, error) {
+	result, err := u.DO.Find()
+	return result.([]*model.UserOperationLog), err
+}
+
+func (u userOperationLogDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.UserOperationLog, err error) {
+	buf := make([]*model.UserOperationLog, 0, batchSize)
+	err = u.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (u userOperationLogDo) FindInBatches(result *[]*model.UserOperationLog, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return u.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (u userOperationLogDo) Attrs(attrs ...field.AssignExpr) IUserOperationLogDo {
+	return u.withDO(u.DO.Attrs(attrs...))
+}
+
+func (u userOperationLogDo) Assign(attrs ...field.AssignExpr) IUserOperationLogDo {
+	return u.withDO(u.DO.Assign(attrs...))
+}
+
+func (u userOperationLogDo) Joins(fields ...field.RelationField) IUserOperationLogDo {
+	for _, _f := range fields {
+		u = *u.withDO(u.DO.Joins(_f))
+	}
+	return &u
+}
+
+func (u userOperationLogDo) Preload(fields ...field.RelationField) IUserOperationLogDo {
+	for _, _f := range fields {
+		u = *u.withDO(u.DO.Preload(_f))
+	}
+	return &u
+}
+
+func (u userOperationLogDo) FirstOrInit() (*model.UserOperationLog, error) {
+	if result, err := u.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.UserOperationLog), nil
+	}
+}
+
+func (u userOperationLogDo) FirstOrCreate() (*model.UserOperationLog, error) {
+	if result, err := u.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.UserOperationLog), nil
+	}
+}
+
+func (u userOperationLogDo) FindByPage(offset int, limit int) (result []*model.UserOperationLog, count int64, err error) {
+	result, err = u.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = u.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (u userOperationLogDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = u.Count()
+	if err != nil {
+		return
+	}
+
+	err = u.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (u userOperationLogDo) Scan(result interface{}) (err error) {
+	return u.DO.Scan(result)
+}
+
+func (u userOperationLogDo) Delete(models ...*model.UserOperationLog) (result gen.ResultInfo, err error) {
+	return u.DO.Delete(models)
+}
+
+func (u *userOperationLogDo) withDO(do gen.Dao) *userOperationLogDo {
+	u.DO = *do.(*gen.DO)
+	return u
+}
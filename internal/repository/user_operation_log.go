@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/internal/service"
+)
+
+type userOperationLogRepository struct {
+	query *Query
+}
+
+func NewUserOperationLogRepository(query *Query) service.UserOperationLogRepository {
+	return &userOperationLogRepository{query: query}
+}
+
+func (r *userOperationLogRepository) Create(ctx context.Context, log *model.UserOperationLog) error {
+	return r.query.WithContext(ctx).UserOperationLog.Create(log)
+}
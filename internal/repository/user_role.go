@@ -25,6 +25,16 @@ func (r *userRoleRepository) DeleteByUserID(ctx context.Context, userID uint64)
 	return err
 }
 
+func (r *userRoleRepository) DeleteByUserIDs(ctx context.Context, userIDs ...uint64) error {
+	_, err := r.query.WithContext(ctx).UserRoles.Where(r.query.UserRoles.UserID.In(userIDs...)).Delete()
+	return err
+}
+
+func (r *userRoleRepository) DeleteByRoleIDs(ctx context.Context, roleIDs ...uint64) error {
+	_, err := r.query.WithContext(ctx).UserRoles.Where(r.query.UserRoles.RoleID.In(roleIDs...)).Delete()
+	return err
+}
+
 func (r *userRoleRepository) Create(ctx context.Context, userRoles ...*model.UserRoles) error {
 	return r.query.WithContext(ctx).UserRoles.Create(userRoles...)
 }
@@ -53,3 +63,7 @@ func (r *userRoleRepository) FindRolesByUserID(ctx context.Context, userID uint6
 	// 返回找到的角色和nil作为错误。
 	return roles, nil
 }
+
+func (r *userRoleRepository) FindByUserIDs(ctx context.Context, userIDs ...uint64) ([]*model.UserRoles, error) {
+	return r.query.WithContext(ctx).UserRoles.Where(r.query.UserRoles.UserID.In(userIDs...)).Find()
+}
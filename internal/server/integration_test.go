@@ -0,0 +1,679 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+
+	"github.com/wxlbd/gin-casbin-admin/internal/dto"
+	"github.com/wxlbd/gin-casbin-admin/internal/handler"
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/internal/repository"
+	"github.com/wxlbd/gin-casbin-admin/internal/server"
+	"github.com/wxlbd/gin-casbin-admin/internal/service"
+	"github.com/wxlbd/gin-casbin-admin/migrations"
+	"github.com/wxlbd/gin-casbin-admin/pkg/casbinx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
+	"github.com/wxlbd/gin-casbin-admin/pkg/gormx"
+	httpx "github.com/wxlbd/gin-casbin-admin/pkg/http"
+	"github.com/wxlbd/gin-casbin-admin/pkg/jwtx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"github.com/wxlbd/gin-casbin-admin/pkg/mailx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/migrate"
+	"github.com/wxlbd/gin-casbin-admin/pkg/oidcx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/otelx"
+)
+
+// 集成测试默认使用临时目录中的 SQLite 数据库，设置 INTEGRATION_DB_DRIVER 和 INTEGRATION_DB_DSN
+// 后可在空的 MySQL 或 PostgreSQL 数据库上运行同一套用例，结束时会回滚全部迁移
+const adminPassword = "admin123"
+
+func TestMain(m *testing.M) {
+	// casbin 模型文件按项目根目录的相对路径加载
+	if err := os.Chdir("../.."); err != nil {
+		panic(err)
+	}
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+type testApp struct {
+	engine *gin.Engine
+	db     *gorm.DB
+	redis  *miniredis.Miniredis
+	state  *httpx.State
+	logs   *observer.ObservedLogs
+}
+
+func newTestApp(t *testing.T) *testApp {
+	t.Helper()
+	cfg := &config.Config{
+		JWT: config.JWTConfig{
+			AccessSecret:      "integration-access-secret",
+			RefreshSecret:     "integration-refresh-secret",
+			AccessExpire:      time.Hour,
+			RefreshExpire:     24 * time.Hour,
+			Issuer:            "integration",
+			ImpersonateExpire: 10 * time.Minute,
+		},
+		Database: config.DatabaseConfig{
+			Driver: os.Getenv("INTEGRATION_DB_DRIVER"),
+			DSN:    os.Getenv("INTEGRATION_DB_DSN"),
+		},
+		Captcha: config.CaptchaConfig{Mode: "off"},
+	}
+	if cfg.Database.Driver == "" {
+		cfg.Database.Driver = "sqlite"
+		cfg.Database.DSN = filepath.Join(t.TempDir(), "integration.db")
+	}
+	core, logs := observer.New(zap.InfoLevel)
+	logger := &log.Logger{Logger: zap.New(core)}
+	ctx := context.Background()
+
+	db, closeDB := gormx.NewDB(cfg, logger)
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if cfg.Database.Driver != "sqlite" {
+			if _, err := migrator.To(ctx, 0); err != nil {
+				t.Errorf("roll back migrations: %v", err)
+			}
+		}
+		closeDB()
+	})
+
+	mr := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = redisClient.Close() })
+
+	enforcer, err := casbinx.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := repository.NewRepository(db)
+	if _, err := service.NewSeeder(repo, enforcer).Seed(ctx, service.SeedOptions{AdminPassword: adminPassword}); err != nil {
+		t.Fatal(err)
+	}
+	authenticators, err := service.NewAuthenticators(cfg, repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watcher := config.NewWatcher("", cfg, logger.Logger)
+	state := httpx.NewState()
+	jwt := jwtx.New(cfg, watcher, redisClient)
+	svc := service.NewService(cfg, watcher, logger, repo, enforcer, jwt, redisClient, mailx.NewLogMailer(logger), authenticators, oidcx.New(cfg), state)
+	engine := server.NewServerHTTP(cfg, watcher, logger, jwt, handler.NewHandler(svc, cfg, logger), enforcer, svc)
+	return &testApp{engine: engine, db: db, redis: mr, state: state, logs: logs}
+}
+
+type apiResponse struct {
+	status  int
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func (a *testApp) do(t *testing.T, method, path, token string, body any) *apiResponse {
+	t.Helper()
+	var reader *bytes.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(b)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	a.engine.ServeHTTP(w, req)
+	resp := &apiResponse{status: w.Code}
+	if err := json.Unmarshal(w.Body.Bytes(), resp); err != nil {
+		t.Fatalf("%s %s: invalid response %q", method, path, w.Body.String())
+	}
+	return resp
+}
+
+// ok 请求并要求返回成功，data 不为 nil 时解析响应数据
+func (a *testApp) ok(t *testing.T, method, path, token string, body, data any) {
+	t.Helper()
+	resp := a.do(t, method, path, token, body)
+	if resp.status != http.StatusOK || resp.Code != 200 {
+		t.Fatalf("%s %s: status %d code %d message %q", method, path, resp.status, resp.Code, resp.Message)
+	}
+	if data != nil {
+		if err := json.Unmarshal(resp.Data, data); err != nil {
+			t.Fatalf("%s %s: decode data %s: %v", method, path, resp.Data, err)
+		}
+	}
+}
+
+// upload 以 multipart 表单上传文件，字段名为 file
+func (a *testApp) upload(t *testing.T, path, token, filename, content string) *apiResponse {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fw.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	a.engine.ServeHTTP(w, req)
+	resp := &apiResponse{status: w.Code}
+	if err := json.Unmarshal(w.Body.Bytes(), resp); err != nil {
+		t.Fatalf("POST %s: invalid response %q", path, w.Body.String())
+	}
+	return resp
+}
+
+func (a *testApp) login(t *testing.T, username, password string) string {
+	t.Helper()
+	var data struct {
+		AccessToken string `json:"accessToken"`
+	}
+	a.ok(t, http.MethodPost, "/api/auth/login", "", map[string]string{"username": username, "password": password}, &data)
+	return data.AccessToken
+}
+
+func (a *testApp) menuID(t *testing.T, auths string) int64 {
+	t.Helper()
+	var menu model.SysMenu
+	if err := a.db.Where("auths = ?", auths).First(&menu).Error; err != nil {
+		t.Fatalf("menu %s: %v", auths, err)
+	}
+	return menu.ID
+}
+
+func TestIntegration(t *testing.T) {
+	app := newTestApp(t)
+	admin := app.login(t, "admin", adminPassword)
+
+	t.Run("profile and settings", func(t *testing.T) {
+		var user struct {
+			Username string `json:"username"`
+		}
+		app.ok(t, http.MethodGet, "/api/user/profile", admin, nil, &user)
+		if user.Username != "admin" {
+			t.Errorf("profile username = %q", user.Username)
+		}
+		// 后台设置保存在 JSON 列中
+		app.ok(t, http.MethodPut, "/api/user/profile/settings", admin, map[string]any{"app": map[string]any{"colorMode": "dark"}}, nil)
+		var setting struct {
+			App struct {
+				ColorMode string `json:"colorMode"`
+			} `json:"app"`
+		}
+		app.ok(t, http.MethodGet, "/api/user/profile/settings", admin, nil, &setting)
+		if setting.App.ColorMode != "dark" {
+			t.Errorf("colorMode = %q, want dark", setting.App.ColorMode)
+		}
+	})
+
+	t.Run("seeded dictionaries", func(t *testing.T) {
+		var items []struct {
+			Label string `json:"label"`
+			Value string `json:"value"`
+		}
+		app.ok(t, http.MethodGet, "/api/dict/gender", admin, nil, &items)
+		if len(items) != 3 || items[0].Label != "男" {
+			t.Errorf("gender items = %+v", items)
+		}
+	})
+
+	t.Run("role permissions", func(t *testing.T) {
+		app.ok(t, http.MethodPost, "/api/system/role", admin, map[string]any{"name": "审计员", "code": "auditor", "status": 1}, nil)
+		var role model.Role
+		if err := app.db.Where("code = ?", "auditor").First(&role).Error; err != nil {
+			t.Fatal(err)
+		}
+		if role.CreatedBy == 0 {
+			t.Errorf("role created_by should be filled from the operator")
+		}
+		app.ok(t, http.MethodPut, fmt.Sprintf("/api/system/role/%d/menus", role.ID), admin, []int64{app.menuID(t, "system:user:list")}, nil)
+
+		app.ok(t, http.MethodPost, "/api/system/user", admin, map[string]any{"username": "alice", "nickname": "Alice", "status": 1}, nil)
+		var user model.User
+		if err := app.db.Where("username = ?", "alice").First(&user).Error; err != nil {
+			t.Fatal(err)
+		}
+		app.ok(t, http.MethodPut, fmt.Sprintf("/api/system/user/%d/password", user.ID), admin, map[string]string{"password": "alice123"}, nil)
+		app.ok(t, http.MethodPut, fmt.Sprintf("/api/system/user/%d/roles", user.ID), admin, map[string]any{"roleIds": []uint64{role.ID}}, nil)
+
+		alice := app.login(t, "alice", "alice123")
+		var roles []struct {
+			Code string `json:"code"`
+		}
+		app.ok(t, http.MethodGet, "/api/user/profile/roles", alice, nil, &roles)
+		if len(roles) != 1 || roles[0].Code != "auditor" {
+			t.Errorf("alice roles = %+v", roles)
+		}
+		app.ok(t, http.MethodGet, "/api/system/user", alice, nil, nil)
+		if resp := app.do(t, http.MethodGet, "/api/system/role", alice, nil); resp.status != http.StatusForbidden {
+			t.Errorf("role list without permission: status %d, want 403", resp.status)
+		}
+	})
+
+	// 详情权限的策略为 GET .../:id，不能授权同一分组下的静态路由
+	t.Run("detail permission does not grant static routes", func(t *testing.T) {
+		app.ok(t, http.MethodPost, "/api/system/role", admin, map[string]any{"name": "查看员", "code": "viewer", "status": 1}, nil)
+		var role model.Role
+		if err := app.db.Where("code = ?", "viewer").First(&role).Error; err != nil {
+			t.Fatal(err)
+		}
+		app.ok(t, http.MethodPut, fmt.Sprintf("/api/system/role/%d/menus", role.ID), admin, []int64{
+			app.menuID(t, "system:user:detail"),
+			app.menuID(t, "system:role:detail"),
+			app.menuID(t, "system:dict-type:detail"),
+			app.menuID(t, "system:dict-data:detail"),
+		}, nil)
+		app.ok(t, http.MethodPost, "/api/system/user", admin, map[string]any{"username": "victor", "nickname": "Victor", "status": 1}, nil)
+		var user model.User
+		if err := app.db.Where("username = ?", "victor").First(&user).Error; err != nil {
+			t.Fatal(err)
+		}
+		app.ok(t, http.MethodPut, fmt.Sprintf("/api/system/user/%d/password", user.ID), admin, map[string]string{"password": "victor123"}, nil)
+		app.ok(t, http.MethodPut, fmt.Sprintf("/api/system/user/%d/roles", user.ID), admin, map[string]any{"roleIds": []uint64{role.ID}}, nil)
+		victor := app.login(t, "victor", "victor123")
+
+		app.ok(t, http.MethodGet, fmt.Sprintf("/api/system/user/%d", user.ID), victor, nil, nil)
+		app.ok(t, http.MethodGet, fmt.Sprintf("/api/system/role/%d", role.ID), victor, nil, nil)
+		for _, path := range []string{
+			"/api/system/user/export",
+			"/api/system/role/export",
+			"/api/system/dict-type/export",
+			"/api/system/dict-data/export",
+			"/api/system/user/recycle",
+			"/api/system/role/recycle",
+			"/api/system/dict-type/recycle",
+			"/api/system/dict-data/recycle",
+		} {
+			if resp := app.do(t, http.MethodGet, path, victor, nil); resp.status != http.StatusForbidden {
+				t.Errorf("GET %s with detail permission only: status %d, want 403", path, resp.status)
+			}
+		}
+	})
+
+	// 导入时的角色列与单独分配角色使用相同的权限，超级管理员角色只能由超级管理员分配
+	t.Run("user import roles", func(t *testing.T) {
+		app.ok(t, http.MethodPost, "/api/system/role", admin, map[string]any{"name": "导入员", "code": "importer", "status": 1}, nil)
+		var role model.Role
+		if err := app.db.Where("code = ?", "importer").First(&role).Error; err != nil {
+			t.Fatal(err)
+		}
+		grant := func(auths ...string) {
+			ids := make([]int64, len(auths))
+			for i, a := range auths {
+				ids[i] = app.menuID(t, a)
+			}
+			app.ok(t, http.MethodPut, fmt.Sprintf("/api/system/role/%d/menus", role.ID), admin, ids, nil)
+		}
+		grant("system:user:import")
+		app.ok(t, http.MethodPost, "/api/system/user", admin, map[string]any{"username": "ivan", "nickname": "Ivan", "status": 1}, nil)
+		var user model.User
+		if err := app.db.Where("username = ?", "ivan").First(&user).Error; err != nil {
+			t.Fatal(err)
+		}
+		app.ok(t, http.MethodPut, fmt.Sprintf("/api/system/user/%d/password", user.ID), admin, map[string]string{"password": "ivan123"}, nil)
+		app.ok(t, http.MethodPut, fmt.Sprintf("/api/system/user/%d/roles", user.ID), admin, map[string]any{"roleIds": []uint64{role.ID}}, nil)
+		ivan := app.login(t, "ivan", "ivan123")
+
+		importUsers := func(content string) dto.UserImportResponse {
+			t.Helper()
+			resp := app.upload(t, "/api/system/user/import?dry_run=true", ivan, "users.csv", content)
+			if resp.status != http.StatusOK || resp.Code != 200 {
+				t.Fatalf("import: status %d code %d message %q", resp.status, resp.Code, resp.Message)
+			}
+			var result dto.UserImportResponse
+			if err := json.Unmarshal(resp.Data, &result); err != nil {
+				t.Fatal(err)
+			}
+			return result
+		}
+		content := "username,password,roles\nimp1,secret1,\nimp2,secret2,auditor\nimp3,secret3,SuperAdmin\n"
+		if result := importUsers(content); result.Success != 1 || result.Failed != 2 {
+			t.Errorf("import without set:roles = %+v", result)
+		}
+		grant("system:user:import", "system:user:set:roles")
+		result := importUsers(content)
+		if result.Success != 2 || result.Failed != 1 || result.Errors[0].Username != "imp3" {
+			t.Errorf("import with set:roles = %+v", result)
+		}
+	})
+
+	t.Run("recycle bin", func(t *testing.T) {
+		app.ok(t, http.MethodPost, "/api/system/dict-type", admin, map[string]any{"code": "color", "name": "颜色", "status": 1}, nil)
+		var dictType model.DictType
+		if err := app.db.Where("code = ?", "color").First(&dictType).Error; err != nil {
+			t.Fatal(err)
+		}
+		app.ok(t, http.MethodDelete, fmt.Sprintf("/api/system/dict-type/%d", dictType.ID), admin, nil, nil)
+		var list struct {
+			Total int64 `json:"total"`
+		}
+		app.ok(t, http.MethodGet, "/api/system/dict-type/recycle", admin, nil, &list)
+		if list.Total != 1 {
+			t.Errorf("recycle total = %d, want 1", list.Total)
+		}
+		app.ok(t, http.MethodPut, fmt.Sprintf("/api/system/dict-type/recycle/%d", dictType.ID), admin, nil, nil)
+		app.ok(t, http.MethodGet, fmt.Sprintf("/api/system/dict-type/%d", dictType.ID), admin, nil, nil)
+	})
+
+	t.Run("impersonation", func(t *testing.T) {
+		var alice, root model.User
+		if err := app.db.Where("username = ?", "alice").First(&alice).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := app.db.Where("username = ?", "admin").First(&root).Error; err != nil {
+			t.Fatal(err)
+		}
+		impersonate := func() string {
+			var data struct {
+				AccessToken string `json:"accessToken"`
+			}
+			app.ok(t, http.MethodPost, fmt.Sprintf("/api/system/user/%d/impersonate", alice.ID), admin, nil, &data)
+			return data.AccessToken
+		}
+
+		// 模拟期间的请求写入操作日志并标记发起模拟的管理员
+		token := impersonate()
+		app.ok(t, http.MethodGet, "/api/system/user", token, nil, nil)
+		var entry model.UserOperationLog
+		if err := app.db.Where("router = ?", "/api/system/user").Last(&entry).Error; err != nil {
+			t.Fatalf("operation log not written: %v", err)
+		}
+		if entry.Username != "alice" || entry.ImpersonatorID == nil || *entry.ImpersonatorID != root.ID || entry.ServiceName != "system:user:list" {
+			t.Errorf("operation log = %+v", entry)
+		}
+
+		// 模拟期间的数据变更记录实际操作的管理员
+		app.ok(t, http.MethodPut, "/api/user/profile", token, map[string]string{"nickname": "Alice2"}, nil)
+		if err := app.db.First(&alice, alice.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if alice.Nickname != "Alice2" || alice.UpdatedBy != root.ID {
+			t.Errorf("updated_by = %d, want impersonator %d", alice.UpdatedBy, root.ID)
+		}
+
+		// 模拟令牌不能代替被模拟用户授权第三方应用
+		if resp := app.do(t, http.MethodPost, "/api/oauth/authorize", token, map[string]any{"client_id": "c", "redirect_uri": "https://example.com/cb", "response_type": "code", "approve": true}); resp.Code != int(errors.Forbidden) {
+			t.Errorf("oauth approve while impersonating: code %d message %q", resp.Code, resp.Message)
+		}
+
+		// 服务端模拟记录被清除后，仅凭模拟令牌不能换回管理员令牌
+		for _, key := range app.redis.Keys() {
+			if strings.HasPrefix(key, "token:impersonation:") {
+				app.redis.Del(key)
+			}
+		}
+		if resp := app.do(t, http.MethodPost, "/api/user/impersonate/exit", token, nil); resp.Code != int(errors.TokenInvalid) {
+			t.Errorf("exit without record: code %d message %q", resp.Code, resp.Message)
+		}
+
+		token = impersonate()
+		app.ok(t, http.MethodPost, "/api/user/impersonate/exit", token, nil, nil)
+		if resp := app.do(t, http.MethodPost, "/api/user/impersonate/exit", token, nil); resp.Code == 200 {
+			t.Error("impersonation token can be exchanged twice")
+		}
+	})
+
+	t.Run("forgot password ignores email case", func(t *testing.T) {
+		if err := app.db.Model(&model.User{}).Where("username = ?", "victor").Update("email", "Victor@Example.com").Error; err != nil {
+			t.Fatal(err)
+		}
+		app.ok(t, http.MethodPost, "/api/auth/password/forgot", "", map[string]string{"email": "victor@example.com"}, nil)
+		var tokens int
+		for _, key := range app.redis.Keys() {
+			if strings.HasPrefix(key, "password:reset:token:") {
+				tokens++
+			}
+		}
+		if tokens != 1 {
+			t.Errorf("reset tokens = %d, want 1", tokens)
+		}
+		// 限流计数随第一次请求设置过期时间
+		if ttl := app.redis.TTL("password:reset:limit:victor@example.com"); ttl <= 0 {
+			t.Errorf("rate limit ttl = %v", ttl)
+		}
+	})
+
+	t.Run("oidc callback requires state cookie", func(t *testing.T) {
+		// 没有发起登录时写入的 Cookie，即使 state 有效也拒绝回调
+		resp := app.do(t, http.MethodGet, "/api/auth/oidc/callback?code=c&state=s", "", nil)
+		if resp.Code != int(errors.TokenInvalid) {
+			t.Errorf("callback code = %d message %q, want %d", resp.Code, resp.Message, errors.TokenInvalid)
+		}
+	})
+
+	t.Run("tracing", func(t *testing.T) {
+		var buf bytes.Buffer
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(&buf))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := otelx.New(&config.Config{}); err != nil {
+			t.Fatal(err)
+		}
+		tp := otelx.NewTracerProvider(config.TracingConfig{}, sdktrace.WithSyncer(exporter))
+		otel.SetTracerProvider(tp)
+		t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+		// 延续请求头中的上游链路
+		const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		req := httptest.NewRequest(http.MethodGet, "/api/system/user", nil)
+		req.Header.Set("Authorization", "Bearer "+admin)
+		req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+		w := httptest.NewRecorder()
+		app.engine.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status %d", w.Code)
+		}
+
+		spans := make(map[string]string)
+		dec := json.NewDecoder(&buf)
+		for dec.More() {
+			var span struct {
+				Name        string
+				SpanContext struct{ TraceID string }
+			}
+			if err := dec.Decode(&span); err != nil {
+				t.Fatal(err)
+			}
+			spans[span.Name] = span.SpanContext.TraceID
+		}
+		for _, name := range []string{"/api/system/user", "middleware.JWTAuth", "middleware.Casbin", "UserService.GetUserRoles", "gorm.query"} {
+			if got, ok := spans[name]; !ok || got != traceID {
+				t.Errorf("span %s trace id = %q, want %s (spans: %v)", name, got, traceID, spans)
+			}
+		}
+	})
+
+	t.Run("request id", func(t *testing.T) {
+		send := func(requestID string) (*httptest.ResponseRecorder, *apiResponse) {
+			req := httptest.NewRequest(http.MethodGet, "/api/system/user", nil)
+			req.Header.Set("Authorization", "Bearer "+admin)
+			if requestID != "" {
+				req.Header.Set("X-Request-ID", requestID)
+			}
+			w := httptest.NewRecorder()
+			app.engine.ServeHTTP(w, req)
+			var resp struct {
+				apiResponse
+				RequestID string `json:"requestId"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if got := w.Header().Get("X-Request-ID"); got == "" || got != resp.RequestID {
+				t.Errorf("response header request id %q, body %q", got, resp.RequestID)
+			}
+			return w, &resp.apiResponse
+		}
+
+		// 沿用上游的请求ID，同一请求的请求日志和 SQL 日志均带有该ID
+		const requestID = "integration-request-1"
+		if w, _ := send(requestID); w.Header().Get("X-Request-ID") != requestID {
+			t.Fatalf("request id = %q, want %s", w.Header().Get("X-Request-ID"), requestID)
+		}
+		messages := make(map[string]bool)
+		for _, entry := range app.logs.FilterField(zap.String("request_id", requestID)).All() {
+			messages[entry.Message] = true
+		}
+		if !messages["Request completed"] || !messages["trace"] {
+			t.Errorf("logs with request id = %v, want request and SQL logs", messages)
+		}
+
+		// 没有或不合法时生成新的ID
+		w1, _ := send("")
+		w2, _ := send("bad id\r\n")
+		if id1, id2 := w1.Header().Get("X-Request-ID"), w2.Header().Get("X-Request-ID"); id1 == id2 || strings.Contains(id2, " ") {
+			t.Errorf("generated request ids %q %q", id1, id2)
+		}
+	})
+
+	t.Run("password change revokes other sessions", func(t *testing.T) {
+		// 其他会话的令牌与修改密码在同一秒内签发，同样需要吊销
+		other := app.login(t, "ivan", "ivan123")
+		current := app.login(t, "ivan", "ivan123")
+		var data struct {
+			AccessToken string `json:"accessToken"`
+		}
+		app.ok(t, http.MethodPut, "/api/user/profile/password", current, map[string]string{"old_password": "ivan123", "new_password": "ivan456"}, &data)
+		if resp := app.do(t, http.MethodGet, "/api/user/profile", other, nil); resp.status == http.StatusOK && resp.Code == 200 {
+			t.Error("other session token should be revoked after password change")
+		}
+		app.ok(t, http.MethodGet, "/api/user/profile", data.AccessToken, nil, nil)
+	})
+
+	t.Run("logout revokes token", func(t *testing.T) {
+		token := app.login(t, "admin", adminPassword)
+		app.ok(t, http.MethodPost, "/api/auth/logout", token, nil, nil)
+		if resp := app.do(t, http.MethodGet, "/api/user/profile", token, nil); resp.status == http.StatusOK && resp.Code == 200 {
+			t.Errorf("token should be rejected after logout")
+		}
+	})
+
+	t.Run("health endpoints", func(t *testing.T) {
+		app.ok(t, http.MethodGet, "/healthz", "", nil, nil)
+		var version struct {
+			Version   string `json:"version"`
+			GoVersion string `json:"goVersion"`
+		}
+		app.ok(t, http.MethodGet, "/version", "", nil, &version)
+		if version.Version != "dev" || version.GoVersion == "" {
+			t.Errorf("version = %+v", version)
+		}
+
+		var ready dto.ReadinessResponse
+		app.ok(t, http.MethodGet, "/readyz", "", nil, &ready)
+		for _, name := range []string{"database", "redis", "casbin"} {
+			if check := ready.Checks[name]; check == nil || check.Status != dto.HealthStatusOK {
+				t.Errorf("readyz check %s = %+v", name, check)
+			}
+		}
+
+		app.redis.SetError("redis down")
+		resp := app.do(t, http.MethodGet, "/readyz", "", nil)
+		app.redis.SetError("")
+		if resp.status != http.StatusServiceUnavailable {
+			t.Fatalf("readyz with redis down: status %d, want 503", resp.status)
+		}
+		if err := json.Unmarshal(resp.Data, &ready); err != nil {
+			t.Fatal(err)
+		}
+		if ready.Checks["redis"].Status != dto.HealthStatusFail || ready.Checks["database"].Status != dto.HealthStatusOK {
+			t.Errorf("readyz checks with redis down = %+v %+v", ready.Checks["redis"], ready.Checks["database"])
+		}
+		// 不返回原始错误
+		if ready.Checks["redis"].Reason != "check_failed" || strings.Contains(string(resp.Data), "redis down") {
+			t.Errorf("readyz leaks error details: %s", resp.Data)
+		}
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		// 登录失败同样计数
+		if resp := app.do(t, http.MethodPost, "/api/auth/login", "", map[string]string{"username": "admin", "password": "wrong"}); resp.Code == 200 {
+			t.Fatalf("login with wrong password should fail")
+		}
+		// 非标准方法请求未匹配的路由
+		app.engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("RANDOMX", "/nope", nil))
+		// httptest 请求的来源地址 192.0.2.1 不在默认允许的网段内
+		w := httptest.NewRecorder()
+		app.engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("metrics from public address: status %d, want 403", w.Code)
+		}
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.RemoteAddr = "127.0.0.1:40000"
+		req.Header.Set("X-Forwarded-For", "192.0.2.1")
+		w = httptest.NewRecorder()
+		app.engine.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("metrics: status %d", w.Code)
+		}
+		body := w.Body.String()
+		for _, want := range []string{
+			`admin_http_requests_total{method="GET",permission="system:user:list",route="/api/system/user",status="200"}`,
+			`admin_http_request_duration_seconds_bucket{method="PUT",permission="system:user:set:password",route="/api/system/user/:id/password",status="200"`,
+			`admin_casbin_decisions_total{permission="system:role:list",result="deny"}`,
+			`admin_auth_logins_total{method="password",result="success"}`,
+			`admin_auth_logins_total{method="password",result="failure"}`,
+			`admin_db_query_duration_seconds_bucket{operation="query",status="ok",table="user"`,
+			`admin_http_requests_total{method="other",permission="",route="unmatched",status="404"}`,
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("metrics missing %s", want)
+			}
+		}
+		if strings.Contains(body, "RANDOMX") {
+			t.Error("metrics use the client supplied method as a label")
+		}
+	})
+
+	// 停机后所有就绪检查均失败，放在最后执行
+	t.Run("readiness during drain", func(t *testing.T) {
+		app.ok(t, http.MethodGet, "/readyz", "", nil, nil)
+		app.state.Drain()
+		if resp := app.do(t, http.MethodGet, "/readyz", "", nil); resp.status != http.StatusServiceUnavailable {
+			t.Errorf("readyz while draining: status %d, want 503", resp.status)
+		}
+	})
+}
@@ -10,10 +10,13 @@ import (
 	"github.com/wxlbd/gin-casbin-admin/pkg/config"
 	"github.com/wxlbd/gin-casbin-admin/pkg/jwtx"
 	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"github.com/wxlbd/gin-casbin-admin/pkg/metrics"
+	"github.com/wxlbd/gin-casbin-admin/pkg/otelx"
 )
 
 func NewServerHTTP(
 	cfg *config.Config,
+	watcher *config.Watcher,
 	logger *log.Logger,
 	jwt *jwtx.JWT,
 	handler *handler.Handler,
@@ -24,11 +27,21 @@ func NewServerHTTP(
 		gin.SetMode(gin.ReleaseMode)
 	}
 	r := gin.Default()
+	// 以 *gin.Context 作为 context.Context 传给服务层时，可以取到请求上下文中的值和取消信号
+	r.ContextWithFallback = true
 	r.Use(
-		middleware.CORSMiddleware(),
+		middleware.Tracing(otelx.ServiceName(cfg)),
+		middleware.RequestID(logger),
+		middleware.Metrics(),
+		middleware.CORSMiddleware(cfg, watcher),
 		middleware.RequestLogger(logger),
+		middleware.OperationLog(svc, logger),
 		middleware.ErrorHandler(),
 	)
+	r.GET("/healthz", handler.Health().Live)
+	r.GET("/readyz", handler.Health().Ready)
+	r.GET("/version", handler.Health().Version)
+	r.GET("/metrics", middleware.AllowCIDRs(cfg.Metrics.AllowCIDRs), gin.WrapH(metrics.Handler()))
 	api := r.Group("api")
 	{
 		auth := api.Group("auth")
@@ -37,6 +50,15 @@ func NewServerHTTP(
 		auth.POST("/refresh-token", handler.User().RefreshToken)
 		auth.POST("/logout", handler.User().Logout)
 		auth.GET("/captcha", handler.Captcha().Generate)
+		auth.POST("/password/forgot", handler.User().ForgotPassword)
+		auth.POST("/password/reset", handler.User().ResetPasswordByToken)
+		auth.GET("/oidc/login", handler.OIDC().Login)
+		auth.GET("/oidc/callback", handler.OIDC().Callback)
+
+		// OAuth2 授权服务，令牌端点由客户端凭证认证，用户信息端点使用签发给客户端的令牌
+		oauth := api.Group("oauth")
+		oauth.POST("/token", handler.OAuth().Token)
+		oauth.GET("/userinfo", middleware.OAuthTokenAuth(jwt), handler.OAuth().UserInfo)
 
 		// 需要JWT认证的接口
 		jwtGroup := api.Group("")
@@ -45,11 +67,25 @@ func NewServerHTTP(
 			profile := jwtGroup.Group("user/profile")
 			{
 				profile.GET("", handler.User().Current)
+				profile.PUT("", handler.User().UpdateProfile)
+				profile.PUT("password", handler.User().UpdatePassword)
+				profile.PUT("avatar", handler.User().UpdateAvatar)
+				profile.GET("settings", handler.User().GetSettings)
+				profile.PUT("settings", handler.User().UpdateSettings)
 				profile.GET("menus", handler.SysMenu().GetUserMenuTree)
 				// profile.GET("/menu/tree", handler.Menu().GetMenuTree)
 				profile.GET("roles", handler.User().GetCurrentUserRoles)
 			}
+			// 退出模拟登录使用模拟令牌调用，被模拟用户不一定拥有用户管理权限
+			jwtGroup.POST("user/impersonate/exit", handler.User().ExitImpersonation)
+			// 导入错误报告只能由导入人下载
+			jwtGroup.GET("system/user/import/report/:id", handler.User().ImportReport)
 			jwtGroup.GET("system/role/all", handler.Role().GetAllRoles)
+			// 字典数据供所有登录用户的下拉框等场景使用，只返回启用的数据
+			jwtGroup.GET("dict", handler.Dict().GetDictDataByTypes)
+			jwtGroup.GET("dict/:typeCode", handler.Dict().GetDictDataByType)
+			jwtGroup.GET("oauth/authorize", handler.OAuth().Authorize)
+			jwtGroup.POST("oauth/authorize", handler.OAuth().Approve)
 		}
 
 		// 需要完整权限控制的接口
@@ -65,26 +101,36 @@ func NewServerHTTP(
 			// 用户管理 system:user:xxx
 			userGroup := sys.Group("user")
 			{
-				userGroup.GET("", handler.User().List)                      // system:user:list
-				userGroup.POST("", handler.User().Create)                   // system:user:create
-				userGroup.PUT("/:id", handler.User().Update)                // system:user:update
-				userGroup.DELETE("/:ids", handler.User().Delete)            // system:user:delete
-				userGroup.GET("/:id", handler.User().Detail)                // system:user:detail
-				userGroup.GET("/:id/roles", handler.User().GerUserRoles)    // system:user:get:roles
-				userGroup.PUT(":id/password", handler.User().ResetPassword) // system:user:set:password
-				userGroup.PUT(":id/roles", handler.User().AssignRoles)      // system:user:set:roles
+				userGroup.GET("", handler.User().List)                        // system:user:list
+				userGroup.GET("/export", handler.User().Export)               // system:user:export
+				userGroup.POST("/import", handler.User().Import)              // system:user:import
+				userGroup.POST("", handler.User().Create)                     // system:user:create
+				userGroup.PUT("/:id", handler.User().Update)                  // system:user:update
+				userGroup.DELETE("/:ids", handler.User().Delete)              // system:user:delete
+				userGroup.GET("/:id", handler.User().Detail)                  // system:user:detail
+				userGroup.GET("/:id/roles", handler.User().GerUserRoles)      // system:user:get:roles
+				userGroup.PUT(":id/password", handler.User().ResetPassword)   // system:user:set:password
+				userGroup.PUT(":id/roles", handler.User().AssignRoles)        // system:user:set:roles
+				userGroup.POST(":id/impersonate", handler.User().Impersonate) // system:user:impersonate
+				userGroup.GET("/recycle", handler.User().Recycle)             // system:user:recycle
+				userGroup.PUT("/recycle/:ids", handler.User().Restore)        // system:user:restore
+				userGroup.DELETE("/recycle/:ids", handler.User().Purge)       // system:user:purge
 			}
 
 			// 角色管理 permission:role:xxx
 			roleGroup := sys.Group("role")
 			{
 				roleGroup.GET("", handler.Role().List)                           // system:role:list
+				roleGroup.GET("/export", handler.Role().Export)                  // system:role:export
 				roleGroup.POST("", handler.Role().Create)                        // system:role:create
 				roleGroup.PUT("/:id", handler.Role().Update)                     // system:role:update
 				roleGroup.DELETE("/:ids", handler.Role().Delete)                 // system:role:delete
 				roleGroup.GET("/:id", handler.Role().Detail)                     // system:role:detail
 				roleGroup.GET("/:id/menus", handler.Role().GetPermittedMenus)    // system:role:get:menus
 				roleGroup.PUT("/:id/menus", handler.Role().AssignRoleMenusByIDs) // system:role:set:menus
+				roleGroup.GET("/recycle", handler.Role().Recycle)                // system:role:recycle
+				roleGroup.PUT("/recycle/:ids", handler.Role().Restore)           // system:role:restore
+				roleGroup.DELETE("/recycle/:ids", handler.Role().Purge)          // system:role:purge
 			}
 
 			// 菜单管理 permission:menu:xxx
@@ -94,8 +140,23 @@ func NewServerHTTP(
 				menuGroup.PUT("/:id", handler.SysMenu().Update)                // system:menu:update
 				menuGroup.DELETE("/:ids", handler.SysMenu().Delete)            // system:menu:delete
 				menuGroup.GET("", handler.SysMenu().List)                      // system:menu:list
+				menuGroup.GET("/export", handler.SysMenu().Export)             // system:menu:export
 				menuGroup.GET("/tree", handler.SysMenu().GetMenuTree)          // system:menu:tree
 				menuGroup.GET("/user-tree", handler.SysMenu().GetUserMenuTree) // system:menu:user-tree
+				menuGroup.GET("/recycle", handler.SysMenu().Recycle)           // system:menu:recycle
+				menuGroup.PUT("/recycle/:ids", handler.SysMenu().Restore)      // system:menu:restore
+				menuGroup.DELETE("/recycle/:ids", handler.SysMenu().Purge)     // system:menu:purge
+			}
+
+			// 应用管理 system:oauth-client:xxx
+			oauthClient := sys.Group("oauth-client")
+			{
+				oauthClient.GET("", handler.OAuth().ListClient)                   // system:oauth-client:list
+				oauthClient.POST("", handler.OAuth().CreateClient)                // system:oauth-client:create
+				oauthClient.PUT("/:id", handler.OAuth().UpdateClient)             // system:oauth-client:update
+				oauthClient.DELETE("/:ids", handler.OAuth().DeleteClient)         // system:oauth-client:delete
+				oauthClient.GET("/:id", handler.OAuth().GetClient)                // system:oauth-client:detail
+				oauthClient.PUT("/:id/secret", handler.OAuth().ResetClientSecret) // system:oauth-client:set:secret
 			}
 
 			// 字典管理
@@ -103,22 +164,31 @@ func NewServerHTTP(
 				// 字典类型管理
 				dictType := sys.Group("dict-type")
 				{
-					dictType.POST("", handler.Dict().CreateDictType)        // system:dict:type:create
-					dictType.PUT("/:id", handler.Dict().UpdateDictType)     // system:dict:type:update
-					dictType.DELETE("/:ids", handler.Dict().DeleteDictType) // system:dict:type:delete
-					dictType.GET("/:id", handler.Dict().GetDictType)        // system:dict:type:detail
-					dictType.GET("", handler.Dict().ListDictType)           // system:dict:type:list
+					dictType.POST("", handler.Dict().CreateDictType)               // system:dict:type:create
+					dictType.PUT("/:id", handler.Dict().UpdateDictType)            // system:dict:type:update
+					dictType.DELETE("/:ids", handler.Dict().DeleteDictType)        // system:dict:type:delete
+					dictType.GET("/:id", handler.Dict().GetDictType)               // system:dict:type:detail
+					dictType.GET("", handler.Dict().ListDictType)                  // system:dict:type:list
+					dictType.GET("/export", handler.Dict().ExportDictType)         // system:dict-type:export
+					dictType.GET("/export/bundle", handler.Dict().ExportBundle)    // system:dict-type:export:bundle
+					dictType.POST("/import/bundle", handler.Dict().ImportBundle)   // system:dict-type:import:bundle
+					dictType.GET("/recycle", handler.Dict().DictTypeRecycle)       // system:dict-type:recycle
+					dictType.PUT("/recycle/:ids", handler.Dict().DictTypeRestore)  // system:dict-type:restore
+					dictType.DELETE("/recycle/:ids", handler.Dict().DictTypePurge) // system:dict-type:purge
 				}
 
 				// 字典数据管理
 				dictData := sys.Group("dict-data")
 				{
-					dictData.POST("", handler.Dict().CreateDictData)        // system:dict:data:create
-					dictData.PUT("/:id", handler.Dict().UpdateDictData)     // system:dict:data:update
-					dictData.DELETE("/:ids", handler.Dict().DeleteDictData) // system:dict:data:delete
-					dictData.GET("/:id", handler.Dict().GetDictData)        // system:dict:data:detail
-					dictData.GET("", handler.Dict().ListDictData)           // system:dict:data:list
-					//dictData.GET("/type/:type", handler.Dict().GetDictDataByType) // system:dict:data:list:type
+					dictData.POST("", handler.Dict().CreateDictData)               // system:dict:data:create
+					dictData.PUT("/:id", handler.Dict().UpdateDictData)            // system:dict:data:update
+					dictData.DELETE("/:ids", handler.Dict().DeleteDictData)        // system:dict:data:delete
+					dictData.GET("/:id", handler.Dict().GetDictData)               // system:dict:data:detail
+					dictData.GET("", handler.Dict().ListDictData)                  // system:dict:data:list
+					dictData.GET("/export", handler.Dict().ExportDictData)         // system:dict-data:export
+					dictData.GET("/recycle", handler.Dict().DictDataRecycle)       // system:dict-data:recycle
+					dictData.PUT("/recycle/:ids", handler.Dict().DictDataRestore)  // system:dict-data:restore
+					dictData.DELETE("/recycle/:ids", handler.Dict().DictDataPurge) // system:dict-data:purge
 				}
 			}
 		}
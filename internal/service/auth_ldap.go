@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
+)
+
+const defaultLDAPTimeout = 5 * time.Second
+
+type ldapAuthenticator struct {
+	config *config.LDAPConfig
+}
+
+// NewLDAPAuthenticator 创建 LDAP/AD 认证器：先用服务账号查找用户 DN，再以用户 DN 和密码绑定校验
+func NewLDAPAuthenticator(cfg *config.LDAPConfig) Authenticator {
+	return &ldapAuthenticator{config: cfg}
+}
+
+func (a *ldapAuthenticator) Name() string {
+	return AuthenticatorLDAP
+}
+
+func (a *ldapAuthenticator) Authenticate(ctx context.Context, username, password string) (*Identity, error) {
+	// 空密码会被服务端视为匿名绑定而直接成功，必须拒绝
+	if username == "" || password == "" {
+		return nil, errors.WithMsg(errors.Unauthorized, "用户名或密码错误")
+	}
+
+	conn, err := a.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if a.config.BindDN != "" {
+		if err := conn.Bind(a.config.BindDN, a.config.BindPassword); err != nil {
+			return nil, fmt.Errorf("ldap service bind failed: %w", err)
+		}
+	}
+
+	attrs := a.attributes()
+	filter := a.config.UserFilter
+	if filter == "" {
+		filter = "(uid=%s)"
+	}
+	result, err := conn.Search(ldap.NewSearchRequest(
+		a.config.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		2, 0, false,
+		fmt.Sprintf(filter, ldap.EscapeFilter(username)),
+		[]string{attrs.Username, attrs.Nickname, attrs.Email, attrs.Phone, attrs.Group},
+		nil,
+	))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, errors.WithMsg(errors.NotFound, "用户不存在")
+		}
+		return nil, fmt.Errorf("ldap search failed: %w", err)
+	}
+	if len(result.Entries) == 0 {
+		return nil, errors.WithMsg(errors.NotFound, "用户不存在")
+	}
+	if len(result.Entries) > 1 {
+		return nil, fmt.Errorf("ldap search returned multiple entries for %q", username)
+	}
+	entry := result.Entries[0]
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, errors.WithMsg(errors.Unauthorized, "密码错误")
+		}
+		return nil, fmt.Errorf("ldap user bind failed: %w", err)
+	}
+
+	identity := &Identity{
+		Username: entry.GetAttributeValue(attrs.Username),
+		Nickname: entry.GetAttributeValue(attrs.Nickname),
+		Email:    entry.GetAttributeValue(attrs.Email),
+		Phone:    entry.GetAttributeValue(attrs.Phone),
+		Groups:   entry.GetAttributeValues(attrs.Group),
+		Subject:  entry.DN,
+	}
+	if identity.Username == "" {
+		identity.Username = username
+	}
+	return identity, nil
+}
+
+func (a *ldapAuthenticator) dial(ctx context.Context) (*ldap.Conn, error) {
+	timeout := a.config.Timeout
+	if timeout <= 0 {
+		timeout = defaultLDAPTimeout
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: a.config.InsecureSkipVerify}
+	if u, err := url.Parse(a.config.URL); err == nil {
+		tlsConfig.ServerName = u.Hostname()
+	}
+
+	conn, err := ldap.DialURL(a.config.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: timeout}),
+		ldap.DialWithTLSConfig(tlsConfig),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("dial ldap server failed: %w", err)
+	}
+	// 单次请求超时取配置值与上下文剩余时间中的较小者
+	if deadline, ok := ctx.Deadline(); ok {
+		if remain := time.Until(deadline); remain < timeout {
+			timeout = remain
+		}
+	}
+	conn.SetTimeout(timeout)
+
+	if a.config.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("ldap starttls failed: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// attributes 返回属性映射，未配置的属性使用 OpenLDAP 常用名称
+func (a *ldapAuthenticator) attributes() config.LDAPAttributeConfig {
+	attrs := a.config.Attributes
+	if attrs.Username == "" {
+		attrs.Username = "uid"
+	}
+	if attrs.Nickname == "" {
+		attrs.Nickname = "cn"
+	}
+	if attrs.Email == "" {
+		attrs.Email = "mail"
+	}
+	if attrs.Phone == "" {
+		attrs.Phone = "telephoneNumber"
+	}
+	if attrs.Group == "" {
+		attrs.Group = "memberOf"
+	}
+	return attrs
+}
+
+// mapGroupRoles 将目录组映射为系统角色编码，组 DN 比较时忽略大小写，未匹配任何组时返回默认角色
+func mapGroupRoles(cfg *config.LDAPConfig, groups []string) []string {
+	var codes []string
+	seen := make(map[string]bool)
+	for _, mapping := range cfg.GroupRoles {
+		for _, group := range groups {
+			if !strings.EqualFold(strings.TrimSpace(mapping.Group), strings.TrimSpace(group)) {
+				continue
+			}
+			for _, code := range mapping.Roles {
+				if !seen[code] {
+					seen[code] = true
+					codes = append(codes, code)
+				}
+			}
+		}
+	}
+	if len(codes) == 0 {
+		return cfg.DefaultRoles
+	}
+	return codes
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jimlambrt/gldap"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
+)
+
+const (
+	testLDAPBaseDN       = "ou=people,dc=example,dc=com"
+	testLDAPServiceDN    = "cn=readonly,dc=example,dc=com"
+	testLDAPServicePass  = "readonly"
+	testLDAPAliceDN      = "uid=alice,ou=people,dc=example,dc=com"
+	testLDAPAlicePass    = "alice-secret"
+	testLDAPAdminGroupDN = "cn=admins,ou=groups,dc=example,dc=com"
+)
+
+// startTestLDAPServer 启动一个只包含 alice 用户的进程内 LDAP 服务
+func startTestLDAPServer(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := l.Addr().String()
+	_ = l.Close()
+
+	passwords := map[string]string{
+		testLDAPServiceDN: testLDAPServicePass,
+		testLDAPAliceDN:   testLDAPAlicePass,
+	}
+
+	mux, err := gldap.NewMux()
+	if err != nil {
+		t.Fatalf("new mux: %v", err)
+	}
+	_ = mux.Bind(func(w *gldap.ResponseWriter, r *gldap.Request) {
+		resp := r.NewBindResponse(gldap.WithResponseCode(gldap.ResultInvalidCredentials))
+		defer func() { _ = w.Write(resp) }()
+		m, err := r.GetSimpleBindMessage()
+		if err != nil {
+			return
+		}
+		if pass, ok := passwords[m.UserName]; ok && pass == string(m.Password) {
+			resp.SetResultCode(gldap.ResultSuccess)
+		}
+	})
+	_ = mux.Search(func(w *gldap.ResponseWriter, r *gldap.Request) {
+		resp := r.NewSearchDoneResponse(gldap.WithResponseCode(gldap.ResultSuccess))
+		defer func() { _ = w.Write(resp) }()
+		m, err := r.GetSearchMessage()
+		if err != nil || m.BaseDN != testLDAPBaseDN {
+			resp.SetResultCode(gldap.ResultNoSuchObject)
+			return
+		}
+		if m.Filter != "(uid=alice)" {
+			return
+		}
+		_ = w.Write(r.NewSearchResponseEntry(testLDAPAliceDN, gldap.WithAttributes(map[string][]string{
+			"uid":             {"alice"},
+			"cn":              {"Alice Smith"},
+			"mail":            {"alice@example.com"},
+			"telephoneNumber": {"13800000000"},
+			"memberOf":        {strings.ToUpper(testLDAPAdminGroupDN), "cn=staff,ou=groups,dc=example,dc=com"},
+		})))
+	})
+
+	server, err := gldap.NewServer()
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	_ = server.Router(mux)
+	go func() { _ = server.Run(addr) }()
+	t.Cleanup(func() { _ = server.Stop() })
+
+	for i := 0; i < 100 && !server.Ready(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !server.Ready() {
+		t.Fatal("ldap server not ready")
+	}
+	return addr
+}
+
+func TestLDAPAuthenticator_Authenticate(t *testing.T) {
+	addr := startTestLDAPServer(t)
+	cfg := &config.LDAPConfig{
+		URL:          fmt.Sprintf("ldap://%s", addr),
+		BindDN:       testLDAPServiceDN,
+		BindPassword: testLDAPServicePass,
+		BaseDN:       testLDAPBaseDN,
+		UserFilter:   "(uid=%s)",
+		Timeout:      2 * time.Second,
+	}
+	authenticator := NewLDAPAuthenticator(cfg)
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		wantCode errors.ErrorCode
+	}{
+		{name: "success", username: "alice", password: testLDAPAlicePass},
+		{name: "wrong password", username: "alice", password: "wrong", wantCode: errors.Unauthorized},
+		{name: "empty password", username: "alice", password: "", wantCode: errors.Unauthorized},
+		{name: "unknown user", username: "bob", password: "whatever", wantCode: errors.NotFound},
+		{name: "filter injection", username: "*", password: testLDAPAlicePass, wantCode: errors.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := authenticator.Authenticate(context.Background(), tt.username, tt.password)
+			if tt.wantCode != 0 {
+				e, ok := err.(*errors.Error)
+				if !ok || e.Code != int(tt.wantCode) {
+					t.Fatalf("Authenticate() error = %v, want code %d", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if identity.Username != "alice" || identity.Nickname != "Alice Smith" ||
+				identity.Email != "alice@example.com" || identity.Phone != "13800000000" {
+				t.Errorf("identity = %+v", identity)
+			}
+			if len(identity.Groups) != 2 {
+				t.Errorf("groups = %v", identity.Groups)
+			}
+		})
+	}
+}
+
+func TestLDAPAuthenticator_ServiceBindFailed(t *testing.T) {
+	addr := startTestLDAPServer(t)
+	authenticator := NewLDAPAuthenticator(&config.LDAPConfig{
+		URL:          fmt.Sprintf("ldap://%s", addr),
+		BindDN:       testLDAPServiceDN,
+		BindPassword: "wrong",
+		BaseDN:       testLDAPBaseDN,
+	})
+	_, err := authenticator.Authenticate(context.Background(), "alice", testLDAPAlicePass)
+	if _, ok := err.(*errors.Error); ok || err == nil {
+		t.Fatalf("Authenticate() error = %v, want infrastructure error", err)
+	}
+}
+
+func TestMapGroupRoles(t *testing.T) {
+	cfg := &config.LDAPConfig{
+		GroupRoles: []config.LDAPGroupRoleConfig{
+			{Group: testLDAPAdminGroupDN, Roles: []string{"SuperAdmin", "Auditor"}},
+			{Group: "cn=auditors,ou=groups,dc=example,dc=com", Roles: []string{"Auditor"}},
+		},
+		DefaultRoles: []string{"Guest"},
+	}
+	got := mapGroupRoles(cfg, []string{"CN=Admins,OU=Groups,DC=example,DC=com", "cn=auditors,ou=groups,dc=example,dc=com"})
+	if !slices.Equal(got, []string{"SuperAdmin", "Auditor"}) {
+		t.Errorf("mapGroupRoles() = %v", got)
+	}
+	if got := mapGroupRoles(cfg, []string{"cn=staff,ou=groups,dc=example,dc=com"}); !slices.Equal(got, []string{"Guest"}) {
+		t.Errorf("mapGroupRoles() default = %v", got)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
+	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AuthenticatorLocal = "local"
+	AuthenticatorLDAP  = "ldap"
+)
+
+// Identity 认证成功后得到的身份信息
+type Identity struct {
+	// User 本地用户，外部认证器返回 nil，由登录流程按 Username 关联或自动创建
+	User     *model.User
+	Username string
+	Nickname string
+	Email    string
+	Phone    string
+	// Groups 外部目录中的组，用于映射系统角色
+	Groups []string
+	// Source 认证来源，即认证器名称
+	Source string
+	// Subject 用户在外部认证源中的唯一标识，LDAP 为用户 DN，本地认证为空
+	Subject string
+}
+
+// Authenticator 用户名密码认证器
+type Authenticator interface {
+	Name() string
+	Authenticate(ctx context.Context, username, password string) (*Identity, error)
+}
+
+// NewAuthenticators 按配置顺序创建认证器链，未配置时仅使用本地认证
+func NewAuthenticators(cfg *config.Config, repo Repository) ([]Authenticator, error) {
+	names := cfg.Auth.Authenticators
+	if len(names) == 0 {
+		names = []string{AuthenticatorLocal}
+	}
+	authenticators := make([]Authenticator, 0, len(names))
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case AuthenticatorLocal:
+			authenticators = append(authenticators, NewLocalAuthenticator(repo))
+		case AuthenticatorLDAP:
+			authenticators = append(authenticators, NewLDAPAuthenticator(&cfg.LDAP))
+		default:
+			return nil, fmt.Errorf("unknown authenticator %q", name)
+		}
+	}
+	return authenticators, nil
+}
+
+// authenticate 依次尝试认证器链，任一认证器成功即返回，全部失败时返回最后一个错误
+func authenticate(ctx context.Context, logger *log.Logger, authenticators []Authenticator, username, password string) (*Identity, error) {
+	err := error(errors.WithMsg(errors.Unauthorized, "用户名或密码错误"))
+	for _, a := range authenticators {
+		identity, authErr := a.Authenticate(ctx, username, password)
+		if authErr == nil {
+			identity.Source = a.Name()
+			return identity, nil
+		}
+		if _, ok := authErr.(*errors.Error); !ok {
+			// 非业务错误（如目录服务不可用）记录后继续尝试下一个认证器
+			logger.WithContext(ctx).Error("认证器执行失败", zap.String("authenticator", a.Name()), zap.Error(authErr))
+		}
+		err = authErr
+	}
+	return nil, err
+}
+
+type localAuthenticator struct {
+	repo Repository
+}
+
+// NewLocalAuthenticator 创建基于本地用户表密码的认证器
+func NewLocalAuthenticator(repo Repository) Authenticator {
+	return &localAuthenticator{repo: repo}
+}
+
+func (a *localAuthenticator) Name() string {
+	return AuthenticatorLocal
+}
+
+func (a *localAuthenticator) Authenticate(ctx context.Context, username, password string) (*Identity, error) {
+	user, err := a.repo.User().FindByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.WithMsg(errors.NotFound, "用户不存在")
+	}
+	// 外部认证器自动创建的用户没有本地密码
+	if user.Password == "" {
+		return nil, errors.WithMsg(errors.Unauthorized, "密码错误")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return nil, errors.WithMsg(errors.Unauthorized, "密码错误")
+	}
+	return &Identity{
+		User:     user,
+		Username: user.Username,
+		Nickname: user.Nickname,
+		Email:    user.Email,
+		Phone:    user.Phone,
+	}, nil
+}
@@ -2,30 +2,215 @@ package service
 
 import (
 	"context"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/mojocn/base64Captcha"
 	"github.com/redis/go-redis/v9"
+	"github.com/wxlbd/gin-casbin-admin/internal/dto"
 	"github.com/wxlbd/gin-casbin-admin/internal/handler"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"github.com/wxlbd/gin-casbin-admin/pkg/otelx"
+	"go.uber.org/zap"
+)
+
+const (
+	CaptchaModeAlways = "always"
+	CaptchaModeFailed = "failed"
+	CaptchaModeOff    = "off"
+
+	CaptchaTypeDigit  = "digit"
+	CaptchaTypeString = "string"
+	CaptchaTypeMath   = "math"
+	CaptchaTypeAudio  = "audio"
+	CaptchaTypeSlider = "slider"
+
+	captchaFailPrefix = "captcha:fail:"
+
+	defaultCaptchaWidth           = 360
+	defaultCaptchaHeight          = 180
+	defaultCaptchaLength          = 4
+	defaultCaptchaExpire          = 10 * time.Minute
+	defaultCaptchaFailThreshold   = 3
+	defaultCaptchaFailWindow      = 15 * time.Minute
+	defaultCaptchaSource          = "1234567890qwertyuioplkjhgfdsazxcvbnm"
+	defaultCaptchaLanguage        = "en"
+	defaultCaptchaSliderTolerance = 5
 )
 
 type captchaService struct {
-	store base64Captcha.Store
+	redis  *redis.Client
+	logger *log.Logger
+	// state 由配置生成的验证码驱动，配置重新加载时整体替换
+	state atomic.Pointer[captchaState]
+}
+
+type captchaState struct {
+	store  base64Captcha.Store
+	driver base64Captcha.Driver
+	slider *sliderCaptcha
+	config config.CaptchaConfig
+}
+
+func NewCaptchaService(cfg *config.Config, watcher *config.Watcher, logger *log.Logger, redisClient *redis.Client) handler.CaptchaService {
+	s := &captchaService{
+		redis:  redisClient,
+		logger: logger,
+	}
+	s.state.Store(s.newState(cfg.Captcha))
+	watcher.Subscribe(func(_, next *config.Config) {
+		s.state.Store(s.newState(next.Captcha))
+	})
+	return s
 }
 
-func NewCaptchaService(redisClient *redis.Client) handler.CaptchaService {
-	return &captchaService{
-		store: NewRedisStore(redisClient, 10*time.Minute),
+// newState 按配置补全默认值并创建验证码驱动
+func (s *captchaService) newState(c config.CaptchaConfig) *captchaState {
+	c.Mode = strings.ToLower(c.Mode)
+	c.Type = strings.ToLower(c.Type)
+	if c.Mode == "" {
+		c.Mode = CaptchaModeAlways
+	}
+	if c.Type == "" {
+		c.Type = CaptchaTypeDigit
+	}
+	if c.Width <= 0 {
+		c.Width = defaultCaptchaWidth
+	}
+	if c.Height <= 0 {
+		c.Height = defaultCaptchaHeight
+	}
+	if c.Length <= 0 {
+		c.Length = defaultCaptchaLength
+	}
+	if c.Expire <= 0 {
+		c.Expire = defaultCaptchaExpire
+	}
+	if c.FailThreshold <= 0 {
+		c.FailThreshold = defaultCaptchaFailThreshold
+	}
+	if c.FailWindow <= 0 {
+		c.FailWindow = defaultCaptchaFailWindow
+	}
+	if c.Source == "" {
+		c.Source = defaultCaptchaSource
+	}
+	if c.Language == "" {
+		c.Language = defaultCaptchaLanguage
+	}
+	if c.SliderTolerance <= 0 {
+		c.SliderTolerance = defaultCaptchaSliderTolerance
+	}
+
+	st := &captchaState{store: NewRedisStore(s.redis, c.Expire)}
+	switch c.Type {
+	case CaptchaTypeDigit:
+		st.driver = base64Captcha.NewDriverDigit(c.Height, c.Width, c.Length, 0.7, 80)
+	case CaptchaTypeString:
+		st.driver = base64Captcha.NewDriverString(c.Height, c.Width, c.NoiseCount, c.ShowLineOptions, c.Length, c.Source, nil, nil, nil)
+	case CaptchaTypeMath:
+		st.driver = base64Captcha.NewDriverMath(c.Height, c.Width, c.NoiseCount, c.ShowLineOptions, nil, nil, nil)
+	case CaptchaTypeAudio:
+		st.driver = base64Captcha.NewDriverAudio(c.Length, c.Language)
+	case CaptchaTypeSlider:
+		st.slider = newSliderCaptcha(c.Width, c.Height)
+	default:
+		s.logger.Warn("未知的验证码类型，使用数字验证码", zap.String("type", c.Type))
+		c.Type = CaptchaTypeDigit
+		st.driver = base64Captcha.NewDriverDigit(c.Height, c.Width, c.Length, 0.7, 80)
 	}
+	st.config = c
+	return st
 }
 
-func (s *captchaService) Generate(ctx context.Context) (id, b64s string, err error) {
-	driver := base64Captcha.NewDriverDigit(180, 360, 4, 0.7, 80)
-	captcha := base64Captcha.NewCaptcha(driver, s.store)
-	id, b64s, _, err = captcha.Generate()
-	return
+func (s *captchaService) Generate(ctx context.Context, ip string) (resp *dto.CaptchaResponse, err error) {
+	ctx, span := otelx.Start(ctx, "CaptchaService.Generate")
+	defer func() { otelx.End(span, err) }()
+	st := s.state.Load()
+	required, err := s.Required(ctx, ip)
+	if err != nil {
+		return nil, err
+	}
+	resp = &dto.CaptchaResponse{CaptchaType: st.config.Type, Required: required}
+	if !required {
+		return resp, nil
+	}
+
+	if st.slider != nil {
+		puzzle, err := st.slider.Generate()
+		if err != nil {
+			return nil, err
+		}
+		id := base64Captcha.RandomId()
+		if err := st.store.Set(id, puzzle.Answer); err != nil {
+			return nil, err
+		}
+		resp.CaptchaId = id
+		resp.CaptchaImage = puzzle.Background
+		resp.SliderImage = puzzle.Piece
+		resp.SliderY = puzzle.Y
+		return resp, nil
+	}
+
+	captcha := base64Captcha.NewCaptcha(st.driver, st.store)
+	id, b64s, _, err := captcha.Generate()
+	if err != nil {
+		return nil, err
+	}
+	resp.CaptchaId = id
+	resp.CaptchaImage = b64s
+	return resp, nil
 }
 
 func (s *captchaService) Verify(ctx context.Context, id, answer string) bool {
-	return s.store.Verify(id, answer, true)
+	st := s.state.Load()
+	// 验证码不存在时 store 返回空字符串，空答案不能通过校验
+	if id == "" || answer == "" {
+		return false
+	}
+	if st.slider != nil {
+		return st.slider.Verify(st.store.Get(id, true), answer, st.config.SliderTolerance)
+	}
+	return st.store.Verify(id, answer, true)
+}
+
+func (s *captchaService) Required(ctx context.Context, ip string) (required bool, err error) {
+	ctx, span := otelx.Start(ctx, "CaptchaService.Required")
+	defer func() { otelx.End(span, err) }()
+	st := s.state.Load()
+	switch st.config.Mode {
+	case CaptchaModeOff:
+		return false, nil
+	case CaptchaModeFailed:
+		count, err := s.redis.Get(ctx, captchaFailPrefix+ip).Int()
+		if err != nil {
+			if err == redis.Nil {
+				return false, nil
+			}
+			return false, err
+		}
+		return count >= st.config.FailThreshold, nil
+	default:
+		return true, nil
+	}
+}
+
+func (s *captchaService) RecordLoginFailure(ctx context.Context, ip string) (err error) {
+	ctx, span := otelx.Start(ctx, "CaptchaService.RecordLoginFailure")
+	defer func() { otelx.End(span, err) }()
+	st := s.state.Load()
+	if st.config.Mode != CaptchaModeFailed {
+		return nil
+	}
+	// 窗口从第一次失败开始计算，后续失败不延长。先以 SET NX 创建带过期时间的计数再自增，
+	// 避免自增成功而设置过期时间失败时计数永不过期
+	key := captchaFailPrefix + ip
+	_, err = s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SetNX(ctx, key, 0, st.config.FailWindow)
+		pipe.Incr(ctx, key)
+		return nil
+	})
+	return err
 }
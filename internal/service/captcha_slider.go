@@ -0,0 +1,133 @@
+package service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// sliderPuzzle 滑块验证码，Answer 为缺口的横向位置
+type sliderPuzzle struct {
+	Background string
+	Piece      string
+	Y          int
+	Answer     string
+}
+
+// sliderCaptcha 生成拼图滑块验证码，背景图挖出一块方形缺口，用户需要将滑块拖到缺口位置
+type sliderCaptcha struct {
+	width     int
+	height    int
+	pieceSize int
+}
+
+func newSliderCaptcha(width, height int) *sliderCaptcha {
+	pieceSize := min(width, height) / 4
+	if pieceSize < 10 {
+		pieceSize = 10
+	}
+	return &sliderCaptcha{width: max(width, pieceSize*3), height: max(height, pieceSize+10), pieceSize: pieceSize}
+}
+
+func (d *sliderCaptcha) Generate() (*sliderPuzzle, error) {
+	bg := image.NewRGBA(image.Rect(0, 0, d.width, d.height))
+	d.drawBackground(bg)
+
+	// 缺口不与滑块初始位置重叠
+	x := d.pieceSize*2 + rand.IntN(d.width-d.pieceSize*3+1)
+	y := 5 + rand.IntN(d.height-d.pieceSize-10+1)
+	rect := image.Rect(x, y, x+d.pieceSize, y+d.pieceSize)
+
+	piece := image.NewRGBA(image.Rect(0, 0, d.pieceSize, d.pieceSize))
+	draw.Draw(piece, piece.Bounds(), bg, rect.Min, draw.Src)
+	drawBorder(piece, piece.Bounds(), color.RGBA{R: 255, G: 255, B: 255, A: 255})
+
+	// 缺口处变暗并描边
+	draw.Draw(bg, rect, image.NewUniform(color.RGBA{A: 140}), image.Point{}, draw.Over)
+	drawBorder(bg, rect, color.RGBA{R: 255, G: 255, B: 255, A: 200})
+
+	background, err := encodePNG(bg)
+	if err != nil {
+		return nil, err
+	}
+	pieceImage, err := encodePNG(piece)
+	if err != nil {
+		return nil, err
+	}
+	return &sliderPuzzle{
+		Background: background,
+		Piece:      pieceImage,
+		Y:          y,
+		Answer:     strconv.Itoa(x),
+	}, nil
+}
+
+// Verify 校验滑块偏移量与缺口位置的偏差是否在允许范围内
+func (d *sliderCaptcha) Verify(expected, answer string, tolerance int) bool {
+	want, err := strconv.Atoi(expected)
+	if err != nil {
+		return false
+	}
+	got, err := strconv.ParseFloat(strings.TrimSpace(answer), 64)
+	if err != nil {
+		return false
+	}
+	diff := int(got+0.5) - want
+	return diff >= -tolerance && diff <= tolerance
+}
+
+// drawBackground 随机渐变背景叠加色块，避免缺口位置可以通过纯色背景直接识别
+func (d *sliderCaptcha) drawBackground(img *image.RGBA) {
+	from := randomColor()
+	to := randomColor()
+	for x := 0; x < d.width; x++ {
+		t := float64(x) / float64(d.width)
+		c := color.RGBA{
+			R: uint8(float64(from.R)*(1-t) + float64(to.R)*t),
+			G: uint8(float64(from.G)*(1-t) + float64(to.G)*t),
+			B: uint8(float64(from.B)*(1-t) + float64(to.B)*t),
+			A: 255,
+		}
+		for y := 0; y < d.height; y++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	for i := 0; i < 12; i++ {
+		w := d.pieceSize/2 + rand.IntN(d.pieceSize*2)
+		h := d.pieceSize/2 + rand.IntN(d.pieceSize*2)
+		x := rand.IntN(d.width)
+		y := rand.IntN(d.height)
+		c := randomColor()
+		c.A = 120
+		draw.Draw(img, image.Rect(x, y, x+w, y+h), image.NewUniform(c), image.Point{}, draw.Over)
+	}
+}
+
+func drawBorder(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	for x := rect.Min.X; x < rect.Max.X; x++ {
+		img.SetRGBA(x, rect.Min.Y, c)
+		img.SetRGBA(x, rect.Max.Y-1, c)
+	}
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		img.SetRGBA(rect.Min.X, y, c)
+		img.SetRGBA(rect.Max.X-1, y, c)
+	}
+}
+
+func randomColor() color.RGBA {
+	return color.RGBA{R: uint8(rand.IntN(256)), G: uint8(rand.IntN(256)), B: uint8(rand.IntN(256)), A: 255}
+}
+
+func encodePNG(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
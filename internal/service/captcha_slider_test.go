@@ -0,0 +1,43 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSliderCaptcha(t *testing.T) {
+	d := newSliderCaptcha(360, 180)
+	for i := 0; i < 20; i++ {
+		puzzle, err := d.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if !strings.HasPrefix(puzzle.Background, "data:image/png;base64,") || !strings.HasPrefix(puzzle.Piece, "data:image/png;base64,") {
+			t.Fatalf("unexpected image encoding")
+		}
+		x, _ := strconv.Atoi(puzzle.Answer)
+		if x < d.pieceSize*2 || x+d.pieceSize > d.width || puzzle.Y < 0 || puzzle.Y+d.pieceSize > d.height {
+			t.Fatalf("piece out of range: x=%d y=%d", x, puzzle.Y)
+		}
+	}
+
+	tests := []struct {
+		answer string
+		want   bool
+	}{
+		{answer: "100", want: true},
+		{answer: "104.6", want: true},
+		{answer: "95", want: true},
+		{answer: "106", want: false},
+		{answer: "abc", want: false},
+	}
+	for _, tt := range tests {
+		if got := d.Verify("100", tt.answer, 5); got != tt.want {
+			t.Errorf("Verify(%q) = %v, want %v", tt.answer, got, tt.want)
+		}
+	}
+	if d.Verify("", "0", 5) {
+		t.Error("Verify() with expired captcha should fail")
+	}
+}
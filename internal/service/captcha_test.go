@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+)
+
+func TestCaptchaLoginFailures(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	s := &captchaService{redis: client}
+	s.state.Store(s.newState(config.CaptchaConfig{Mode: CaptchaModeFailed, FailThreshold: 2, FailWindow: time.Minute}))
+
+	const ip = "192.0.2.1"
+	for i := 0; i < 2; i++ {
+		if required, err := s.Required(ctx, ip); err != nil || required {
+			t.Fatalf("Required() after %d failures = %v, %v", i, required, err)
+		}
+		if err := s.RecordLoginFailure(ctx, ip); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if required, err := s.Required(ctx, ip); err != nil || !required {
+		t.Fatalf("Required() after threshold = %v, %v", required, err)
+	}
+	// 窗口从第一次失败开始计算，之后的失败不延长
+	mr.FastForward(30 * time.Second)
+	if err := s.RecordLoginFailure(ctx, ip); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := mr.TTL(captchaFailPrefix + ip); ttl != 30*time.Second {
+		t.Errorf("ttl = %v, want 30s", ttl)
+	}
+	mr.FastForward(30 * time.Second)
+	if required, err := s.Required(ctx, ip); err != nil || required {
+		t.Fatalf("Required() after window = %v, %v", required, err)
+	}
+}
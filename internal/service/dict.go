@@ -2,45 +2,71 @@ package service
 
 import (
 	"context"
+	"slices"
+	"strings"
+	"time"
 
-	"github.com/wxlbd/gin-casbin-admin/internal/handler"
-
+	"github.com/redis/go-redis/v9"
 	"github.com/wxlbd/gin-casbin-admin/internal/dto"
+	"github.com/wxlbd/gin-casbin-admin/internal/handler"
 	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
 	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
 	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"github.com/wxlbd/gin-casbin-admin/pkg/otelx"
+	"go.uber.org/zap"
 )
 
 var _ handler.DictService = (*dictService)(nil)
 
+const (
+	defaultDictCacheExpire = time.Hour
+
+	// DictDeleteRefuse 字典类型下有数据时拒绝删除
+	DictDeleteRefuse = "refuse"
+	// DictDeleteCascade 删除字典类型时同时删除其数据
+	DictDeleteCascade = "cascade"
+)
+
 type dictService struct {
-	log      *log.Logger
-	repo     Repository
-	typeRepo DictTypeRepository
-	dataRepo DictDataRepository
+	log          *log.Logger
+	repo         Repository
+	typeRepo     DictTypeRepository
+	dataRepo     DictDataRepository
+	cache        *dictCache
+	deletePolicy string
 }
 
-func NewDictService(logger *log.Logger, repo Repository) handler.DictService {
+func NewDictService(cfg *config.Config, logger *log.Logger, repo Repository, redisClient *redis.Client) handler.DictService {
+	expire := cfg.Dict.CacheExpire
+	if expire <= 0 {
+		expire = defaultDictCacheExpire
+	}
+	deletePolicy := strings.ToLower(cfg.Dict.DeletePolicy)
+	if deletePolicy != DictDeleteCascade {
+		deletePolicy = DictDeleteRefuse
+	}
 	return &dictService{
-		log:      logger,
-		repo:     repo,
-		typeRepo: repo.DictType(),
-		dataRepo: repo.DictData(),
+		log:          logger,
+		repo:         repo,
+		typeRepo:     repo.DictType(),
+		dataRepo:     repo.DictData(),
+		cache:        newDictCache(redisClient, expire, cfg.Dict.LocalCacheExpire),
+		deletePolicy: deletePolicy,
 	}
 }
 
 // DictType 实现
 
 // CreateDictType DictType
-func (s *dictService) CreateDictType(ctx context.Context, req *dto.DictTypeRequest) error {
+func (s *dictService) CreateDictType(ctx context.Context, req *dto.DictTypeRequest) (err error) {
+	ctx, span := otelx.Start(ctx, "DictService.CreateDictType")
+	defer func() { otelx.End(span, err) }()
 	// 检查编码是否存在
-	exist, err := s.typeRepo.FindByCode(ctx, req.Code)
-	if err != nil {
+	if err := s.checkDictTypeCode(ctx, req.Code); err != nil {
 		return err
 	}
-	if exist != nil {
-		return errors.WithMsg(errors.AlreadyExists, "字典类型编码已存在")
-	}
 
 	dictType := &model.DictType{
 		Name:   req.Name,
@@ -50,10 +76,17 @@ func (s *dictService) CreateDictType(ctx context.Context, req *dto.DictTypeReque
 		Remark: req.Remark,
 	}
 
-	return s.typeRepo.Create(ctx, dictType)
+	if err := s.typeRepo.Create(ctx, dictType); err != nil {
+		return err
+	}
+	// 类型编码可能已被查询过，清除缓存中的空结果
+	s.invalidateCache(ctx, dictType.Code)
+	return nil
 }
 
-func (s *dictService) UpdateDictType(ctx context.Context, req *dto.DictTypeRequest) error {
+func (s *dictService) UpdateDictType(ctx context.Context, req *dto.DictTypeRequest) (err error) {
+	ctx, span := otelx.Start(ctx, "DictService.UpdateDictType")
+	defer func() { otelx.End(span, err) }()
 	// 检查是否存在
 	exist, err := s.typeRepo.FindByID(ctx, req.ID)
 	if err != nil {
@@ -64,9 +97,10 @@ func (s *dictService) UpdateDictType(ctx context.Context, req *dto.DictTypeReque
 	}
 
 	// 如果修改了编码，检查新编码是否存在
-	if req.Code != exist.Code {
-		if existCode, _ := s.typeRepo.FindByCode(ctx, req.Code); existCode != nil {
-			return errors.WithMsg(errors.AlreadyExists, "字典类型编码已存在")
+	renamed := req.Code != exist.Code
+	if renamed {
+		if err := s.checkDictTypeCode(ctx, req.Code); err != nil {
+			return err
 		}
 	}
 
@@ -79,11 +113,62 @@ func (s *dictService) UpdateDictType(ctx context.Context, req *dto.DictTypeReque
 		Remark: req.Remark,
 	}
 
-	return s.typeRepo.Update(ctx, dictType)
+	// 修改编码时在同一事务中迁移字典数据，避免数据遗留在旧编码下
+	err = s.repo.Transaction(func(r Repository) error {
+		if err := r.DictType().Update(ctx, dictType); err != nil {
+			return err
+		}
+		if renamed {
+			return r.DictData().UpdateTypeCode(ctx, exist.Code, dictType.Code)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.invalidateCache(ctx, exist.Code, dictType.Code)
+	return nil
 }
 
-func (s *dictService) DeleteDictType(ctx context.Context, ids ...int64) error {
-	return s.typeRepo.Delete(ctx, ids...)
+func (s *dictService) DeleteDictType(ctx context.Context, ids ...int64) (err error) {
+	ctx, span := otelx.Start(ctx, "DictService.DeleteDictType")
+	defer func() { otelx.End(span, err) }()
+	var codes []string
+	err = s.repo.Transaction(func(r Repository) error {
+		// 锁定类型后再检查字典数据，并发新增的字典数据要么在此之前提交并被统计，要么等待删除完成后发现类型不存在
+		types, err := r.DictType().FindByIDsForUpdate(ctx, ids...)
+		if err != nil {
+			return err
+		}
+		if len(types) == 0 {
+			return nil
+		}
+		codes = make([]string, len(types))
+		for i, t := range types {
+			codes[i] = t.Code
+		}
+
+		switch s.deletePolicy {
+		case DictDeleteRefuse:
+			count, err := r.DictData().CountByTypeCodes(ctx, codes)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return errors.WithMsg(errors.InvalidParam, "字典类型下存在字典数据，请先删除字典数据")
+			}
+		case DictDeleteCascade:
+			if err := r.DictData().DeleteByTypeCodes(ctx, codes); err != nil {
+				return err
+			}
+		}
+		return r.DictType().Delete(ctx, ids...)
+	})
+	if err != nil {
+		return err
+	}
+	s.invalidateCache(ctx, codes...)
+	return nil
 }
 
 func (s *dictService) GetDictType(ctx context.Context, id int64) (*model.DictType, error) {
@@ -95,15 +180,12 @@ func (s *dictService) ListDictType(ctx context.Context, query *model.DictTypeQue
 }
 
 // DictData 实现
-func (s *dictService) CreateDictData(ctx context.Context, req *dto.DictDataRequest) error {
-	// 检查字典类型是否存在
-	dictType, err := s.typeRepo.FindByCode(ctx, req.TypeCode)
-	if err != nil {
+func (s *dictService) CreateDictData(ctx context.Context, req *dto.DictDataRequest) (err error) {
+	ctx, span := otelx.Start(ctx, "DictService.CreateDictData")
+	defer func() { otelx.End(span, err) }()
+	if err := s.checkDictValue(ctx, req.TypeCode, req.Value, 0); err != nil {
 		return err
 	}
-	if dictType == nil {
-		return errors.WithMsg(errors.NotFound, "字典类型不存在")
-	}
 
 	dictData := &model.DictDatum{
 		TypeCode: req.TypeCode,
@@ -114,10 +196,23 @@ func (s *dictService) CreateDictData(ctx context.Context, req *dto.DictDataReque
 		Remark:   req.Remark,
 	}
 
-	return s.dataRepo.Create(ctx, dictData)
+	// 锁定字典类型，避免与删除该类型并发时留下孤立的字典数据
+	err = s.repo.Transaction(func(r Repository) error {
+		if err := lockDictType(ctx, r, req.TypeCode); err != nil {
+			return err
+		}
+		return r.DictData().Create(ctx, dictData)
+	})
+	if err != nil {
+		return err
+	}
+	s.invalidateCache(ctx, dictData.TypeCode)
+	return nil
 }
 
-func (s *dictService) UpdateDictData(ctx context.Context, req *dto.DictDataRequest) error {
+func (s *dictService) UpdateDictData(ctx context.Context, req *dto.DictDataRequest) (err error) {
+	ctx, span := otelx.Start(ctx, "DictService.UpdateDictData")
+	defer func() { otelx.End(span, err) }()
 	// 检查是否存在
 	exist, err := s.dataRepo.FindByID(ctx, req.ID)
 	if err != nil {
@@ -127,15 +222,10 @@ func (s *dictService) UpdateDictData(ctx context.Context, req *dto.DictDataReque
 		return errors.WithMsg(errors.NotFound, "字典数据不存在")
 	}
 
-	// 如果修改了类型，检查新类型是否存在
-	if req.TypeCode != exist.TypeCode {
-		dictType, err := s.typeRepo.FindByCode(ctx, req.TypeCode)
-		if err != nil {
+	if req.TypeCode != exist.TypeCode || req.Value != exist.Value {
+		if err := s.checkDictValue(ctx, req.TypeCode, req.Value, exist.ID); err != nil {
 			return err
 		}
-		if dictType == nil {
-			return errors.WithMsg(errors.NotFound, "字典类型不存在")
-		}
 	}
 
 	dictData := &model.DictDatum{
@@ -148,11 +238,38 @@ func (s *dictService) UpdateDictData(ctx context.Context, req *dto.DictDataReque
 		Remark:   req.Remark,
 	}
 
-	return s.dataRepo.Update(ctx, dictData)
+	err = s.repo.Transaction(func(r Repository) error {
+		// 修改了类型时锁定并检查新类型
+		if req.TypeCode != exist.TypeCode {
+			if err := lockDictType(ctx, r, req.TypeCode); err != nil {
+				return err
+			}
+		}
+		return r.DictData().Update(ctx, dictData)
+	})
+	if err != nil {
+		return err
+	}
+	s.invalidateCache(ctx, exist.TypeCode, dictData.TypeCode)
+	return nil
 }
 
-func (s *dictService) DeleteDictData(ctx context.Context, ids ...int64) error {
-	return s.dataRepo.Delete(ctx, ids...)
+func (s *dictService) DeleteDictData(ctx context.Context, ids ...int64) (err error) {
+	ctx, span := otelx.Start(ctx, "DictService.DeleteDictData")
+	defer func() { otelx.End(span, err) }()
+	list, err := s.dataRepo.FindByIDs(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	if err := s.dataRepo.Delete(ctx, ids...); err != nil {
+		return err
+	}
+	codes := make([]string, len(list))
+	for i, d := range list {
+		codes[i] = d.TypeCode
+	}
+	s.invalidateCache(ctx, codes...)
+	return nil
 }
 
 func (s *dictService) GetDictData(ctx context.Context, id int64) (*model.DictDatum, error) {
@@ -163,6 +280,208 @@ func (s *dictService) ListDictData(ctx context.Context, query *model.DictDataQue
 	return s.dataRepo.List(ctx, query)
 }
 
+// 回收站
+
+func (s *dictService) ListDeletedDictType(ctx context.Context, page *types.PageParam) ([]*model.DictType, int64, error) {
+	return s.typeRepo.ListDeleted(ctx, page)
+}
+
+// RestoreDictType 恢复字典类型，随类型一起删除的字典数据需要在字典数据回收站中单独恢复
+func (s *dictService) RestoreDictType(ctx context.Context, ids ...int64) (err error) {
+	ctx, span := otelx.Start(ctx, "DictService.RestoreDictType")
+	defer func() { otelx.End(span, err) }()
+	list, err := s.typeRepo.FindDeletedByIDs(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return errors.WithMsg(errors.NotFound, "回收站中不存在该字典类型")
+	}
+	if err := s.typeRepo.Restore(ctx, ids...); err != nil {
+		return err
+	}
+	codes := make([]string, len(list))
+	for i, t := range list {
+		codes[i] = t.Code
+	}
+	s.invalidateCache(ctx, codes...)
+	return nil
+}
+
+// PurgeDictType 彻底删除字典类型及其在回收站中的字典数据
+func (s *dictService) PurgeDictType(ctx context.Context, ids ...int64) (err error) {
+	ctx, span := otelx.Start(ctx, "DictService.PurgeDictType")
+	defer func() { otelx.End(span, err) }()
+	list, err := s.typeRepo.FindDeletedByIDs(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return errors.WithMsg(errors.NotFound, "回收站中不存在该字典类型")
+	}
+	codes := make([]string, len(list))
+	deletedIDs := make([]int64, len(list))
+	for i, t := range list {
+		codes[i] = t.Code
+		deletedIDs[i] = t.ID
+	}
+	return s.repo.Transaction(func(r Repository) error {
+		if err := r.DictData().PurgeByTypeCodes(ctx, codes); err != nil {
+			return err
+		}
+		return r.DictType().Purge(ctx, deletedIDs...)
+	})
+}
+
+func (s *dictService) ListDeletedDictData(ctx context.Context, page *types.PageParam) ([]*model.DictDatum, int64, error) {
+	return s.dataRepo.ListDeleted(ctx, page)
+}
+
+// RestoreDictData 恢复字典数据，所属字典类型必须未被删除
+func (s *dictService) RestoreDictData(ctx context.Context, ids ...int64) (err error) {
+	ctx, span := otelx.Start(ctx, "DictService.RestoreDictData")
+	defer func() { otelx.End(span, err) }()
+	list, err := s.dataRepo.FindDeletedByIDs(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return errors.WithMsg(errors.NotFound, "回收站中不存在该字典数据")
+	}
+	var codes []string
+	for _, d := range list {
+		if !slices.Contains(codes, d.TypeCode) {
+			codes = append(codes, d.TypeCode)
+		}
+	}
+	err = s.repo.Transaction(func(r Repository) error {
+		dictTypes, err := r.DictType().FindByCodesForUpdate(ctx, codes)
+		if err != nil {
+			return err
+		}
+		if len(dictTypes) != len(codes) {
+			return errors.WithMsg(errors.InvalidParam, "字典数据所属的字典类型不存在，请先恢复字典类型")
+		}
+		return r.DictData().Restore(ctx, ids...)
+	})
+	if err != nil {
+		return err
+	}
+	s.invalidateCache(ctx, codes...)
+	return nil
+}
+
+func (s *dictService) PurgeDictData(ctx context.Context, ids ...int64) (err error) {
+	ctx, span := otelx.Start(ctx, "DictService.PurgeDictData")
+	defer func() { otelx.End(span, err) }()
+	list, err := s.dataRepo.FindDeletedByIDs(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return errors.WithMsg(errors.NotFound, "回收站中不存在该字典数据")
+	}
+	return s.dataRepo.Purge(ctx, ids...)
+}
+
 func (s *dictService) GetDictDataByType(ctx context.Context, typeCode string) ([]*model.DictDatum, error) {
-	return s.dataRepo.FindByTypeCode(ctx, typeCode)
+	data, err := s.GetDictDataByTypes(ctx, []string{typeCode})
+	if err != nil {
+		return nil, err
+	}
+	return data[typeCode], nil
+}
+
+// GetDictDataByTypes 优先从缓存获取，未命中的类型一次查询数据库后回填缓存；
+// 类型不存在或已禁用时返回空列表
+func (s *dictService) GetDictDataByTypes(ctx context.Context, typeCodes []string) (data map[string][]*model.DictDatum, err error) {
+	ctx, span := otelx.Start(ctx, "DictService.GetDictDataByTypes")
+	defer func() { otelx.End(span, err) }()
+	result, misses, version, err := s.cache.Get(ctx, typeCodes)
+	if err != nil {
+		// 缓存不可用时降级为直接查询数据库
+		s.log.Warn("读取字典缓存失败", zap.Error(err))
+	}
+	if len(misses) == 0 {
+		return result, nil
+	}
+
+	types, err := s.typeRepo.FindByCodes(ctx, misses)
+	if err != nil {
+		return nil, err
+	}
+	var enabled []string
+	loaded := make(map[string][]*model.DictDatum, len(types))
+	for _, t := range types {
+		loaded[t.Code] = nil
+		if t.Status == 1 {
+			enabled = append(enabled, t.Code)
+		}
+	}
+	if len(enabled) > 0 {
+		list, err := s.dataRepo.FindEnabledByTypeCodes(ctx, enabled)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range list {
+			loaded[d.TypeCode] = append(loaded[d.TypeCode], d)
+		}
+	}
+	// 不存在的类型不写入缓存，避免任意编码占用缓存空间
+	if err := s.cache.Set(ctx, loaded, version); err != nil {
+		s.log.Warn("写入字典缓存失败", zap.Error(err))
+	}
+	for _, code := range misses {
+		result[code] = loaded[code]
+	}
+	return result, nil
+}
+
+// checkDictTypeCode 检查字典类型编码是否可用，回收站中的类型仍然占用编码
+func (s *dictService) checkDictTypeCode(ctx context.Context, code string) error {
+	types, err := s.typeRepo.FindByCodesWithDeleted(ctx, []string{code})
+	if err != nil {
+		return err
+	}
+	if len(types) == 0 {
+		return nil
+	}
+	if types[0].DeletedAt.Valid {
+		return errors.WithMsg(errors.AlreadyExists, "字典类型编码已被回收站中的类型使用，请恢复或彻底删除后重试")
+	}
+	return errors.WithMsg(errors.AlreadyExists, "字典类型编码已存在")
+}
+
+// checkDictValue 检查同一字典类型下的字典值是否已被 excludeID 以外的数据使用，回收站中的数据仍然占用字典值
+func (s *dictService) checkDictValue(ctx context.Context, typeCode, value string, excludeID int64) error {
+	exist, err := s.dataRepo.FindByTypeCodeAndValueWithDeleted(ctx, typeCode, value)
+	if err != nil {
+		return err
+	}
+	if exist == nil || exist.ID == excludeID {
+		return nil
+	}
+	if exist.DeletedAt.Valid {
+		return errors.WithMsg(errors.AlreadyExists, "字典值已被回收站中的数据使用，请恢复或彻底删除后重试")
+	}
+	return errors.WithMsg(errors.AlreadyExists, "字典值已存在")
+}
+
+// lockDictType 在事务中锁定字典类型，类型不存在时返回错误
+func lockDictType(ctx context.Context, r Repository, code string) error {
+	types, err := r.DictType().FindByCodesForUpdate(ctx, []string{code})
+	if err != nil {
+		return err
+	}
+	if len(types) == 0 {
+		return errors.WithMsg(errors.NotFound, "字典类型不存在")
+	}
+	return nil
+}
+
+// invalidateCache 字典修改已提交，清除缓存失败只记录日志，缓存会在过期后自动更新
+func (s *dictService) invalidateCache(ctx context.Context, typeCodes ...string) {
+	if err := s.cache.Invalidate(ctx, typeCodes...); err != nil {
+		s.log.Warn("清除字典缓存失败", zap.Strings("type_codes", typeCodes), zap.Error(err))
+	}
 }
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wxlbd/gin-casbin-admin/internal/dto"
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
+	"github.com/wxlbd/gin-casbin-admin/pkg/otelx"
+	"gorm.io/gorm"
+)
+
+// DictBundleVersion 当前字典包格式版本，格式不兼容地变化时递增
+const DictBundleVersion = 1
+
+// dictBundlePlan 字典包导入计划
+type dictBundlePlan struct {
+	createTypes []*model.DictType
+	updateTypes []*model.DictType
+	createData  []*model.DictDatum
+	updateData  []*model.DictDatum
+	// 回收站中与字典包匹配的记录，先恢复再按 updateTypes、updateData 更新
+	restoreTypeIDs []int64
+	restoreDataIDs []int64
+	resp           *dto.DictBundleImportResponse
+}
+
+func (s *dictService) ExportBundle(ctx context.Context, codes []string) (bundle *dto.DictBundle, err error) {
+	ctx, span := otelx.Start(ctx, "DictService.ExportBundle")
+	defer func() { otelx.End(span, err) }()
+	types, err := s.typeRepo.FindByCodes(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+	typeMap := make(map[string]*model.DictType, len(types))
+	for _, t := range types {
+		typeMap[t.Code] = t
+	}
+	for _, code := range codes {
+		if typeMap[code] == nil {
+			return nil, errors.WithMsg(errors.NotFound, fmt.Sprintf("字典类型 %s 不存在", code))
+		}
+	}
+
+	list, err := s.dataRepo.FindByTypeCodes(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+	dataMap := make(map[string][]*dto.DictBundleData, len(codes))
+	for _, d := range list {
+		dataMap[d.TypeCode] = append(dataMap[d.TypeCode], &dto.DictBundleData{
+			Label:  d.Label,
+			Value:  d.Value,
+			Status: d.Status,
+			Sort:   d.Sort,
+			Remark: d.Remark,
+		})
+	}
+
+	bundle = &dto.DictBundle{
+		Version:    DictBundleVersion,
+		ExportedAt: time.Now(),
+		Types:      make([]*dto.DictBundleType, 0, len(codes)),
+	}
+	// 按请求的顺序输出，便于比较不同环境导出的文件
+	for _, code := range codes {
+		t := typeMap[code]
+		bundle.Types = append(bundle.Types, &dto.DictBundleType{
+			Code:   t.Code,
+			Name:   t.Name,
+			Status: t.Status,
+			Sort:   t.Sort,
+			Remark: t.Remark,
+			Data:   dataMap[code],
+		})
+	}
+	return bundle, nil
+}
+
+// ImportBundle 按类型编码和字典值更新或新增，包中没有的字典不会被删除
+func (s *dictService) ImportBundle(ctx context.Context, bundle *dto.DictBundle, dryRun bool) (resp *dto.DictBundleImportResponse, err error) {
+	ctx, span := otelx.Start(ctx, "DictService.ImportBundle")
+	defer func() { otelx.End(span, err) }()
+	if err := validateDictBundle(bundle); err != nil {
+		return nil, err
+	}
+	codes := make([]string, len(bundle.Types))
+	for i, t := range bundle.Types {
+		codes[i] = t.Code
+	}
+	// 包含回收站中的记录，编码和值的唯一约束对已删除的记录同样生效
+	types, err := s.typeRepo.FindByCodesWithDeleted(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.dataRepo.FindByTypeCodesWithDeleted(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := planDictBundle(bundle, types, list)
+	plan.resp.DryRun = dryRun
+	if dryRun || plan.empty() {
+		return plan.resp, nil
+	}
+
+	err = s.repo.Transaction(func(r Repository) error {
+		// 锁定已存在的类型，计划生成后被并发删除的类型不再写入字典数据
+		locked, err := r.DictType().FindByCodesForUpdate(ctx, codes)
+		if err != nil {
+			return err
+		}
+		live := 0
+		for _, t := range types {
+			if !t.DeletedAt.Valid {
+				live++
+			}
+		}
+		if len(locked) != live {
+			return errors.WithMsg(errors.InvalidParam, "字典类型已被修改，请重新导入")
+		}
+		if len(plan.restoreTypeIDs) > 0 {
+			if err := r.DictType().Restore(ctx, plan.restoreTypeIDs...); err != nil {
+				return err
+			}
+		}
+		if len(plan.restoreDataIDs) > 0 {
+			if err := r.DictData().Restore(ctx, plan.restoreDataIDs...); err != nil {
+				return err
+			}
+		}
+		for _, t := range plan.createTypes {
+			if err := r.DictType().Create(ctx, t); err != nil {
+				return err
+			}
+		}
+		for _, t := range plan.updateTypes {
+			if err := r.DictType().Update(ctx, t); err != nil {
+				return err
+			}
+		}
+		for _, d := range plan.createData {
+			if err := r.DictData().Create(ctx, d); err != nil {
+				return err
+			}
+		}
+		for _, d := range plan.updateData {
+			if err := r.DictData().Update(ctx, d); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateCache(ctx, codes...)
+	return plan.resp, nil
+}
+
+// validateDictBundle 校验字典包内容，未填写的状态默认为正常
+func validateDictBundle(bundle *dto.DictBundle) error {
+	if bundle.Version != DictBundleVersion {
+		return errors.WithMsg(errors.InvalidParam, fmt.Sprintf("不支持的字典包版本 %d", bundle.Version))
+	}
+	if len(bundle.Types) == 0 {
+		return errors.WithMsg(errors.InvalidParam, "字典包中没有字典类型")
+	}
+	codes := make(map[string]bool, len(bundle.Types))
+	for _, t := range bundle.Types {
+		if t == nil || t.Code == "" || t.Name == "" {
+			return errors.WithMsg(errors.InvalidParam, "字典类型编码和名称不能为空")
+		}
+		if codes[t.Code] {
+			return errors.WithMsg(errors.InvalidParam, fmt.Sprintf("字典类型 %s 重复", t.Code))
+		}
+		codes[t.Code] = true
+		if t.Status == 0 {
+			t.Status = 1
+		}
+		if t.Status != 1 && t.Status != 2 {
+			return errors.WithMsg(errors.InvalidParam, fmt.Sprintf("字典类型 %s 的状态无效", t.Code))
+		}
+
+		values := make(map[string]bool, len(t.Data))
+		for _, d := range t.Data {
+			if d == nil || d.Value == "" || d.Label == "" {
+				return errors.WithMsg(errors.InvalidParam, fmt.Sprintf("字典类型 %s 中存在标签或值为空的数据", t.Code))
+			}
+			if values[d.Value] {
+				return errors.WithMsg(errors.InvalidParam, fmt.Sprintf("字典类型 %s 中的值 %s 重复", t.Code, d.Value))
+			}
+			values[d.Value] = true
+			if d.Status == 0 {
+				d.Status = 1
+			}
+			if d.Status != 1 && d.Status != 2 {
+				return errors.WithMsg(errors.InvalidParam, fmt.Sprintf("字典类型 %s 中值 %s 的状态无效", t.Code, d.Value))
+			}
+		}
+	}
+	return nil
+}
+
+// planDictBundle 对比字典包与当前数据，生成变更预览和需要写入的记录
+func planDictBundle(bundle *dto.DictBundle, types []*model.DictType, list []*model.DictDatum) *dictBundlePlan {
+	existTypes := make(map[string]*model.DictType, len(types))
+	for _, t := range types {
+		existTypes[t.Code] = t
+	}
+	existData := make(map[string]map[string]*model.DictDatum, len(types))
+	for _, d := range list {
+		if existData[d.TypeCode] == nil {
+			existData[d.TypeCode] = make(map[string]*model.DictDatum)
+		}
+		existData[d.TypeCode][d.Value] = d
+	}
+
+	plan := &dictBundlePlan{resp: &dto.DictBundleImportResponse{Types: make([]*dto.DictTypeDiff, 0, len(bundle.Types))}}
+	for _, bt := range bundle.Types {
+		diff := &dto.DictTypeDiff{Code: bt.Code, Name: bt.Name}
+		if exist, ok := existTypes[bt.Code]; ok {
+			var changes []*dto.DictFieldChange
+			changes = appendDictChange(changes, "name", exist.Name, bt.Name)
+			changes = appendDictChange(changes, "status", exist.Status, bt.Status)
+			changes = appendDictChange(changes, "sort", exist.Sort, bt.Sort)
+			changes = appendDictChange(changes, "remark", exist.Remark, bt.Remark)
+			if len(changes) > 0 {
+				t := *exist
+				t.Name, t.Status, t.Sort, t.Remark = bt.Name, bt.Status, bt.Sort, bt.Remark
+				// Save 会写入全部字段，清除删除时间避免恢复后又被标记为删除
+				t.DeletedAt = gorm.DeletedAt{}
+				plan.updateTypes = append(plan.updateTypes, &t)
+			}
+			if exist.DeletedAt.Valid {
+				plan.restoreTypeIDs = append(plan.restoreTypeIDs, exist.ID)
+				diff.Action, diff.Changes = dto.DictDiffRestore, changes
+				plan.resp.TypeRestored++
+			} else if len(changes) > 0 {
+				diff.Action, diff.Changes = dto.DictDiffUpdate, changes
+				plan.resp.TypeUpdated++
+			} else {
+				diff.Action = dto.DictDiffUnchanged
+				plan.resp.TypeUnchanged++
+			}
+		} else {
+			plan.createTypes = append(plan.createTypes, &model.DictType{
+				Code:   bt.Code,
+				Name:   bt.Name,
+				Status: bt.Status,
+				Sort:   bt.Sort,
+				Remark: bt.Remark,
+			})
+			diff.Action = dto.DictDiffCreate
+			plan.resp.TypeCreated++
+		}
+
+		for _, bd := range bt.Data {
+			dataDiff := &dto.DictDataDiff{Value: bd.Value, Label: bd.Label}
+			if exist, ok := existData[bt.Code][bd.Value]; ok {
+				var changes []*dto.DictFieldChange
+				changes = appendDictChange(changes, "label", exist.Label, bd.Label)
+				changes = appendDictChange(changes, "status", exist.Status, bd.Status)
+				changes = appendDictChange(changes, "sort", exist.Sort, bd.Sort)
+				changes = appendDictChange(changes, "remark", exist.Remark, bd.Remark)
+				if len(changes) == 0 && !exist.DeletedAt.Valid {
+					plan.resp.DataUnchanged++
+					continue
+				}
+				if len(changes) > 0 {
+					d := *exist
+					d.Label, d.Status, d.Sort, d.Remark = bd.Label, bd.Status, bd.Sort, bd.Remark
+					d.DeletedAt = gorm.DeletedAt{}
+					plan.updateData = append(plan.updateData, &d)
+				}
+				if exist.DeletedAt.Valid {
+					plan.restoreDataIDs = append(plan.restoreDataIDs, exist.ID)
+					dataDiff.Action, dataDiff.Changes = dto.DictDiffRestore, changes
+					plan.resp.DataRestored++
+				} else {
+					dataDiff.Action, dataDiff.Changes = dto.DictDiffUpdate, changes
+					plan.resp.DataUpdated++
+				}
+			} else {
+				plan.createData = append(plan.createData, &model.DictDatum{
+					TypeCode: bt.Code,
+					Label:    bd.Label,
+					Value:    bd.Value,
+					Status:   bd.Status,
+					Sort:     bd.Sort,
+					Remark:   bd.Remark,
+				})
+				dataDiff.Action = dto.DictDiffCreate
+				plan.resp.DataCreated++
+			}
+			diff.Data = append(diff.Data, dataDiff)
+		}
+		plan.resp.Types = append(plan.resp.Types, diff)
+	}
+	return plan
+}
+
+func (p *dictBundlePlan) empty() bool {
+	return len(p.createTypes) == 0 && len(p.updateTypes) == 0 && len(p.createData) == 0 && len(p.updateData) == 0 &&
+		len(p.restoreTypeIDs) == 0 && len(p.restoreDataIDs) == 0
+}
+
+func appendDictChange[T comparable](changes []*dto.DictFieldChange, field string, old, new T) []*dto.DictFieldChange {
+	if old == new {
+		return changes
+	}
+	return append(changes, &dto.DictFieldChange{Field: field, Old: old, New: new})
+}
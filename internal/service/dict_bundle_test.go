@@ -0,0 +1,91 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wxlbd/gin-casbin-admin/internal/dto"
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"gorm.io/gorm"
+)
+
+func TestValidateDictBundle(t *testing.T) {
+	tests := []struct {
+		name    string
+		bundle  *dto.DictBundle
+		wantErr bool
+	}{
+		{name: "valid", bundle: &dto.DictBundle{Version: DictBundleVersion, Types: []*dto.DictBundleType{
+			{Code: "gender", Name: "性别", Data: []*dto.DictBundleData{{Label: "男", Value: "1"}, {Label: "女", Value: "2"}}},
+		}}},
+		{name: "unsupported version", bundle: &dto.DictBundle{Version: 99, Types: []*dto.DictBundleType{{Code: "gender", Name: "性别"}}}, wantErr: true},
+		{name: "empty", bundle: &dto.DictBundle{Version: DictBundleVersion}, wantErr: true},
+		{name: "duplicate type", bundle: &dto.DictBundle{Version: DictBundleVersion, Types: []*dto.DictBundleType{
+			{Code: "gender", Name: "性别"}, {Code: "gender", Name: "性别"},
+		}}, wantErr: true},
+		{name: "duplicate value", bundle: &dto.DictBundle{Version: DictBundleVersion, Types: []*dto.DictBundleType{
+			{Code: "gender", Name: "性别", Data: []*dto.DictBundleData{{Label: "男", Value: "1"}, {Label: "女", Value: "1"}}},
+		}}, wantErr: true},
+		{name: "invalid status", bundle: &dto.DictBundle{Version: DictBundleVersion, Types: []*dto.DictBundleType{
+			{Code: "gender", Name: "性别", Status: 3},
+		}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDictBundle(tt.bundle)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateDictBundle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPlanDictBundle(t *testing.T) {
+	bundle := &dto.DictBundle{Version: DictBundleVersion, Types: []*dto.DictBundleType{
+		{Code: "gender", Name: "性别", Status: 1, Data: []*dto.DictBundleData{
+			{Label: "男", Value: "1", Status: 1, Sort: 1},
+			{Label: "女性", Value: "2", Status: 1, Sort: 2},
+			{Label: "未知", Value: "0", Status: 1, Sort: 3},
+			{Label: "其他", Value: "8", Status: 1, Sort: 8},
+		}},
+		{Code: "yes_no", Name: "是否", Status: 1, Data: []*dto.DictBundleData{{Label: "是", Value: "Y", Status: 1}}},
+	}}
+	types := []*model.DictType{{ID: 1, Code: "gender", Name: "性别", Status: 1}}
+	list := []*model.DictDatum{
+		{ID: 1, TypeCode: "gender", Label: "男", Value: "1", Status: 1, Sort: 1},
+		{ID: 2, TypeCode: "gender", Label: "女", Value: "2", Status: 1, Sort: 2},
+		{ID: 3, TypeCode: "gender", Label: "保密", Value: "9", Status: 1, Sort: 9},
+		{ID: 4, TypeCode: "gender", Label: "其他", Value: "8", Status: 1, Sort: 8, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}},
+	}
+
+	plan := planDictBundle(bundle, types, list)
+	resp := plan.resp
+	if resp.TypeCreated != 1 || resp.TypeUpdated != 0 || resp.TypeUnchanged != 1 {
+		t.Errorf("type counts = %d/%d/%d, want 1/0/1", resp.TypeCreated, resp.TypeUpdated, resp.TypeUnchanged)
+	}
+	if resp.DataCreated != 2 || resp.DataUpdated != 1 || resp.DataUnchanged != 1 {
+		t.Errorf("data counts = %d/%d/%d, want 2/1/1", resp.DataCreated, resp.DataUpdated, resp.DataUnchanged)
+	}
+	if len(plan.updateData) != 1 || plan.updateData[0].ID != 2 || plan.updateData[0].Label != "女性" {
+		t.Fatalf("updateData = %+v", plan.updateData)
+	}
+	if resp.DataRestored != 1 || len(plan.restoreDataIDs) != 1 || plan.restoreDataIDs[0] != 4 {
+		t.Errorf("restored = %d %v, want 1 [4]", resp.DataRestored, plan.restoreDataIDs)
+	}
+	if list[1].Label != "女" {
+		t.Errorf("existing record modified: %+v", list[1])
+	}
+	gender := resp.Types[0]
+	if gender.Action != dto.DictDiffUnchanged || len(gender.Data) != 3 {
+		t.Errorf("gender diff = %+v", gender)
+	}
+	if c := gender.Data[0].Changes; len(c) != 1 || c[0].Field != "label" || c[0].Old != "女" {
+		t.Errorf("gender changes = %+v", c)
+	}
+	if d := gender.Data[2]; d.Action != dto.DictDiffRestore || len(d.Changes) != 0 {
+		t.Errorf("restored diff = %+v", d)
+	}
+	if resp.Types[1].Action != dto.DictDiffCreate || len(plan.createData) != 2 {
+		t.Errorf("yes_no diff = %+v, createData = %d", resp.Types[1], len(plan.createData))
+	}
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+)
+
+const (
+	dictCachePrefix = "dict:data:"
+	// dictVersionPrefix 每次清除缓存时递增的版本号，回填前比较版本，避免覆盖期间发生的清除
+	dictVersionPrefix = "dict:version:"
+)
+
+// dictCacheSetScript 版本号与读取时一致才写入缓存，KEYS[1] 为数据键，KEYS[2] 为版本键，
+// ARGV[1] 为读取时的版本号（不存在时为空字符串），ARGV[2] 为数据，ARGV[3] 为过期毫秒数
+var dictCacheSetScript = redis.NewScript(`
+if (redis.call('GET', KEYS[2]) or '') ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+// dictCache 缓存各字典类型下启用的字典数据，Redis 为共享缓存，进程内缓存用于减少热点字典的网络往返
+type dictCache struct {
+	redis       *redis.Client
+	expire      time.Duration
+	localExpire time.Duration
+
+	mu    sync.RWMutex
+	local map[string]dictCacheEntry
+	// generation 每次清除进程内缓存时递增，读取期间发生清除时不写入进程内缓存
+	generation uint64
+}
+
+type dictCacheEntry struct {
+	items    []*model.DictDatum
+	expireAt time.Time
+}
+
+// dictCacheVersion 未命中时读取到的版本，回填缓存时用于判断期间是否发生过清除
+type dictCacheVersion struct {
+	local  uint64
+	remote map[string]string
+}
+
+func newDictCache(redisClient *redis.Client, expire, localExpire time.Duration) *dictCache {
+	return &dictCache{
+		redis:       redisClient,
+		expire:      expire,
+		localExpire: localExpire,
+		local:       make(map[string]dictCacheEntry),
+	}
+}
+
+// Get 读取缓存，返回命中的数据、未命中的类型编码和回填 Set 时需要传入的版本
+func (c *dictCache) Get(ctx context.Context, typeCodes []string) (map[string][]*model.DictDatum, []string, dictCacheVersion, error) {
+	hits := make(map[string][]*model.DictDatum, len(typeCodes))
+	var remote []string
+	c.mu.RLock()
+	version := dictCacheVersion{local: c.generation}
+	if c.localExpire > 0 {
+		now := time.Now()
+		for _, code := range typeCodes {
+			if e, ok := c.local[code]; ok && now.Before(e.expireAt) {
+				hits[code] = e.items
+			} else {
+				remote = append(remote, code)
+			}
+		}
+	} else {
+		remote = typeCodes
+	}
+	c.mu.RUnlock()
+	if len(remote) == 0 {
+		return hits, nil, version, nil
+	}
+
+	// 数据和版本号一次读取，版本号在数据之后返回
+	keys := make([]string, 0, len(remote)*2)
+	for _, code := range remote {
+		keys = append(keys, dictCachePrefix+code)
+	}
+	for _, code := range remote {
+		keys = append(keys, dictVersionPrefix+code)
+	}
+	vals, err := c.redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		return hits, remote, version, err
+	}
+	var misses []string
+	fetched := make(map[string][]*model.DictDatum)
+	version.remote = make(map[string]string, len(remote))
+	for i, code := range remote {
+		v, _ := vals[len(remote)+i].(string)
+		version.remote[code] = v
+		s, ok := vals[i].(string)
+		if !ok {
+			misses = append(misses, code)
+			continue
+		}
+		var items []*model.DictDatum
+		if err := json.Unmarshal([]byte(s), &items); err != nil {
+			misses = append(misses, code)
+			continue
+		}
+		hits[code] = items
+		fetched[code] = items
+	}
+	c.setLocal(fetched, version.local)
+	return hits, misses, version, nil
+}
+
+// Set 按 Get 返回的版本写入 data 中的类型，期间发生过清除的类型不写入。存在但没有数据或已禁用的类型以空列表缓存，
+// 不存在的类型由调用方排除在 data 之外，不会被缓存。Get 读取 Redis 失败时没有版本，不写入缓存
+func (c *dictCache) Set(ctx context.Context, data map[string][]*model.DictDatum, version dictCacheVersion) error {
+	if len(data) == 0 || version.remote == nil {
+		return nil
+	}
+	pipe := c.redis.Pipeline()
+	cmds := make(map[string]*redis.Cmd, len(data))
+	for code, items := range data {
+		v, ok := version.remote[code]
+		if !ok {
+			continue
+		}
+		if items == nil {
+			items = []*model.DictDatum{}
+		}
+		b, err := json.Marshal(items)
+		if err != nil {
+			return err
+		}
+		cmds[code] = dictCacheSetScript.Eval(ctx, pipe, []string{dictCachePrefix + code, dictVersionPrefix + code},
+			v, b, c.expire.Milliseconds())
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	written := make(map[string][]*model.DictDatum, len(cmds))
+	for code, cmd := range cmds {
+		if n, _ := cmd.Int(); n == 1 {
+			written[code] = data[code]
+		}
+	}
+	c.setLocal(written, version.local)
+	return nil
+}
+
+// Invalidate 清除指定类型编码的缓存并递增版本号，使清除前开始的读取不再回填
+func (c *dictCache) Invalidate(ctx context.Context, typeCodes ...string) error {
+	if len(typeCodes) == 0 {
+		return nil
+	}
+	c.mu.Lock()
+	c.generation++
+	for _, code := range typeCodes {
+		delete(c.local, code)
+	}
+	c.mu.Unlock()
+
+	pipe := c.redis.TxPipeline()
+	for _, code := range typeCodes {
+		pipe.Del(ctx, dictCachePrefix+code)
+		pipe.Incr(ctx, dictVersionPrefix+code)
+		// 版本号比数据保留更久即可，过期后键不存在同样与读取时的版本不一致
+		pipe.PExpire(ctx, dictVersionPrefix+code, 2*c.expire)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// setLocal 写入进程内缓存，generation 与读取时不一致说明期间发生过清除，放弃写入
+func (c *dictCache) setLocal(data map[string][]*model.DictDatum, generation uint64) {
+	if c.localExpire <= 0 || len(data) == 0 {
+		return
+	}
+	expireAt := time.Now().Add(c.localExpire)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation != generation {
+		return
+	}
+	for code, items := range data {
+		c.local[code] = dictCacheEntry{items: items, expireAt: expireAt}
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+)
+
+func newTestDictCache(t *testing.T, localExpire time.Duration) (*dictCache, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return newDictCache(client, time.Hour, localExpire), mr
+}
+
+func TestDictCache(t *testing.T) {
+	ctx := context.Background()
+	gender := []*model.DictDatum{{TypeCode: "gender", Label: "男", Value: "1"}}
+
+	t.Run("miss then hit", func(t *testing.T) {
+		c, _ := newTestDictCache(t, 0)
+		hits, misses, version, err := c.Get(ctx, []string{"gender", "status"})
+		if err != nil || len(hits) != 0 || len(misses) != 2 {
+			t.Fatalf("Get() = %v, %v, %v", hits, misses, err)
+		}
+		// 存在但没有数据的类型以空列表缓存
+		if err := c.Set(ctx, map[string][]*model.DictDatum{"gender": gender, "status": nil}, version); err != nil {
+			t.Fatal(err)
+		}
+		hits, misses, _, err = c.Get(ctx, []string{"gender", "status"})
+		if err != nil || len(misses) != 0 || len(hits["gender"]) != 1 || hits["gender"][0].Label != "男" || hits["status"] == nil {
+			t.Fatalf("Get() after Set = %v, %v, %v", hits, misses, err)
+		}
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		c, _ := newTestDictCache(t, time.Hour)
+		_, _, version, _ := c.Get(ctx, []string{"gender"})
+		if err := c.Set(ctx, map[string][]*model.DictDatum{"gender": gender}, version); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Invalidate(ctx, "gender"); err != nil {
+			t.Fatal(err)
+		}
+		if _, misses, _, err := c.Get(ctx, []string{"gender"}); err != nil || len(misses) != 1 {
+			t.Fatalf("Get() after Invalidate misses = %v, %v", misses, err)
+		}
+	})
+
+	t.Run("stale set after invalidate", func(t *testing.T) {
+		c, mr := newTestDictCache(t, time.Hour)
+		// 读取数据库期间字典被修改并清除缓存，读取到的旧数据不能回填
+		_, _, version, _ := c.Get(ctx, []string{"gender"})
+		if err := c.Invalidate(ctx, "gender"); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Set(ctx, map[string][]*model.DictDatum{"gender": gender}, version); err != nil {
+			t.Fatal(err)
+		}
+		if mr.Exists(dictCachePrefix + "gender") {
+			t.Error("stale data written to redis")
+		}
+		if _, misses, _, _ := c.Get(ctx, []string{"gender"}); len(misses) != 1 {
+			t.Error("stale data written to local cache")
+		}
+
+		// 其他实例清除缓存
+		other := newDictCache(redis.NewClient(&redis.Options{Addr: mr.Addr()}), time.Hour, 0)
+		_, _, version, _ = c.Get(ctx, []string{"gender"})
+		if err := other.Invalidate(ctx, "gender"); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Set(ctx, map[string][]*model.DictDatum{"gender": gender}, version); err != nil {
+			t.Fatal(err)
+		}
+		if mr.Exists(dictCachePrefix + "gender") {
+			t.Error("stale data written to redis after invalidation by another instance")
+		}
+	})
+
+	t.Run("local ttl", func(t *testing.T) {
+		c, mr := newTestDictCache(t, 100*time.Millisecond)
+		_, _, version, _ := c.Get(ctx, []string{"gender"})
+		if err := c.Set(ctx, map[string][]*model.DictDatum{"gender": gender}, version); err != nil {
+			t.Fatal(err)
+		}
+		// 删除 Redis 中的数据后仍从进程内缓存命中，过期后未命中
+		mr.Del(dictCachePrefix + "gender")
+		if _, misses, _, _ := c.Get(ctx, []string{"gender"}); len(misses) != 0 {
+			t.Fatal("local cache should hit before expiry")
+		}
+		time.Sleep(150 * time.Millisecond)
+		if _, misses, _, _ := c.Get(ctx, []string{"gender"}); len(misses) != 1 {
+			t.Fatal("local cache should miss after expiry")
+		}
+	})
+}
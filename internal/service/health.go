@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/wxlbd/gin-casbin-admin/internal/dto"
+	"github.com/wxlbd/gin-casbin-admin/internal/handler"
+	httpx "github.com/wxlbd/gin-casbin-admin/pkg/http"
+	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"go.uber.org/zap"
+)
+
+// healthCheckTimeout 单个依赖检查的超时时间，应小于探针的超时时间
+const healthCheckTimeout = 2 * time.Second
+
+// errNoPolicy 未加载任何权限策略
+var errNoPolicy = errors.New("未加载任何权限策略")
+
+type healthService struct {
+	logger   *log.Logger
+	state    *httpx.State
+	repo     Repository
+	redis    *redis.Client
+	enforcer *casbin.Enforcer
+}
+
+func NewHealthService(logger *log.Logger, state *httpx.State, repo Repository, redisClient *redis.Client, enforcer *casbin.Enforcer) handler.HealthService {
+	return &healthService{
+		logger:   logger,
+		state:    state,
+		repo:     repo,
+		redis:    redisClient,
+		enforcer: enforcer,
+	}
+}
+
+// Ready 并发检查数据库、Redis 和权限策略，任一失败时服务不可用。
+// 接口无需认证，错误可能包含连接地址和驱动信息，只返回概括的失败原因，详细错误记录到日志
+func (s *healthService) Ready(ctx context.Context) *dto.ReadinessResponse {
+	if s.state.Draining() {
+		return &dto.ReadinessResponse{Status: dto.HealthStatusDraining}
+	}
+
+	checks := map[string]func(ctx context.Context) error{
+		"database": s.pingDatabase,
+		"redis":    s.pingRedis,
+		"casbin":   s.checkPolicy,
+	}
+	resp := &dto.ReadinessResponse{Status: dto.HealthStatusOK, Checks: make(map[string]*dto.DependencyCheck, len(checks))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+			start := time.Now()
+			err := check(ctx)
+			result := &dto.DependencyCheck{Status: dto.HealthStatusOK, Latency: time.Since(start).String()}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				s.logger.WithContext(ctx).Warn("就绪检查失败", zap.String("dependency", name), zap.Error(err))
+				result.Status, result.Reason = dto.HealthStatusFail, healthFailReason(err)
+				resp.Status = dto.HealthStatusUnavailable
+			}
+			resp.Checks[name] = result
+		}()
+	}
+	wg.Wait()
+	return resp
+}
+
+// healthFailReason 返回不含错误细节的失败原因
+func healthFailReason(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, errNoPolicy):
+		return "no_policy"
+	default:
+		return "check_failed"
+	}
+}
+
+func (s *healthService) pingDatabase(ctx context.Context) error {
+	sqlDB, err := s.repo.DB().DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+func (s *healthService) pingRedis(ctx context.Context) error {
+	return s.redis.Ping(ctx).Err()
+}
+
+// checkPolicy 权限策略为空说明未加载成功或尚未执行 seed 初始化，此时除超级管理员外的请求都会被拒绝
+func (s *healthService) checkPolicy(ctx context.Context) error {
+	policies, err := s.enforcer.GetPolicy()
+	if err != nil {
+		return err
+	}
+	if len(policies) == 0 {
+		return errNoPolicy
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"testing"
+)
+
+func Test_verifyCodeChallenge(t *testing.T) {
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	sum := sha256.Sum256([]byte(verifier))
+	s256 := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	tests := []struct {
+		name      string
+		challenge string
+		method    string
+		verifier  string
+		want      bool
+	}{
+		{name: "no pkce", want: true},
+		{name: "s256 ok", challenge: s256, method: "S256", verifier: verifier, want: true},
+		{name: "s256 wrong verifier", challenge: s256, method: "S256", verifier: "other", want: false},
+		{name: "s256 missing verifier", challenge: s256, method: "S256", want: false},
+		{name: "plain ok", challenge: "abc", method: "plain", verifier: "abc", want: true},
+		{name: "plain wrong", challenge: "abc", method: "plain", verifier: "abd", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyCodeChallenge(tt.challenge, tt.method, tt.verifier); got != tt.want {
+				t.Errorf("verifyCodeChallenge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_redirectWithParams(t *testing.T) {
+	got := redirectWithParams("https://app.example.com/cb?from=admin", url.Values{
+		"code":  {"abc"},
+		"state": {""},
+	})
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatalf("parse %q: %v", got, err)
+	}
+	q := u.Query()
+	if q.Get("from") != "admin" || q.Get("code") != "abc" || q.Has("state") {
+		t.Errorf("redirectWithParams() = %q", got)
+	}
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/wxlbd/gin-casbin-admin/internal/handler"
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
+	"github.com/wxlbd/gin-casbin-admin/pkg/jwtx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"github.com/wxlbd/gin-casbin-admin/pkg/oidcx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/otelx"
+	"go.uber.org/zap"
+)
+
+const (
+	oidcStatePrefix = "oidc:state:"
+
+	defaultOIDCStateExpire = 10 * time.Minute
+)
+
+// oidcState 登录流程中需要在回调时校验的数据
+type oidcState struct {
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+type oidcService struct {
+	repo   Repository
+	redis  *redis.Client
+	jwt    *jwtx.JWT
+	client *oidcx.Client
+	logger *log.Logger
+	config config.OIDCConfig
+}
+
+func NewOIDCService(cfg *config.Config, logger *log.Logger, repo Repository, redisClient *redis.Client, jwt *jwtx.JWT, client *oidcx.Client) handler.OIDCService {
+	c := cfg.OIDC
+	if c.StateExpire <= 0 {
+		c.StateExpire = defaultOIDCStateExpire
+	}
+	return &oidcService{
+		repo:   repo,
+		redis:  redisClient,
+		jwt:    jwt,
+		client: client,
+		logger: logger,
+		config: c,
+	}
+}
+
+// AuthURL 生成 state、nonce 和 PKCE verifier 并返回身份提供方授权地址
+func (s *oidcService) AuthURL(ctx context.Context) (authURL, state string, err error) {
+	ctx, span := otelx.Start(ctx, "OIDCService.AuthURL")
+	defer func() { otelx.End(span, err) }()
+	if !s.client.Enabled() {
+		return "", "", errors.WithMsg(errors.Forbidden, "未启用单点登录")
+	}
+	state, err = randomToken()
+	if err != nil {
+		return "", "", errors.WithMsg(errors.ServerError, "生成登录状态失败")
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return "", "", errors.WithMsg(errors.ServerError, "生成登录状态失败")
+	}
+	st := oidcState{Nonce: nonce, Verifier: oidcx.GenerateVerifier()}
+	data, _ := json.Marshal(st)
+	if err := s.redis.Set(ctx, oidcStatePrefix+state, data, s.config.StateExpire).Err(); err != nil {
+		return "", "", err
+	}
+
+	authURL, err = s.client.AuthCodeURL(ctx, state, st.Nonce, st.Verifier)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("获取身份提供方配置失败", zap.Error(err))
+		return "", "", errors.WithMsg(errors.ExternalServiceError, "身份提供方不可用")
+	}
+	return authURL, state, nil
+}
+
+// Callback 校验 state 后用授权码换取 ID Token，关联本地用户并签发系统令牌
+func (s *oidcService) Callback(ctx context.Context, code, state string) (accessToken, refreshToken string, err error) {
+	ctx, span := otelx.Start(ctx, "OIDCService.Callback")
+	defer func() { otelx.End(span, err) }()
+	if !s.client.Enabled() {
+		return "", "", errors.WithMsg(errors.Forbidden, "未启用单点登录")
+	}
+	// GETDEL 保证 state 只能使用一次
+	val, err := s.redis.GetDel(ctx, oidcStatePrefix+state).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", "", errors.WithMsg(errors.TokenInvalid, "登录状态无效或已过期")
+		}
+		return "", "", err
+	}
+	var st oidcState
+	if err := json.Unmarshal([]byte(val), &st); err != nil {
+		return "", "", errors.WithMsg(errors.TokenInvalid, "登录状态无效或已过期")
+	}
+
+	claims, err := s.client.Exchange(ctx, code, st.Verifier, st.Nonce)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("单点登录认证失败", zap.Error(err))
+		return "", "", errors.WithMsg(errors.Unauthorized, "单点登录认证失败")
+	}
+
+	user, err := s.resolveUser(ctx, claims)
+	if err != nil {
+		return "", "", err
+	}
+	if user.Status != 1 {
+		return "", "", errors.WithMsg(errors.Forbidden, "账号已停用")
+	}
+
+	accessToken, refreshToken, err = s.jwt.GenerateToken(user.ID, user.Username)
+	if err != nil {
+		return "", "", err
+	}
+	user.LoginTime = time.Now()
+	if err := s.repo.User().Update(ctx, user); err != nil {
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
+}
+
+// resolveUser 查找外部身份关联的用户，首次登录时按配置关联已有用户或自动创建用户
+func (s *oidcService) resolveUser(ctx context.Context, claims *oidcx.Claims) (*model.User, error) {
+	identity, err := s.repo.UserIdentity().FindBySubject(ctx, claims.Issuer, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if identity != nil {
+		return s.repo.User().FindByID(ctx, identity.UserID)
+	}
+
+	var user *model.User
+	err = s.repo.Transaction(func(r Repository) error {
+		// 仅信任身份提供方已验证的邮箱
+		if s.config.LinkByEmail && claims.EmailVerified && claims.Email != "" {
+			users, err := r.User().ListByEmail(ctx, claims.Email, 2)
+			if err != nil {
+				return err
+			}
+			// 多个用户使用同一邮箱时无法确定关联哪一个
+			if len(users) > 1 {
+				return errors.WithMsg(errors.Unauthorized, "邮箱对应多个系统用户，无法自动关联")
+			}
+			if len(users) == 1 {
+				// 已关联其他外部身份的用户不能再按邮箱关联
+				linked, err := r.UserIdentity().ExistsByUserID(ctx, users[0].ID)
+				if err != nil {
+					return err
+				}
+				if linked {
+					return errors.WithMsg(errors.Unauthorized, "邮箱对应的系统用户已关联其他外部账号")
+				}
+				user = users[0]
+			}
+		}
+		if user == nil {
+			if !s.config.AutoCreate {
+				return errors.WithMsg(errors.Unauthorized, "外部账号未关联系统用户")
+			}
+			if user, err = s.createUser(ctx, r, claims); err != nil {
+				return err
+			}
+		}
+		return r.UserIdentity().Create(ctx, &model.UserIdentity{
+			UserID:   user.ID,
+			Provider: claims.Issuer,
+			Subject:  claims.Subject,
+			Email:    claims.Email,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.WithContext(ctx).Info("外部账号已关联用户", zap.String("subject", claims.Subject), zap.Uint64("user_id", user.ID))
+	return user, nil
+}
+
+// createUser 按 ID Token 声明创建用户并分配默认角色，用户没有本地密码
+func (s *oidcService) createUser(ctx context.Context, r Repository, claims *oidcx.Claims) (*model.User, error) {
+	username := claims.Username
+	if username == "" {
+		username = claims.Email
+	}
+	if username == "" {
+		return nil, errors.WithMsg(errors.ValidationFailed, "身份提供方未返回用户名")
+	}
+	// 回收站中的用户同样占用用户名
+	exist, err := r.User().FindByUsernameWithDeleted(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if exist != nil {
+		return nil, errors.WithMsg(errors.AlreadyExists, "用户名已存在，无法自动创建用户")
+	}
+
+	user := &model.User{
+		Username: username,
+		Nickname: claims.Name,
+		Email:    claims.Email,
+		Status:   1,
+		Remark:   "由单点登录自动创建",
+	}
+	if err := r.User().Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if len(s.config.DefaultRoles) > 0 {
+		roles, err := r.Role().FindByCodes(ctx, s.config.DefaultRoles...)
+		if err != nil {
+			return nil, err
+		}
+		userRoles := make([]*model.UserRoles, 0, len(roles))
+		for _, role := range roles {
+			userRoles = append(userRoles, &model.UserRoles{UserID: user.ID, RoleID: role.ID})
+		}
+		if len(userRoles) > 0 {
+			if err := r.UserRole().Create(ctx, userRoles...); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return user, nil
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/wxlbd/gin-casbin-admin/internal/handler"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
+	"github.com/wxlbd/gin-casbin-admin/pkg/jwtx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"github.com/wxlbd/gin-casbin-admin/pkg/mailx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/otelx"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	passwordResetTokenPrefix = "password:reset:token:"
+	passwordResetLimitPrefix = "password:reset:limit:"
+
+	defaultResetTokenExpire = 30 * time.Minute
+	defaultResetRateLimit   = 3
+	defaultResetRateWindow  = time.Hour
+)
+
+type passwordResetService struct {
+	repo   Repository
+	redis  *redis.Client
+	mailer mailx.Mailer
+	jwt    *jwtx.JWT
+	logger *log.Logger
+	// config 找回密码配置，配置重新加载时更新频率限制
+	config atomic.Pointer[config.PasswordResetConfig]
+}
+
+func NewPasswordResetService(cfg *config.Config, watcher *config.Watcher, logger *log.Logger, repo Repository, redisClient *redis.Client, mailer mailx.Mailer, jwt *jwtx.JWT) handler.PasswordResetService {
+	s := &passwordResetService{
+		repo:   repo,
+		redis:  redisClient,
+		mailer: mailer,
+		jwt:    jwt,
+		logger: logger,
+	}
+	c := passwordResetConfig(cfg.PasswordReset)
+	s.config.Store(&c)
+	watcher.Subscribe(func(_, next *config.Config) {
+		c := *s.config.Load()
+		reloaded := passwordResetConfig(next.PasswordReset)
+		c.RateLimit, c.RateWindow = reloaded.RateLimit, reloaded.RateWindow
+		s.config.Store(&c)
+	})
+	return s
+}
+
+// passwordResetConfig 补全找回密码配置的默认值
+func passwordResetConfig(c config.PasswordResetConfig) config.PasswordResetConfig {
+	if c.TokenExpire <= 0 {
+		c.TokenExpire = defaultResetTokenExpire
+	}
+	if c.RateLimit <= 0 {
+		c.RateLimit = defaultResetRateLimit
+	}
+	if c.RateWindow <= 0 {
+		c.RateWindow = defaultResetRateWindow
+	}
+	return c
+}
+
+// Forgot 为邮箱对应的用户签发重置令牌并发送邮件。
+// 邮箱不存在、对应多个账号或账号已停用时同样返回成功，避免通过该接口枚举账号。
+func (s *passwordResetService) Forgot(ctx context.Context, email string) (err error) {
+	ctx, span := otelx.Start(ctx, "PasswordResetService.Forgot")
+	defer func() { otelx.End(span, err) }()
+	email = strings.ToLower(strings.TrimSpace(email))
+	if err := s.checkRateLimit(ctx, email); err != nil {
+		return err
+	}
+
+	users, err := s.repo.User().ListByEmail(ctx, email, 2)
+	if err != nil {
+		return err
+	}
+	// 邮箱不唯一，匹配到多个账号时无法确定重置哪一个
+	if len(users) != 1 || users[0].Status != 1 {
+		s.logger.WithContext(ctx).Info("找回密码邮箱未匹配到唯一的可用账号", zap.String("email", email), zap.Int("matched", len(users)))
+		return nil
+	}
+	user := users[0]
+
+	token, err := randomToken()
+	if err != nil {
+		return errors.WithMsg(errors.ServerError, "生成重置令牌失败")
+	}
+	key := passwordResetTokenPrefix + hashToken(token)
+	if err := s.redis.Set(ctx, key, user.ID, s.config.Load().TokenExpire).Err(); err != nil {
+		return err
+	}
+
+	link := s.resetLink(token)
+	body := fmt.Sprintf(`<p>%s，您好：</p>
+<p>我们收到了重置您账号密码的请求，请在 %d 分钟内点击下方链接设置新密码：</p>
+<p><a href="%s">%s</a></p>
+<p>如果这不是您本人的操作，请忽略此邮件，您的密码不会被修改。</p>`,
+		html.EscapeString(user.Username), int(s.config.Load().TokenExpire.Minutes()), html.EscapeString(link), html.EscapeString(link))
+
+	if err := s.mailer.Send(ctx, &mailx.Message{
+		To:      []string{user.Email},
+		Subject: "重置密码",
+		Body:    body,
+		HTML:    true,
+	}); err != nil {
+		s.logger.WithContext(ctx).Error("发送重置密码邮件失败", zap.Uint64("user_id", user.ID), zap.Error(err))
+		s.redis.Del(ctx, key)
+		return errors.WithMsg(errors.ExternalServiceError, "邮件发送失败，请稍后重试")
+	}
+	return nil
+}
+
+// Reset 校验重置令牌并设置新密码，令牌使用后立即失效，同时吊销该用户已签发的所有登录令牌。
+// 令牌签发后账号被停用时拒绝重置
+func (s *passwordResetService) Reset(ctx context.Context, token, newPassword string) (err error) {
+	ctx, span := otelx.Start(ctx, "PasswordResetService.Reset")
+	defer func() { otelx.End(span, err) }()
+	// GETDEL 保证令牌只能被使用一次
+	val, err := s.redis.GetDel(ctx, passwordResetTokenPrefix+hashToken(token)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return errors.WithMsg(errors.TokenInvalid, "重置链接无效或已过期")
+		}
+		return err
+	}
+	userID, err := strconv.ParseUint(val, 10, 64)
+	if err != nil {
+		return errors.WithMsg(errors.TokenInvalid, "重置链接无效或已过期")
+	}
+
+	user, err := s.repo.User().FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.WithMsg(errors.NotFound, "用户不存在")
+	}
+	if user.Status != 1 {
+		return errors.WithMsg(errors.Forbidden, "账号已停用")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.WithMsg(errors.ServerError, "密码加密失败")
+	}
+	user.Password = string(hashedPassword)
+	if err := s.repo.User().Update(ctx, user); err != nil {
+		return err
+	}
+
+	return s.jwt.RevokeUserTokens(ctx, user.ID)
+}
+
+// checkRateLimit 限制同一邮箱在时间窗口内的请求次数
+func (s *passwordResetService) checkRateLimit(ctx context.Context, email string) error {
+	key := passwordResetLimitPrefix + email
+	c := s.config.Load()
+	// 先以 SET NX 创建带过期时间的计数再自增，避免自增成功而设置过期时间失败时该邮箱被永久限制
+	var incr *redis.IntCmd
+	if _, err := s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SetNX(ctx, key, 0, c.RateWindow)
+		incr = pipe.Incr(ctx, key)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if incr.Val() > int64(c.RateLimit) {
+		return errors.WithMsg(errors.TooManyRequests, "请求过于频繁，请稍后再试")
+	}
+	return nil
+}
+
+func (s *passwordResetService) resetLink(token string) string {
+	resetURL := s.config.Load().ResetURL
+	sep := "?"
+	if strings.Contains(resetURL, "?") {
+		sep = "&"
+	}
+	return resetURL + sep + "token=" + url.QueryEscape(token)
+}
+
+// randomToken 生成 32 字节随机令牌的十六进制表示
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken Redis 中只保存令牌摘要，避免泄露后可直接使用
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
+)
+
+// permissionIndexTTL 权限索引的最长缓存时间，多实例部署时其他实例修改的菜单在此时间后生效
+const permissionIndexTTL = time.Minute
+
+// permissionIndex 按 "方法 路由模板" 索引按钮菜单的权限标识，数据来自菜单表。
+// 菜单变更后调用 Invalidate，下次查询时重新加载
+type permissionIndex struct {
+	load func(ctx context.Context) ([]*model.SysMenu, error)
+
+	mu       sync.Mutex
+	codes    map[string]string
+	loadedAt time.Time
+	// generation 每次失效加一，加载期间发生失效时丢弃加载结果，避免缓存变更前的数据
+	generation uint64
+}
+
+func newPermissionIndex(load func(ctx context.Context) ([]*model.SysMenu, error)) *permissionIndex {
+	return &permissionIndex{load: load}
+}
+
+// Lookup 查询接口对应的权限标识，没有对应的按钮菜单或加载失败时返回空字符串
+func (p *permissionIndex) Lookup(ctx context.Context, method, route string) string {
+	p.mu.Lock()
+	if p.codes != nil && time.Since(p.loadedAt) < permissionIndexTTL {
+		code := p.codes[method+" "+route]
+		p.mu.Unlock()
+		return code
+	}
+	generation := p.generation
+	p.mu.Unlock()
+
+	menus, err := p.load(ctx)
+	if err != nil {
+		return ""
+	}
+	codes := make(map[string]string)
+	for _, menu := range menus {
+		if types.MenuType(menu.MenuType) != types.MenuTypeButton {
+			continue
+		}
+		if path, m := convertMenuToAPI(menu.Auths); path != "" {
+			codes[m+" "+path] = menu.Auths
+		}
+	}
+
+	p.mu.Lock()
+	if generation == p.generation {
+		p.codes, p.loadedAt = codes, time.Now()
+	}
+	p.mu.Unlock()
+	return codes[method+" "+route]
+}
+
+// Invalidate 使索引失效，菜单新增、修改、删除或恢复后调用
+func (p *permissionIndex) Invalidate() {
+	p.mu.Lock()
+	p.codes = nil
+	p.generation++
+	p.mu.Unlock()
+}
@@ -6,6 +6,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
 )
 
 type DictTypeRepository interface {
@@ -14,7 +15,26 @@ type DictTypeRepository interface {
 	Delete(ctx context.Context, ids ...int64) error
 	FindByID(ctx context.Context, id int64) (*model.DictType, error)
 	FindByCode(ctx context.Context, code string) (*model.DictType, error)
+	// FindByIDs 根据ID列表查询字典类型
+	FindByIDs(ctx context.Context, ids ...int64) ([]*model.DictType, error)
+	// FindByCodes 根据编码列表查询字典类型
+	FindByCodes(ctx context.Context, codes []string) ([]*model.DictType, error)
+	// FindByIDsForUpdate 根据ID列表查询字典类型并加排他锁，需在事务中调用。
+	// 删除类型和新增、恢复、改挂其下的字典数据都先锁定类型，保证二者串行；SQLite 没有行锁，由库级写锁串行
+	FindByIDsForUpdate(ctx context.Context, ids ...int64) ([]*model.DictType, error)
+	// FindByCodesForUpdate 根据编码列表查询字典类型并加排他锁，需在事务中调用，见 FindByIDsForUpdate
+	FindByCodesForUpdate(ctx context.Context, codes []string) ([]*model.DictType, error)
+	// FindByCodesWithDeleted 根据编码列表查询字典类型，包含回收站中的记录
+	FindByCodesWithDeleted(ctx context.Context, codes []string) ([]*model.DictType, error)
 	List(ctx context.Context, query *model.DictTypeQuery) ([]*model.DictType, int64, error)
+	// ListDeleted 分页查询回收站中的字典类型，最近删除的在前
+	ListDeleted(ctx context.Context, page *types.PageParam) ([]*model.DictType, int64, error)
+	// FindDeletedByIDs 根据ID列表查询回收站中的字典类型
+	FindDeletedByIDs(ctx context.Context, ids ...int64) ([]*model.DictType, error)
+	// Restore 恢复回收站中的字典类型
+	Restore(ctx context.Context, ids ...int64) error
+	// Purge 彻底删除回收站中的字典类型
+	Purge(ctx context.Context, ids ...int64) error
 }
 
 type DictDataRepository interface {
@@ -23,7 +43,33 @@ type DictDataRepository interface {
 	Delete(ctx context.Context, ids ...int64) error
 	FindByID(ctx context.Context, id int64) (*model.DictDatum, error)
 	FindByTypeCode(ctx context.Context, typeCode string) ([]*model.DictDatum, error)
+	// FindByIDs 根据ID列表查询字典数据
+	FindByIDs(ctx context.Context, ids ...int64) ([]*model.DictDatum, error)
+	// FindByTypeCodeAndValueWithDeleted 根据类型编码和字典值查询字典数据，包含回收站中的记录，不存在时返回 nil
+	FindByTypeCodeAndValueWithDeleted(ctx context.Context, typeCode, value string) (*model.DictDatum, error)
+	// CountByTypeCodes 统计多个字典类型下的字典数据数量
+	CountByTypeCodes(ctx context.Context, typeCodes []string) (int64, error)
+	// UpdateTypeCode 将字典数据（包含回收站中的记录）从旧类型编码迁移到新类型编码
+	UpdateTypeCode(ctx context.Context, oldCode, newCode string) error
+	// DeleteByTypeCodes 删除多个字典类型下的全部字典数据
+	DeleteByTypeCodes(ctx context.Context, typeCodes []string) error
+	// PurgeByTypeCodes 彻底删除多个字典类型下回收站中的字典数据
+	PurgeByTypeCodes(ctx context.Context, typeCodes []string) error
+	// FindByTypeCodesWithDeleted 查询多个字典类型下的字典数据，包含回收站中的记录
+	FindByTypeCodesWithDeleted(ctx context.Context, typeCodes []string) ([]*model.DictDatum, error)
+	// FindByTypeCodes 查询多个字典类型下的全部字典数据，按排序值升序
+	FindByTypeCodes(ctx context.Context, typeCodes []string) ([]*model.DictDatum, error)
+	// FindEnabledByTypeCodes 查询多个字典类型下启用的字典数据，按排序值升序
+	FindEnabledByTypeCodes(ctx context.Context, typeCodes []string) ([]*model.DictDatum, error)
 	List(ctx context.Context, query *model.DictDataQuery) ([]*model.DictDatum, int64, error)
+	// ListDeleted 分页查询回收站中的字典数据，最近删除的在前
+	ListDeleted(ctx context.Context, page *types.PageParam) ([]*model.DictDatum, int64, error)
+	// FindDeletedByIDs 根据ID列表查询回收站中的字典数据
+	FindDeletedByIDs(ctx context.Context, ids ...int64) ([]*model.DictDatum, error)
+	// Restore 恢复回收站中的字典数据
+	Restore(ctx context.Context, ids ...int64) error
+	// Purge 彻底删除回收站中的字典数据
+	Purge(ctx context.Context, ids ...int64) error
 }
 
 type RoleRepository interface {
@@ -32,18 +78,32 @@ type RoleRepository interface {
 	Delete(ctx context.Context, ids ...uint64) error
 	FindByID(ctx context.Context, id uint64) (*model.Role, error)
 	FindByCode(ctx context.Context, code string) (*model.Role, error)
+	// FindByCodeWithDeleted 根据编码查询角色，包含回收站中的记录，不存在时返回 nil
+	FindByCodeWithDeleted(ctx context.Context, code string) (*model.Role, error)
 	List(ctx context.Context, query *model.RoleQuery) ([]*model.Role, int64, error)
 	// FindByIDs 根据角色ID列表查询角色
 	FindByIDs(ctx context.Context, ids []uint64) ([]*model.Role, error)
 	FindByCodes(ctx context.Context, codes ...string) ([]*model.Role, error)
 	// GetAllRoles 获取所有角色
 	GetAllRoles(ctx context.Context) ([]*model.Role, error)
+	// ListDeleted 分页查询回收站中的角色，最近删除的在前
+	ListDeleted(ctx context.Context, page *types.PageParam) ([]*model.Role, int64, error)
+	// FindDeletedByIDs 根据ID列表查询回收站中的角色
+	FindDeletedByIDs(ctx context.Context, ids ...uint64) ([]*model.Role, error)
+	// Restore 恢复回收站中的角色
+	Restore(ctx context.Context, ids ...uint64) error
+	// Purge 彻底删除回收站中的角色
+	Purge(ctx context.Context, ids ...uint64) error
 }
 
 type RoleMenuRepository interface {
 	Create(ctx context.Context, roleID, menuID uint64) error
 	Delete(ctx context.Context, roleID, menuID uint64) error
 	DeleteByRoleID(ctx context.Context, roleID uint64) error
+	// DeleteByRoleIDs 删除多个角色的菜单关联
+	DeleteByRoleIDs(ctx context.Context, roleIDs ...uint64) error
+	// DeleteByMenuIDs 删除多个菜单的角色关联
+	DeleteByMenuIDs(ctx context.Context, menuIDs ...uint64) error
 	FindMenusByRoleID(ctx context.Context, roleID uint64) ([]*model.SysMenu, error)
 	FindRolesByMenuID(ctx context.Context, menuID uint64) ([]*model.Role, error)
 	BatchCreate(ctx context.Context, roleID uint64, menuIDs []uint64) error
@@ -54,10 +114,30 @@ type RoleMenuRepository interface {
 type UserRepository interface {
 	Create(ctx context.Context, user *model.User) error
 	Update(ctx context.Context, user *model.User) error
+	// UpdateProfile 更新用户个人资料字段（昵称、手机、邮箱、签名），允许更新为空值
+	UpdateProfile(ctx context.Context, user *model.User) error
+	// UpdateAvatar 更新用户头像
+	UpdateAvatar(ctx context.Context, id uint64, avatar string) error
+	// UpdateBackendSetting 更新用户后台设置
+	UpdateBackendSetting(ctx context.Context, id uint64, setting *types.BackendSetting) error
 	Delete(ctx context.Context, ids ...uint64) error
 	FindByID(ctx context.Context, id uint64) (*model.User, error)
 	FindByUsername(ctx context.Context, username string) (*model.User, error)
+	// ListByEmail 根据邮箱查找用户，忽略大小写，最多返回 limit 条。邮箱不唯一，调用方需自行处理多个匹配
+	ListByEmail(ctx context.Context, email string, limit int) ([]*model.User, error)
+	// FindByUsernameWithDeleted 根据用户名查找用户，包含回收站中的记录，不存在时返回 nil
+	FindByUsernameWithDeleted(ctx context.Context, username string) (*model.User, error)
+	// FindByUsernames 根据用户名批量查找用户，包含回收站中的记录，用于检查用户名是否可用
+	FindByUsernames(ctx context.Context, usernames ...string) ([]*model.User, error)
 	List(ctx context.Context, query *model.UserQuery) ([]*model.User, int64, error)
+	// ListDeleted 分页查询回收站中的用户，最近删除的在前
+	ListDeleted(ctx context.Context, page *types.PageParam) ([]*model.User, int64, error)
+	// FindDeletedByIDs 根据ID列表查询回收站中的用户
+	FindDeletedByIDs(ctx context.Context, ids ...uint64) ([]*model.User, error)
+	// Restore 恢复回收站中的用户
+	Restore(ctx context.Context, ids ...uint64) error
+	// Purge 彻底删除回收站中的用户
+	Purge(ctx context.Context, ids ...uint64) error
 }
 type SysMenuRepository interface {
 	Create(ctx context.Context, menu *model.SysMenu) error
@@ -68,13 +148,65 @@ type SysMenuRepository interface {
 	FindByParentID(ctx context.Context, parentID int64) ([]*model.SysMenu, error)
 	List(ctx context.Context, query *model.SysMenuQuery) ([]*model.SysMenu, int64, error)
 	FindAll(ctx context.Context) ([]*model.SysMenu, error)
+	// FindAllWithDeleted 获取所有菜单，包含回收站中的记录
+	FindAllWithDeleted(ctx context.Context) ([]*model.SysMenu, error)
 	FindByRoleIDs(ctx context.Context, roleIDs ...uint64) ([]*model.SysMenu, error)
 	FindByIDs(ctx context.Context, ids ...uint64) ([]*model.SysMenu, error)
+	// ListDeleted 分页查询回收站中的菜单，最近删除的在前
+	ListDeleted(ctx context.Context, page *types.PageParam) ([]*model.SysMenu, int64, error)
+	// FindDeletedByIDs 根据ID列表查询回收站中的菜单
+	FindDeletedByIDs(ctx context.Context, ids ...int64) ([]*model.SysMenu, error)
+	// Restore 恢复回收站中的菜单
+	Restore(ctx context.Context, ids ...int64) error
+	// Purge 彻底删除回收站中的菜单
+	Purge(ctx context.Context, ids ...int64) error
 }
 type UserRoleRepository interface {
 	Create(ctx context.Context, userRoles ...*model.UserRoles) error
 	DeleteByUserID(ctx context.Context, userID uint64) error
+	// DeleteByUserIDs 删除多个用户的角色关联
+	DeleteByUserIDs(ctx context.Context, userIDs ...uint64) error
+	// DeleteByRoleIDs 删除多个角色的用户关联
+	DeleteByRoleIDs(ctx context.Context, roleIDs ...uint64) error
 	FindRolesByUserID(ctx context.Context, userID uint64) ([]*model.Role, error)
+	// FindByUserIDs 批量查找用户的角色关联
+	FindByUserIDs(ctx context.Context, userIDs ...uint64) ([]*model.UserRoles, error)
+}
+
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *model.UserIdentity) error
+	// FindBySubject 根据身份提供方和外部标识查找关联，不存在时返回 nil
+	FindBySubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error)
+	// ExistsByUserID 用户是否已关联任一外部身份
+	ExistsByUserID(ctx context.Context, userID uint64) (bool, error)
+	// DeleteByUserIDs 删除多个用户的外部身份关联
+	DeleteByUserIDs(ctx context.Context, userIDs ...uint64) error
+}
+
+type OAuthClientRepository interface {
+	Create(ctx context.Context, client *model.OAuthClient) error
+	Update(ctx context.Context, client *model.OAuthClient) error
+	// UpdateSecret 更新客户端密钥哈希
+	UpdateSecret(ctx context.Context, id uint64, secret string) error
+	Delete(ctx context.Context, ids ...uint64) error
+	FindByID(ctx context.Context, id uint64) (*model.OAuthClient, error)
+	// FindByClientID 根据客户端标识查找，不存在时返回 nil
+	FindByClientID(ctx context.Context, clientID string) (*model.OAuthClient, error)
+	List(ctx context.Context, query *model.OAuthClientQuery) ([]*model.OAuthClient, int64, error)
+}
+
+type OAuthConsentRepository interface {
+	// Find 查找用户对客户端的授权记录，不存在时返回 nil
+	Find(ctx context.Context, userID uint64, clientID string) (*model.OAuthConsent, error)
+	// Save 保存授权记录，已存在时更新授权范围
+	Save(ctx context.Context, consent *model.OAuthConsent) error
+	DeleteByClientID(ctx context.Context, clientIDs ...string) error
+	// DeleteByUserIDs 删除多个用户的授权记录
+	DeleteByUserIDs(ctx context.Context, userIDs ...uint64) error
+}
+
+type UserOperationLogRepository interface {
+	Create(ctx context.Context, log *model.UserOperationLog) error
 }
 
 type Repository interface {
@@ -85,6 +217,10 @@ type Repository interface {
 	DictType() DictTypeRepository
 	DictData() DictDataRepository
 	SysMenu() SysMenuRepository // 添加系统菜单仓储接口
+	UserIdentity() UserIdentityRepository
+	OAuthClient() OAuthClientRepository
+	OAuthConsent() OAuthConsentRepository
+	UserOperationLog() UserOperationLogRepository
 	Transaction(fn func(Repository) error) error
 	// DB 获取当前仓储使用的gorm.DB
 	DB() *gorm.DB
@@ -11,41 +11,65 @@ import (
 	gormadapter "github.com/casbin/gorm-adapter/v3"
 
 	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
+	"github.com/wxlbd/gin-casbin-admin/pkg/otelx"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/wxlbd/gin-casbin-admin/internal/dto"
 	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"gorm.io/gorm"
 )
 
 type roleService struct {
 	repo     Repository
 	enforcer *casbin.Enforcer
+	// permissions 接口到按钮权限标识的索引，用于监控指标
+	permissions *permissionIndex
 }
 
 func (s *roleService) GetAllRoles(ctx context.Context) ([]*model.Role, error) {
 	return s.repo.Role().GetAllRoles(ctx)
 }
 
-func NewRoleService(repo Repository, enforcer *casbin.Enforcer) handler.RoleService {
+func NewRoleService(repo Repository, enforcer *casbin.Enforcer, permissions *permissionIndex) handler.RoleService {
 	return &roleService{
-		repo:     repo,
-		enforcer: enforcer,
+		repo:        repo,
+		enforcer:    enforcer,
+		permissions: permissions,
 	}
 }
 
-func (s *roleService) Create(ctx context.Context, role *model.Role) error {
-	if s.IsCodeExists(ctx, role.Code) {
-		return errors.WithMsg(errors.AlreadyExists, "角色代码已存在")
+func (s *roleService) Create(ctx context.Context, role *model.Role) (err error) {
+	ctx, span := otelx.Start(ctx, "RoleService.Create")
+	defer func() { otelx.End(span, err) }()
+	if err := s.checkCode(ctx, role.Code); err != nil {
+		return err
 	}
 	return s.repo.Role().Create(ctx, role)
 }
 
+// checkCode 检查角色代码是否可用，回收站中的角色同样占用代码
+func (s *roleService) checkCode(ctx context.Context, code string) error {
+	exist, err := s.repo.Role().FindByCodeWithDeleted(ctx, code)
+	if err != nil {
+		return err
+	}
+	if exist == nil {
+		return nil
+	}
+	if exist.DeletedAt.Valid {
+		return errors.WithMsg(errors.AlreadyExists, "角色代码已被回收站中的角色使用，请恢复或彻底删除后重试")
+	}
+	return errors.WithMsg(errors.AlreadyExists, "角色代码已存在")
+}
+
 func (s *roleService) IsCodeExists(ctx context.Context, code string) bool {
 	roles, _ := s.repo.Role().FindByCodes(ctx, code)
 	return len(roles) > 0
 }
 
-func (s *roleService) Update(ctx context.Context, role *model.Role) error {
+func (s *roleService) Update(ctx context.Context, role *model.Role) (err error) {
+	ctx, span := otelx.Start(ctx, "RoleService.Update")
+	defer func() { otelx.End(span, err) }()
 	existRole, err := s.repo.Role().FindByID(ctx, role.ID)
 	if err != nil {
 		return err
@@ -56,15 +80,17 @@ func (s *roleService) Update(ctx context.Context, role *model.Role) error {
 
 	// 如果修改了角色代码，需要检查新代码是否已存在
 	if role.Code != existRole.Code {
-		if s.IsCodeExists(ctx, role.Code) {
-			return errors.WithMsg(errors.AlreadyExists, "角色代码已存在")
+		if err := s.checkCode(ctx, role.Code); err != nil {
+			return err
 		}
 	}
 
 	return s.repo.Role().Update(ctx, role)
 }
 
-func (s *roleService) Delete(ctx context.Context, ids ...uint64) error {
+func (s *roleService) Delete(ctx context.Context, ids ...uint64) (err error) {
+	ctx, span := otelx.Start(ctx, "RoleService.Delete")
+	defer func() { otelx.End(span, err) }()
 	roles, err := s.repo.Role().FindByIDs(ctx, ids)
 	if err != nil {
 		return err
@@ -82,6 +108,67 @@ func (s *roleService) Delete(ctx context.Context, ids ...uint64) error {
 	return s.repo.Role().Delete(ctx, ids...)
 }
 
+func (s *roleService) ListDeleted(ctx context.Context, page *types.PageParam) ([]*model.Role, int64, error) {
+	return s.repo.Role().ListDeleted(ctx, page)
+}
+
+// Restore 恢复角色，并按保留的角色菜单关联重新生成权限策略
+func (s *roleService) Restore(ctx context.Context, ids ...uint64) (err error) {
+	ctx, span := otelx.Start(ctx, "RoleService.Restore")
+	defer func() { otelx.End(span, err) }()
+	roles, err := s.repo.Role().FindDeletedByIDs(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	if len(roles) == 0 {
+		return errors.WithMsg(errors.NotFound, "回收站中不存在该角色")
+	}
+	if err := s.repo.Role().Restore(ctx, ids...); err != nil {
+		return err
+	}
+	for _, role := range roles {
+		menus, err := s.repo.RoleMenu().FindMenusByRoleID(ctx, role.ID)
+		if err != nil {
+			return err
+		}
+		for _, menu := range menus {
+			if types.MenuType(menu.MenuType) == types.MenuTypeButton {
+				path, method := convertMenuToAPI(menu.Auths)
+				if _, err := s.enforcer.AddPolicy(role.Code, path, method); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Purge 彻底删除角色及其菜单、用户关联
+func (s *roleService) Purge(ctx context.Context, ids ...uint64) (err error) {
+	ctx, span := otelx.Start(ctx, "RoleService.Purge")
+	defer func() { otelx.End(span, err) }()
+	roles, err := s.repo.Role().FindDeletedByIDs(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	if len(roles) == 0 {
+		return errors.WithMsg(errors.NotFound, "回收站中不存在该角色")
+	}
+	deletedIDs := make([]uint64, len(roles))
+	for i, role := range roles {
+		deletedIDs[i] = role.ID
+	}
+	return s.repo.Transaction(func(r Repository) error {
+		if err := r.RoleMenu().DeleteByRoleIDs(ctx, deletedIDs...); err != nil {
+			return err
+		}
+		if err := r.UserRole().DeleteByRoleIDs(ctx, deletedIDs...); err != nil {
+			return err
+		}
+		return r.Role().Purge(ctx, deletedIDs...)
+	})
+}
+
 func (s *roleService) FindByID(ctx context.Context, id uint64) (*model.Role, error) {
 	return s.repo.Role().FindByID(ctx, id)
 }
@@ -91,7 +178,10 @@ func (s *roleService) List(ctx context.Context, req *dto.RoleListRequest) ([]*mo
 	return s.repo.Role().List(ctx, query)
 }
 
-func (s *roleService) AssignMenuByIds(ctx context.Context, roleID uint64, menuIds []uint64) error {
+func (s *roleService) AssignMenuByIds(ctx context.Context, roleID uint64, menuIds []uint64) (err error) {
+	ctx, span := otelx.Start(ctx, "RoleService.AssignMenuByIds")
+	defer func() { otelx.End(span, err) }()
+
 	// 2. 获取所有按钮类型的菜单（即 API）
 	menus, err := s.repo.SysMenu().FindByIDs(ctx, menuIds...)
 	if err != nil {
@@ -103,10 +193,8 @@ func (s *roleService) AssignMenuByIds(ctx context.Context, roleID uint64, menuId
 	if err != nil {
 		return err
 	}
-	return s.repo.Transaction(func(r Repository) error {
-		// 获取事务中的 gorm.DB
-		tx := r.DB()
-		adapter, err := gormadapter.NewAdapterByDB(tx)
+	err = s.repo.Transaction(func(r Repository) error {
+		adapter, err := newTxAdapter(ctx, r.DB())
 		if err != nil {
 			return err
 		}
@@ -140,6 +228,23 @@ func (s *roleService) AssignMenuByIds(ctx context.Context, roleID uint64, menuId
 		// 创建新的角色菜单关联
 		return r.RoleMenu().BatchCreate(ctx, roleID, menuIds)
 	})
+	if err != nil {
+		return err
+	}
+	// 事务中的 enforcer 加载策略时使用独立的模型副本，提交后需要重新加载共享 enforcer 的策略
+	return s.enforcer.LoadPolicy()
+}
+
+// newTxAdapter 创建使用事务连接的 Casbin 适配器并关闭其自动建表：MySQL 执行 DDL 会隐式提交事务，
+// casbin_rule 表由迁移创建，或在事务开始前通过 NewAdapterByDB 创建
+func newTxAdapter(ctx context.Context, tx *gorm.DB) (*gormadapter.Adapter, error) {
+	db := tx.WithContext(ctx)
+	gormadapter.TurnOffAutoMigrate(db)
+	return gormadapter.NewAdapterByDB(db)
+}
+
+func (s *roleService) PermissionCode(ctx context.Context, method, route string) string {
+	return s.permissions.Lookup(ctx, method, route)
 }
 
 // convertMenuToAPI 将菜单名称转换为 API 路径和方法
@@ -193,6 +298,12 @@ func convertMenuToAPI(menuName string) (path, method string) {
 		"tree":    {"GET", "tree"},
 		"status":  {"PATCH", "status"},
 		"set":     {"PUT", ":id"},
+
+		"impersonate": {"POST", ":id/impersonate"},
+		// 回收站
+		"recycle": {"GET", "recycle"},
+		"restore": {"PUT", "recycle/:ids"},
+		"purge":   {"DELETE", "recycle/:ids"},
 	}
 
 	// 获取 HTTP 方法
@@ -1,6 +1,15 @@
 package service
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
+	"gorm.io/gorm"
+)
 
 func Test_convertMenuToAPI(t *testing.T) {
 	type args struct {
@@ -76,6 +85,38 @@ func Test_convertMenuToAPI(t *testing.T) {
 			wantPath:   "/api/system/role/:id",
 			wantMethod: "PUT",
 		},
+		{
+			name: "test9",
+			args: args{
+				menuName: "system:user:impersonate",
+			},
+			wantPath:   "/api/system/user/:id/impersonate",
+			wantMethod: "POST",
+		},
+		{
+			name: "test10",
+			args: args{
+				menuName: "system:role:recycle",
+			},
+			wantPath:   "/api/system/role/recycle",
+			wantMethod: "GET",
+		},
+		{
+			name: "test11",
+			args: args{
+				menuName: "system:dict-type:restore",
+			},
+			wantPath:   "/api/system/dict-type/recycle/:ids",
+			wantMethod: "PUT",
+		},
+		{
+			name: "test12",
+			args: args{
+				menuName: "system:menu:purge",
+			},
+			wantPath:   "/api/system/menu/recycle/:ids",
+			wantMethod: "DELETE",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -89,3 +130,79 @@ func Test_convertMenuToAPI(t *testing.T) {
 		})
 	}
 }
+
+func TestPermissionCode(t *testing.T) {
+	menus := []*model.SysMenu{
+		{MenuType: int32(types.MenuTypeButton), Auths: "system:user:list"},
+		{MenuType: int32(types.MenuTypeButton), Auths: "system:user:set:password"},
+		{MenuType: int32(types.MenuTypeButton), Auths: "custom:report:list"},
+		{MenuType: 1, Auths: "system:role:list"},
+	}
+	loads := 0
+	fail := false
+	index := newPermissionIndex(func(context.Context) ([]*model.SysMenu, error) {
+		loads++
+		if fail {
+			return nil, errors.New("db down")
+		}
+		return menus, nil
+	})
+	s := &roleService{permissions: index}
+	ctx := context.Background()
+	tests := []struct {
+		method, route, want string
+	}{
+		{"GET", "/api/system/user", "system:user:list"},
+		{"PUT", "/api/system/user/:id/password", "system:user:set:password"},
+		{"GET", "/api/custom/report", "custom:report:list"},
+		{"GET", "/api/system/role", ""},
+		{"GET", "/api/user/profile", ""},
+	}
+	for _, tt := range tests {
+		if got := s.PermissionCode(ctx, tt.method, tt.route); got != tt.want {
+			t.Errorf("PermissionCode(%q, %q) = %q, want %q", tt.method, tt.route, got, tt.want)
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("loads = %d, want 1", loads)
+	}
+
+	// 菜单变更后重新加载
+	menus = append(menus, &model.SysMenu{MenuType: int32(types.MenuTypeButton), Auths: "system:role:list"})
+	index.Invalidate()
+	if got := s.PermissionCode(ctx, "GET", "/api/system/role"); got != "system:role:list" {
+		t.Errorf("after invalidate got %q, want system:role:list", got)
+	}
+
+	// 加载失败不缓存
+	index.Invalidate()
+	fail = true
+	if got := s.PermissionCode(ctx, "GET", "/api/system/user"); got != "" {
+		t.Errorf("on load error got %q, want empty", got)
+	}
+	fail = false
+	if got := s.PermissionCode(ctx, "GET", "/api/system/user"); got != "system:user:list" {
+		t.Errorf("after recovery got %q, want system:user:list", got)
+	}
+	if loads != 4 {
+		t.Errorf("loads = %d, want 4", loads)
+	}
+}
+
+// TestNewTxAdapterSkipsMigrate 事务中的适配器不能执行建表等 DDL，MySQL 上会隐式提交事务
+func TestNewTxAdapterSkipsMigrate(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		_, err := newTxAdapter(context.Background(), tx)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.Migrator().HasTable("casbin_rule") {
+		t.Error("newTxAdapter created casbin_rule inside the transaction")
+	}
+}
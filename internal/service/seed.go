@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/casbin/casbin/v2"
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
+)
+
+const (
+	// superAdminRoleCode 超级管理员角色代码，拥有该角色的用户跳过权限校验
+	superAdminRoleCode = "SuperAdmin"
+	// defaultAdminUsername 初始管理员的默认用户名
+	defaultAdminUsername = "admin"
+	// generatedPasswordLength 随机生成的管理员密码长度
+	generatedPasswordLength = 16
+)
+
+const passwordAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789"
+
+// SeedOptions 初始化数据选项
+type SeedOptions struct {
+	// AdminUsername 管理员用户名，为空时使用 admin
+	AdminUsername string
+	// AdminPassword 管理员密码，为空时随机生成
+	AdminPassword string
+	// ResetAdminPassword 管理员已存在时重置其密码并启用账号
+	ResetAdminPassword bool
+}
+
+// SeedResult 初始化结果，重复执行时已存在的数据不计入
+type SeedResult struct {
+	RoleCreated        bool
+	AdminCreated       bool
+	AdminPasswordReset bool
+	// AdminPassword 本次创建或重置的管理员密码明文，未修改密码时为空
+	AdminPassword    string
+	MenusCreated     int
+	RoleMenusCreated int
+	PoliciesAdded    int
+	DictTypesCreated int
+	DictDataCreated  int
+}
+
+// Seeder 初始化全新安装所需的超级管理员角色、管理员账号、系统菜单、权限策略和基础字典，
+// 可重复执行，已存在的数据保持不变，回收站中的数据视为管理员有意删除，不会重新创建
+type Seeder struct {
+	repo     Repository
+	enforcer *casbin.Enforcer
+}
+
+func NewSeeder(repo Repository, enforcer *casbin.Enforcer) *Seeder {
+	return &Seeder{
+		repo:     repo,
+		enforcer: enforcer,
+	}
+}
+
+// Seed 在一个事务中写入初始化数据
+func (s *Seeder) Seed(ctx context.Context, opts SeedOptions) (*SeedResult, error) {
+	if opts.AdminUsername == "" {
+		opts.AdminUsername = defaultAdminUsername
+	}
+	if opts.AdminPassword != "" && (len(opts.AdminPassword) < 6 || len(opts.AdminPassword) > 72) {
+		return nil, fmt.Errorf("管理员密码长度应为 6-72 个字符")
+	}
+
+	// 适配器建表是 DDL，MySQL 上会隐式提交事务，需在事务开始前完成
+	if _, err := gormadapter.NewAdapterByDB(s.repo.DB().WithContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	result := &SeedResult{}
+	err := s.repo.Transaction(func(r Repository) error {
+		role, err := s.seedRole(ctx, r, result)
+		if err != nil {
+			return err
+		}
+		menus, err := s.seedMenus(ctx, r, result)
+		if err != nil {
+			return err
+		}
+		if err := s.seedPermissions(ctx, r, role, menus, result); err != nil {
+			return err
+		}
+		if err := s.seedAdmin(ctx, r, role, opts, result); err != nil {
+			return err
+		}
+		return s.seedDicts(ctx, r, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 策略在事务内通过独立的 enforcer 写入，提交后重新加载
+	if err := s.enforcer.LoadPolicy(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// seedRole 创建超级管理员角色，角色在回收站中时将其恢复
+func (s *Seeder) seedRole(ctx context.Context, r Repository, result *SeedResult) (*model.Role, error) {
+	role, err := r.Role().FindByCodeWithDeleted(ctx, superAdminRoleCode)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		role = &model.Role{Name: "超级管理员", Code: superAdminRoleCode, Status: 1, Remark: "系统内置角色，拥有全部权限"}
+		if err := r.Role().Create(ctx, role); err != nil {
+			return nil, err
+		}
+		result.RoleCreated = true
+		return role, nil
+	}
+	if role.DeletedAt.Valid {
+		if err := r.Role().Restore(ctx, role.ID); err != nil {
+			return nil, err
+		}
+	}
+	return role, nil
+}
+
+// seedMenus 按内置菜单树创建缺少的菜单，返回内置菜单对应的全部有效菜单
+func (s *Seeder) seedMenus(ctx context.Context, r Repository, result *SeedResult) ([]*model.SysMenu, error) {
+	all, err := r.SysMenu().FindAllWithDeleted(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]*model.SysMenu, len(all))
+	for _, menu := range all {
+		key := menu.Name
+		if types.MenuType(menu.MenuType) == types.MenuTypeButton {
+			key = menu.Auths
+		}
+		if key != "" {
+			existing[key] = menu
+		}
+	}
+
+	var menus []*model.SysMenu
+	var walk func(nodes []*seedMenu, parentID int64) error
+	walk = func(nodes []*seedMenu, parentID int64) error {
+		for _, node := range nodes {
+			menu, ok := existing[node.key()]
+			if ok && menu.DeletedAt.Valid {
+				// 回收站中的菜单连同下级菜单一起跳过
+				continue
+			}
+			if !ok {
+				m := node.menu
+				menu = &m
+				menu.ParentID = parentID
+				if err := r.SysMenu().Create(ctx, menu); err != nil {
+					return err
+				}
+				// 带默认值的布尔字段为 false 时创建会写入默认值，需要再更新一次
+				if !node.menu.ShowLink {
+					menu.ShowLink = false
+					if err := r.SysMenu().Update(ctx, menu); err != nil {
+						return err
+					}
+				}
+				result.MenusCreated++
+			}
+			menus = append(menus, menu)
+			if err := walk(node.children, menu.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(seedMenus, 0); err != nil {
+		return nil, err
+	}
+	return menus, nil
+}
+
+// seedPermissions 将菜单授权给超级管理员角色，并为按钮菜单生成对应接口的权限策略
+func (s *Seeder) seedPermissions(ctx context.Context, r Repository, role *model.Role, menus []*model.SysMenu, result *SeedResult) error {
+	granted, err := r.RoleMenu().FindMenusByRoleID(ctx, role.ID)
+	if err != nil {
+		return err
+	}
+	grantedIDs := make(map[int64]bool, len(granted))
+	for _, menu := range granted {
+		grantedIDs[menu.ID] = true
+	}
+	for _, menu := range menus {
+		if grantedIDs[menu.ID] {
+			continue
+		}
+		if err := r.RoleMenu().Create(ctx, role.ID, uint64(menu.ID)); err != nil {
+			return err
+		}
+		result.RoleMenusCreated++
+	}
+
+	// 创建一个使用相同事务的 Casbin 适配器，表已在事务开始前创建
+	adapter, err := newTxAdapter(ctx, r.DB())
+	if err != nil {
+		return err
+	}
+	txEnforcer, err := casbin.NewEnforcer(s.enforcer.GetModel(), adapter)
+	if err != nil {
+		return err
+	}
+	for _, menu := range menus {
+		if types.MenuType(menu.MenuType) != types.MenuTypeButton {
+			continue
+		}
+		path, method := convertMenuToAPI(menu.Auths)
+		if path == "" {
+			continue
+		}
+		// AddPolicy 在策略已存在时同样返回 true，需要先判断
+		exists, err := txEnforcer.HasPolicy(role.Code, path, method)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := txEnforcer.AddPolicy(role.Code, path, method); err != nil {
+			return err
+		}
+		result.PoliciesAdded++
+	}
+	return nil
+}
+
+// seedAdmin 创建管理员账号并关联超级管理员角色，账号已存在时仅在要求时重置密码
+func (s *Seeder) seedAdmin(ctx context.Context, r Repository, role *model.Role, opts SeedOptions, result *SeedResult) error {
+	user, err := r.User().FindByUsernameWithDeleted(ctx, opts.AdminUsername)
+	if err != nil {
+		return err
+	}
+	switch {
+	case user == nil:
+		password, hashed, err := adminPassword(opts.AdminPassword)
+		if err != nil {
+			return err
+		}
+		user = &model.User{Username: opts.AdminUsername, Password: hashed, Nickname: "超级管理员", Status: 1}
+		if err := r.User().Create(ctx, user); err != nil {
+			return err
+		}
+		result.AdminCreated, result.AdminPassword = true, password
+	case opts.ResetAdminPassword:
+		if user.DeletedAt.Valid {
+			if err := r.User().Restore(ctx, user.ID); err != nil {
+				return err
+			}
+		}
+		password, hashed, err := adminPassword(opts.AdminPassword)
+		if err != nil {
+			return err
+		}
+		if err := r.User().Update(ctx, &model.User{ID: user.ID, Password: hashed, Status: 1}); err != nil {
+			return err
+		}
+		result.AdminPasswordReset, result.AdminPassword = true, password
+	case user.DeletedAt.Valid:
+		return fmt.Errorf("管理员 %s 在回收站中，请恢复后重试或使用重置密码选项", opts.AdminUsername)
+	}
+
+	roles, err := r.UserRole().FindRolesByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	for _, item := range roles {
+		if item.ID == role.ID {
+			return nil
+		}
+	}
+	return r.UserRole().Create(ctx, &model.UserRoles{UserID: user.ID, RoleID: role.ID})
+}
+
+// seedDicts 创建缺少的内置字典类型和字典数据
+func (s *Seeder) seedDicts(ctx context.Context, r Repository, result *SeedResult) error {
+	codes := make([]string, len(seedDicts))
+	for i, item := range seedDicts {
+		codes[i] = item.dictType.Code
+	}
+	existingTypes, err := r.DictType().FindByCodesWithDeleted(ctx, codes)
+	if err != nil {
+		return err
+	}
+	typeByCode := make(map[string]*model.DictType, len(existingTypes))
+	for _, t := range existingTypes {
+		typeByCode[t.Code] = t
+	}
+	existingData, err := r.DictData().FindByTypeCodesWithDeleted(ctx, codes)
+	if err != nil {
+		return err
+	}
+	dataKeys := make(map[string]bool, len(existingData))
+	for _, d := range existingData {
+		dataKeys[d.TypeCode+"\x00"+d.Value] = true
+	}
+
+	for _, item := range seedDicts {
+		if t, ok := typeByCode[item.dictType.Code]; ok {
+			if t.DeletedAt.Valid {
+				continue
+			}
+		} else {
+			t := item.dictType
+			if err := r.DictType().Create(ctx, &t); err != nil {
+				return err
+			}
+			result.DictTypesCreated++
+		}
+		for _, d := range item.data {
+			if dataKeys[item.dictType.Code+"\x00"+d.Value] {
+				continue
+			}
+			d.TypeCode = item.dictType.Code
+			if err := r.DictData().Create(ctx, &d); err != nil {
+				return err
+			}
+			result.DictDataCreated++
+		}
+	}
+	return nil
+}
+
+// adminPassword 返回管理员密码明文及其 bcrypt 哈希，未指定密码时随机生成
+func adminPassword(password string) (string, string, error) {
+	if password == "" {
+		b := make([]byte, generatedPasswordLength)
+		limit := big.NewInt(int64(len(passwordAlphabet)))
+		for i := range b {
+			n, err := rand.Int(rand.Reader, limit)
+			if err != nil {
+				return "", "", err
+			}
+			b[i] = passwordAlphabet[n.Int64()]
+		}
+		password = string(b)
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", "", err
+	}
+	return password, string(hashed), nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
+)
+
+// seedMenu 初始化菜单树中的一个节点
+type seedMenu struct {
+	menu     model.SysMenu
+	children []*seedMenu
+}
+
+// key 菜单的唯一标识，按钮使用权限标识，其他菜单使用路由名称
+func (m *seedMenu) key() string {
+	if types.MenuType(m.menu.MenuType) == types.MenuTypeButton {
+		return m.menu.Auths
+	}
+	return m.menu.Name
+}
+
+func seedDir(title, name, path, icon string, rank int32, children ...*seedMenu) *seedMenu {
+	return &seedMenu{
+		menu: model.SysMenu{
+			MenuType:     int32(types.MenuTypeMenu),
+			Title:        title,
+			Name:         name,
+			Path:         path,
+			Rank:         rank,
+			Icon:         icon,
+			FrameLoading: true,
+			ShowLink:     true,
+			Status:       1,
+		},
+		children: children,
+	}
+}
+
+func seedButton(title, auths string) *seedMenu {
+	return &seedMenu{
+		menu: model.SysMenu{
+			MenuType:     int32(types.MenuTypeButton),
+			Title:        title,
+			Auths:        auths,
+			Rank:         99,
+			FrameLoading: true,
+			ShowLink:     true,
+			Status:       1,
+		},
+	}
+}
+
+// hidden 不在侧边栏显示的页面
+func (m *seedMenu) hidden() *seedMenu {
+	m.menu.ShowLink = false
+	return m
+}
+
+// seedMenus 系统内置菜单，按钮的权限标识与 internal/server/router.go 中的接口一一对应
+var seedMenus = []*seedMenu{
+	seedDir("menus.pureHome", "Home", "/welcome", "ep:home-filled", 1),
+	seedDir("menus.pureSysManagement", "PureSystem", "/system", "ri:settings-3-line", 13,
+		seedDir("menus.pureUser", "SystemUser", "/system/user/index", "ri:admin-line", 99,
+			seedButton("用户列表", "system:user:list"),
+			seedButton("用户详情", "system:user:detail"),
+			seedButton("用户添加", "system:user:create"),
+			seedButton("用户修改", "system:user:update"),
+			seedButton("用户删除", "system:user:delete"),
+			seedButton("用户导出", "system:user:export"),
+			seedButton("用户导入", "system:user:import"),
+			seedButton("获取用户角色", "system:user:get:roles"),
+			seedButton("赋予用户角色", "system:user:set:roles"),
+			seedButton("重置用户密码", "system:user:set:password"),
+			seedButton("模拟登录", "system:user:impersonate"),
+			seedButton("用户回收站", "system:user:recycle"),
+			seedButton("用户恢复", "system:user:restore"),
+			seedButton("用户彻底删除", "system:user:purge"),
+		),
+		seedDir("menus.pureRole", "SystemRole", "/system/role/index", "ri:admin-fill", 99,
+			seedButton("角色列表", "system:role:list"),
+			seedButton("角色详情", "system:role:detail"),
+			seedButton("角色添加", "system:role:create"),
+			seedButton("角色修改", "system:role:update"),
+			seedButton("角色删除", "system:role:delete"),
+			seedButton("角色导出", "system:role:export"),
+			seedButton("获取角色权限", "system:role:get:menus"),
+			seedButton("赋予角色权限", "system:role:set:menus"),
+			seedButton("角色回收站", "system:role:recycle"),
+			seedButton("角色恢复", "system:role:restore"),
+			seedButton("角色彻底删除", "system:role:purge"),
+		),
+		seedDir("menus.pureSystemMenu", "SystemMenu", "/system/menu/index", "ep:menu", 99,
+			seedButton("菜单列表", "system:menu:list"),
+			seedButton("菜单树", "system:menu:tree"),
+			seedButton("菜单添加", "system:menu:create"),
+			seedButton("菜单修改", "system:menu:update"),
+			seedButton("菜单删除", "system:menu:delete"),
+			seedButton("菜单导出", "system:menu:export"),
+			seedButton("菜单回收站", "system:menu:recycle"),
+			seedButton("菜单恢复", "system:menu:restore"),
+			seedButton("菜单彻底删除", "system:menu:purge"),
+		),
+		seedDir("字典管理", "sysDict", "/system/dict/index", "ep:memo", 99,
+			seedButton("字典类型列表", "system:dict-type:list"),
+			seedButton("字典类型详情", "system:dict-type:detail"),
+			seedButton("字典类型添加", "system:dict-type:create"),
+			seedButton("字典类型修改", "system:dict-type:update"),
+			seedButton("字典类型删除", "system:dict-type:delete"),
+			seedButton("字典类型导出", "system:dict-type:export"),
+			seedButton("字典包导出", "system:dict-type:export:bundle"),
+			seedButton("字典包导入", "system:dict-type:import:bundle"),
+			seedButton("字典类型回收站", "system:dict-type:recycle"),
+			seedButton("字典类型恢复", "system:dict-type:restore"),
+			seedButton("字典类型彻底删除", "system:dict-type:purge"),
+		),
+		seedDir("字典数据", "dictData", "/system/dict/dictData", "", 99,
+			seedButton("字典数据列表", "system:dict-data:list"),
+			seedButton("字典数据详情", "system:dict-data:detail"),
+			seedButton("字典数据添加", "system:dict-data:create"),
+			seedButton("字典数据修改", "system:dict-data:update"),
+			seedButton("字典数据删除", "system:dict-data:delete"),
+			seedButton("字典数据导出", "system:dict-data:export"),
+			seedButton("字典数据回收站", "system:dict-data:recycle"),
+			seedButton("字典数据恢复", "system:dict-data:restore"),
+			seedButton("字典数据彻底删除", "system:dict-data:purge"),
+		).hidden(),
+		seedDir("应用管理", "SystemOAuthClient", "/system/oauth-client/index", "ri:apps-line", 99,
+			seedButton("应用列表", "system:oauth-client:list"),
+			seedButton("应用详情", "system:oauth-client:detail"),
+			seedButton("应用添加", "system:oauth-client:create"),
+			seedButton("应用修改", "system:oauth-client:update"),
+			seedButton("应用删除", "system:oauth-client:delete"),
+			seedButton("重置应用密钥", "system:oauth-client:set:secret"),
+		),
+	),
+}
+
+// seedDictType 初始化字典类型及其字典数据
+type seedDictType struct {
+	dictType model.DictType
+	data     []model.DictDatum
+}
+
+// seedDicts 系统内置字典
+var seedDicts = []*seedDictType{
+	{
+		dictType: model.DictType{Code: "gender", Name: "性别", Status: 1, Sort: 1},
+		data: []model.DictDatum{
+			{Label: "男", Value: "1", Status: 1, Sort: 1},
+			{Label: "女", Value: "2", Status: 1, Sort: 2},
+			{Label: "未知", Value: "0", Status: 1, Sort: 3},
+		},
+	},
+	{
+		dictType: model.DictType{Code: "status", Name: "状态", Status: 1, Sort: 2, Remark: "用户、角色、字典等数据的启用状态"},
+		data: []model.DictDatum{
+			{Label: "正常", Value: "1", Status: 1, Sort: 1},
+			{Label: "停用", Value: "2", Status: 1, Sort: 2},
+		},
+	},
+}
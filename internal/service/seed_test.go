@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
+)
+
+func TestSeedMenus(t *testing.T) {
+	keys := make(map[string]bool)
+	routes := make(map[string]string)
+	var walk func(nodes []*seedMenu)
+	walk = func(nodes []*seedMenu) {
+		for _, node := range nodes {
+			key := node.key()
+			if key == "" {
+				t.Errorf("menu %q has no name or auths", node.menu.Title)
+			}
+			if keys[key] {
+				t.Errorf("duplicate menu key %q", key)
+			}
+			keys[key] = true
+			if types.MenuType(node.menu.MenuType) == types.MenuTypeButton {
+				if len(node.children) > 0 {
+					t.Errorf("button %q has children", key)
+				}
+				path, method := convertMenuToAPI(node.menu.Auths)
+				if path == "" {
+					t.Errorf("button %q maps to no api", key)
+				}
+				if prev, ok := routes[method+" "+path]; ok {
+					t.Errorf("buttons %q and %q map to the same api %s %s", prev, key, method, path)
+				}
+				routes[method+" "+path] = key
+			}
+			walk(node.children)
+		}
+	}
+	walk(seedMenus)
+
+	for auths, want := range map[string]string{
+		"system:user:set:password":       "PUT /api/system/user/:id/password",
+		"system:dict-type:import:bundle": "POST /api/system/dict-type/import/bundle",
+		"system:role:restore":            "PUT /api/system/role/recycle/:ids",
+		"system:oauth-client:set:secret": "PUT /api/system/oauth-client/:id/secret",
+	} {
+		if routes[want] != auths {
+			t.Errorf("%s should be granted by %q, got %q", want, auths, routes[want])
+		}
+	}
+}
+
+func TestAdminPassword(t *testing.T) {
+	password, hashed, err := adminPassword("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(password) != generatedPasswordLength || hashed == "" || hashed == password {
+		t.Errorf("adminPassword() = %q, %q", password, hashed)
+	}
+	if other, _, _ := adminPassword(""); other == password {
+		t.Errorf("generated passwords should differ")
+	}
+	if given, _, _ := adminPassword("secret123"); given != "secret123" {
+		t.Errorf("adminPassword() should keep the given password, got %q", given)
+	}
+}
@@ -1,11 +1,18 @@
 package service
 
 import (
+	"context"
+
 	"github.com/casbin/casbin/v2"
 	"github.com/redis/go-redis/v9"
 	"github.com/wxlbd/gin-casbin-admin/internal/handler"
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	httpx "github.com/wxlbd/gin-casbin-admin/pkg/http"
 	"github.com/wxlbd/gin-casbin-admin/pkg/jwtx"
 	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"github.com/wxlbd/gin-casbin-admin/pkg/mailx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/oidcx"
 )
 
 type service struct {
@@ -14,15 +21,31 @@ type service struct {
 	dict    handler.DictService
 	captcha handler.CaptchaService
 	sysMenu handler.SysMenuService
+	// passwordReset 找回密码
+	passwordReset handler.PasswordResetService
+	// oidc 单点登录
+	oidc handler.OIDCService
+	// oauth OAuth2 授权服务
+	oauth handler.OAuthService
+	// health 健康和就绪检查
+	health handler.HealthService
 }
 
-func NewService(logger *log.Logger, repo Repository, enforcer *casbin.Enforcer, jwt *jwtx.JWT, redisClient *redis.Client) handler.Service {
+func NewService(cfg *config.Config, watcher *config.Watcher, logger *log.Logger, repo Repository, enforcer *casbin.Enforcer, jwt *jwtx.JWT, redisClient *redis.Client, mailer mailx.Mailer, authenticators []Authenticator, oidcClient *oidcx.Client, state *httpx.State) handler.Service {
+	permissions := newPermissionIndex(func(ctx context.Context) ([]*model.SysMenu, error) {
+		return repo.SysMenu().FindAll(ctx)
+	})
 	return &service{
-		user:    NewUserService(logger, repo, jwt),
-		role:    NewRoleService(repo, enforcer),
-		dict:    NewDictService(logger, repo),
-		captcha: NewCaptchaService(redisClient),
-		sysMenu: NewSysMenuService(repo),
+		user:    NewUserService(cfg, logger, repo, enforcer, jwt, redisClient, authenticators),
+		role:    NewRoleService(repo, enforcer, permissions),
+		dict:    NewDictService(cfg, logger, repo, redisClient),
+		captcha: NewCaptchaService(cfg, watcher, logger, redisClient),
+		sysMenu: NewSysMenuService(repo, permissions),
+
+		passwordReset: NewPasswordResetService(cfg, watcher, logger, repo, redisClient, mailer, jwt),
+		oidc:          NewOIDCService(cfg, logger, repo, redisClient, jwt, oidcClient),
+		oauth:         NewOAuthService(cfg, logger, repo, redisClient, jwt),
+		health:        NewHealthService(logger, state, repo, redisClient, enforcer),
 	}
 }
 
@@ -45,3 +68,19 @@ func (s *service) Captcha() handler.CaptchaService {
 func (s *service) SysMenu() handler.SysMenuService {
 	return s.sysMenu
 }
+
+func (s *service) PasswordReset() handler.PasswordResetService {
+	return s.passwordReset
+}
+
+func (s *service) OIDC() handler.OIDCService {
+	return s.oidc
+}
+
+func (s *service) OAuth() handler.OAuthService {
+	return s.oauth
+}
+
+func (s *service) Health() handler.HealthService {
+	return s.health
+}
@@ -5,20 +5,27 @@ import (
 
 	"github.com/wxlbd/gin-casbin-admin/internal/handler"
 	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
 	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
+	"github.com/wxlbd/gin-casbin-admin/pkg/otelx"
 )
 
 type sysMenuService struct {
 	repo Repository
+	// permissions 菜单变更后失效的接口权限索引
+	permissions *permissionIndex
 }
 
-func NewSysMenuService(repo Repository) handler.SysMenuService {
+func NewSysMenuService(repo Repository, permissions *permissionIndex) handler.SysMenuService {
 	return &sysMenuService{
-		repo: repo,
+		repo:        repo,
+		permissions: permissions,
 	}
 }
 
-func (s *sysMenuService) Create(ctx context.Context, menu *model.SysMenu) error {
+func (s *sysMenuService) Create(ctx context.Context, menu *model.SysMenu) (err error) {
+	ctx, span := otelx.Start(ctx, "SysMenuService.Create")
+	defer func() { otelx.End(span, err) }()
 	// 1. 检查父菜单是否存在
 	if menu.ParentID != 0 {
 		parent, err := s.repo.SysMenu().Get(ctx, menu.ParentID)
@@ -40,10 +47,16 @@ func (s *sysMenuService) Create(ctx context.Context, menu *model.SysMenu) error
 	}
 
 	// 3. 创建菜单
-	return s.repo.SysMenu().Create(ctx, menu)
+	if err := s.repo.SysMenu().Create(ctx, menu); err != nil {
+		return err
+	}
+	s.permissions.Invalidate()
+	return nil
 }
 
-func (s *sysMenuService) Update(ctx context.Context, menu *model.SysMenu) error {
+func (s *sysMenuService) Update(ctx context.Context, menu *model.SysMenu) (err error) {
+	ctx, span := otelx.Start(ctx, "SysMenuService.Update")
+	defer func() { otelx.End(span, err) }()
 	// 1. 检查菜单是否存在
 	old, err := s.repo.SysMenu().Get(ctx, menu.ID)
 	if err != nil {
@@ -83,10 +96,16 @@ func (s *sysMenuService) Update(ctx context.Context, menu *model.SysMenu) error
 	}
 
 	// 4. 更新菜单
-	return s.repo.SysMenu().Update(ctx, menu)
+	if err := s.repo.SysMenu().Update(ctx, menu); err != nil {
+		return err
+	}
+	s.permissions.Invalidate()
+	return nil
 }
 
-func (s *sysMenuService) Delete(ctx context.Context, ids ...int64) error {
+func (s *sysMenuService) Delete(ctx context.Context, ids ...int64) (err error) {
+	ctx, span := otelx.Start(ctx, "SysMenuService.Delete")
+	defer func() { otelx.End(span, err) }()
 	// 1. 检查是否有子菜单
 	for _, id := range ids {
 		children, err := s.repo.SysMenu().FindByParentID(ctx, id)
@@ -99,7 +118,74 @@ func (s *sysMenuService) Delete(ctx context.Context, ids ...int64) error {
 	}
 
 	// 2. 删除菜单
-	return s.repo.SysMenu().Delete(ctx, ids...)
+	if err := s.repo.SysMenu().Delete(ctx, ids...); err != nil {
+		return err
+	}
+	s.permissions.Invalidate()
+	return nil
+}
+
+func (s *sysMenuService) ListDeleted(ctx context.Context, page *types.PageParam) ([]*model.SysMenu, int64, error) {
+	return s.repo.SysMenu().ListDeleted(ctx, page)
+}
+
+// Restore 恢复菜单，上级菜单必须未被删除或在本次一同恢复
+func (s *sysMenuService) Restore(ctx context.Context, ids ...int64) (err error) {
+	ctx, span := otelx.Start(ctx, "SysMenuService.Restore")
+	defer func() { otelx.End(span, err) }()
+	menus, err := s.repo.SysMenu().FindDeletedByIDs(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	if len(menus) == 0 {
+		return errors.WithMsg(errors.NotFound, "回收站中不存在该菜单")
+	}
+	restoring := make(map[int64]bool, len(menus))
+	for _, menu := range menus {
+		restoring[menu.ID] = true
+	}
+	for _, menu := range menus {
+		if menu.ParentID == 0 || restoring[menu.ParentID] {
+			continue
+		}
+		parent, err := s.repo.SysMenu().Get(ctx, menu.ParentID)
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			return errors.WithMsg(errors.InvalidParam, "上级菜单不存在，请先恢复上级菜单")
+		}
+	}
+	if err := s.repo.SysMenu().Restore(ctx, ids...); err != nil {
+		return err
+	}
+	s.permissions.Invalidate()
+	return nil
+}
+
+// Purge 彻底删除菜单及其角色关联
+func (s *sysMenuService) Purge(ctx context.Context, ids ...int64) (err error) {
+	ctx, span := otelx.Start(ctx, "SysMenuService.Purge")
+	defer func() { otelx.End(span, err) }()
+	menus, err := s.repo.SysMenu().FindDeletedByIDs(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	if len(menus) == 0 {
+		return errors.WithMsg(errors.NotFound, "回收站中不存在该菜单")
+	}
+	deletedIDs := make([]int64, len(menus))
+	menuIDs := make([]uint64, len(menus))
+	for i, menu := range menus {
+		deletedIDs[i] = menu.ID
+		menuIDs[i] = uint64(menu.ID)
+	}
+	return s.repo.Transaction(func(r Repository) error {
+		if err := r.RoleMenu().DeleteByMenuIDs(ctx, menuIDs...); err != nil {
+			return err
+		}
+		return r.SysMenu().Purge(ctx, deletedIDs...)
+	})
 }
 
 func (s *sysMenuService) Get(ctx context.Context, id int64) (*model.SysMenu, error) {
@@ -138,7 +224,10 @@ func (s *sysMenuService) GetMenuTree(ctx context.Context) ([]*model.SysMenuTree,
 	return buildTree(menus, 0), nil
 }
 
-func (s *sysMenuService) GetUserMenuTree(ctx context.Context, userID uint64) ([]*model.SysMenuTree, error) {
+func (s *sysMenuService) GetUserMenuTree(ctx context.Context, userID uint64) (tree []*model.SysMenuTree, err error) {
+	ctx, span := otelx.Start(ctx, "SysMenuService.GetUserMenuTree")
+	defer func() { otelx.End(span, err) }()
+
 	// 1. 获取用户角色
 	roles, err := s.repo.UserRole().FindRolesByUserID(ctx, userID)
 	if err != nil {
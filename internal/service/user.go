@@ -2,50 +2,105 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
+	"github.com/casbin/casbin/v2"
+	"github.com/redis/go-redis/v9"
 	"github.com/wxlbd/gin-casbin-admin/internal/handler"
 
 	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/internal/types"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
 	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
 	"github.com/wxlbd/gin-casbin-admin/pkg/jwtx"
 	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"github.com/wxlbd/gin-casbin-admin/pkg/otelx"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type userService struct {
-	repo   Repository
-	jwt    *jwtx.JWT
-	logger *log.Logger
+	repo     Repository
+	enforcer *casbin.Enforcer
+	jwt      *jwtx.JWT
+	redis    *redis.Client
+	logger   *log.Logger
+	// defaultSetting 管理员在配置中定义的后台设置默认值
+	defaultSetting *types.BackendSetting
+	// authenticators 登录认证器链
+	authenticators []Authenticator
+	ldapConfig     *config.LDAPConfig
 }
 
-func NewUserService(logger *log.Logger, repo Repository, jwt *jwtx.JWT) handler.UserService {
+func NewUserService(cfg *config.Config, logger *log.Logger, repo Repository, enforcer *casbin.Enforcer, jwt *jwtx.JWT, redisClient *redis.Client, authenticators []Authenticator) handler.UserService {
 	return &userService{
-		repo:   repo,
-		jwt:    jwt,
-		logger: logger,
+		repo:           repo,
+		enforcer:       enforcer,
+		jwt:            jwt,
+		redis:          redisClient,
+		logger:         logger,
+		defaultSetting: parseDefaultBackendSetting(logger, cfg.Setting.BackendDefault),
+		authenticators: authenticators,
+		ldapConfig:     &cfg.LDAP,
 	}
 }
 
-func (s *userService) Create(ctx context.Context, user *model.User) error {
-	// 检查用户名是否存在
-	existUser, err := s.repo.User().FindByUsername(ctx, user.Username)
+// parseDefaultBackendSetting 将配置中的默认后台设置转换为结构体，配置非法时忽略并记录日志
+func parseDefaultBackendSetting(logger *log.Logger, raw map[string]any) *types.BackendSetting {
+	setting := &types.BackendSetting{}
+	if len(raw) == 0 {
+		return setting
+	}
+	data, err := json.Marshal(raw)
+	if err == nil {
+		err = json.Unmarshal(data, setting)
+	}
+	if err == nil {
+		err = setting.Validate()
+	}
 	if err != nil {
-		return err
+		logger.Warn("默认后台设置配置无效，已忽略", zap.Error(err))
+		return &types.BackendSetting{}
 	}
-	if existUser != nil {
-		return errors.WithMsg(errors.AlreadyExists, "用户名已存在")
+	return setting
+}
+
+func (s *userService) Create(ctx context.Context, user *model.User) (err error) {
+	ctx, span := otelx.Start(ctx, "UserService.Create")
+	defer func() { otelx.End(span, err) }()
+
+	// 检查用户名是否存在
+	if err := s.checkUsername(ctx, user.Username); err != nil {
+		return err
 	}
 	// 创建用户
 	return s.repo.User().Create(ctx, user)
 }
 
-func (s *userService) Update(ctx context.Context, user *model.User) error {
+// checkUsername 检查用户名是否可用，回收站中的用户同样占用用户名
+func (s *userService) checkUsername(ctx context.Context, username string) error {
+	exist, err := s.repo.User().FindByUsernameWithDeleted(ctx, username)
+	if err != nil {
+		return err
+	}
+	if exist == nil {
+		return nil
+	}
+	if exist.DeletedAt.Valid {
+		return errors.WithMsg(errors.AlreadyExists, "用户名已被回收站中的用户使用，请恢复或彻底删除后重试")
+	}
+	return errors.WithMsg(errors.AlreadyExists, "用户名已存在")
+}
+
+func (s *userService) Update(ctx context.Context, user *model.User) (err error) {
+	ctx, span := otelx.Start(ctx, "UserService.Update")
+	defer func() { otelx.End(span, err) }()
+
 	existUser, err := s.repo.User().FindByID(ctx, user.ID)
 	if err != nil {
 		// 记录错误日志
-		s.logger.Error("查询用户失败", zap.Error(err))
+		s.logger.WithContext(ctx).Error("查询用户失败", zap.Error(err))
 		return errors.ErrDatabase
 	}
 	if existUser == nil {
@@ -54,18 +109,67 @@ func (s *userService) Update(ctx context.Context, user *model.User) error {
 
 	// 如果修改了用户名，需要检查新用户名是否已存在
 	if user.Username != existUser.Username {
-		if exist, _ := s.repo.User().FindByUsername(ctx, user.Username); exist != nil {
-			return errors.WithMsg(errors.AlreadyExists, "用户名已存在")
+		if err := s.checkUsername(ctx, user.Username); err != nil {
+			return err
 		}
 	}
 
 	return s.repo.User().Update(ctx, user)
 }
 
-func (s *userService) Delete(ctx context.Context, ids ...uint64) error {
+func (s *userService) Delete(ctx context.Context, ids ...uint64) (err error) {
+	ctx, span := otelx.Start(ctx, "UserService.Delete")
+	defer func() { otelx.End(span, err) }()
 	return s.repo.User().Delete(ctx, ids...)
 }
 
+func (s *userService) ListDeleted(ctx context.Context, page *types.PageParam) ([]*model.User, int64, error) {
+	return s.repo.User().ListDeleted(ctx, page)
+}
+
+// Restore 恢复用户，角色关联在删除时保留，恢复后原有权限随之生效
+func (s *userService) Restore(ctx context.Context, ids ...uint64) (err error) {
+	ctx, span := otelx.Start(ctx, "UserService.Restore")
+	defer func() { otelx.End(span, err) }()
+	users, err := s.repo.User().FindDeletedByIDs(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return errors.WithMsg(errors.NotFound, "回收站中不存在该用户")
+	}
+	return s.repo.User().Restore(ctx, ids...)
+}
+
+// Purge 彻底删除用户及其角色关联、外部身份和第三方授权记录
+func (s *userService) Purge(ctx context.Context, ids ...uint64) (err error) {
+	ctx, span := otelx.Start(ctx, "UserService.Purge")
+	defer func() { otelx.End(span, err) }()
+	users, err := s.repo.User().FindDeletedByIDs(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return errors.WithMsg(errors.NotFound, "回收站中不存在该用户")
+	}
+	deletedIDs := make([]uint64, len(users))
+	for i, user := range users {
+		deletedIDs[i] = user.ID
+	}
+	return s.repo.Transaction(func(r Repository) error {
+		if err := r.UserRole().DeleteByUserIDs(ctx, deletedIDs...); err != nil {
+			return err
+		}
+		if err := r.UserIdentity().DeleteByUserIDs(ctx, deletedIDs...); err != nil {
+			return err
+		}
+		if err := r.OAuthConsent().DeleteByUserIDs(ctx, deletedIDs...); err != nil {
+			return err
+		}
+		return r.User().Purge(ctx, deletedIDs...)
+	})
+}
+
 func (s *userService) FindByID(ctx context.Context, id uint64) (*model.User, error) {
 	return s.repo.User().FindByID(ctx, id)
 }
@@ -78,32 +182,74 @@ func (s *userService) List(ctx context.Context, query *model.UserQuery) ([]*mode
 	return s.repo.User().List(ctx, query)
 }
 
-func (s *userService) UpdatePassword(ctx context.Context, id uint64, oldPassword, newPassword string) error {
+func (s *userService) UpdatePassword(ctx context.Context, id uint64, oldPassword, newPassword string) (accessToken, refreshToken string, err error) {
+	ctx, span := otelx.Start(ctx, "UserService.UpdatePassword")
+	defer func() { otelx.End(span, err) }()
+
 	user, err := s.repo.User().FindByID(ctx, id)
 	if err != nil {
-		return err
+		return "", "", err
 	}
 	if user == nil {
-		return errors.WithMsg(errors.NotFound, "用户不存在")
+		return "", "", errors.WithMsg(errors.NotFound, "用户不存在")
 	}
 
 	// 验证旧密码
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword)); err != nil {
-		return errors.WithMsg(errors.Unauthorized, "旧密码错误")
+		return "", "", errors.WithMsg(errors.Unauthorized, "旧密码错误")
 	}
 
 	// 加密新密码
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
-		return errors.WithMsg(errors.ServerError, "密码加密失败")
+		return "", "", errors.WithMsg(errors.ServerError, "密码加密失败")
 	}
 
 	user.Password = string(hashedPassword)
-	return s.repo.User().Update(ctx, user)
+	if err := s.repo.User().Update(ctx, user); err != nil {
+		return "", "", err
+	}
+
+	// 吊销此前签发的所有令牌，使其他会话失效
+	if err := s.jwt.RevokeUserTokens(ctx, user.ID); err != nil {
+		s.logger.WithContext(ctx).Error("吊销用户令牌失败", zap.Uint64("user_id", user.ID), zap.Error(err))
+		return "", "", err
+	}
+
+	// 为当前会话签发新令牌
+	return s.jwt.GenerateToken(user.ID, user.Username)
+}
+
+func (s *userService) UpdateProfile(ctx context.Context, user *model.User) (err error) {
+	ctx, span := otelx.Start(ctx, "UserService.UpdateProfile")
+	defer func() { otelx.End(span, err) }()
+	exist, err := s.repo.User().FindByID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if exist == nil {
+		return errors.WithMsg(errors.NotFound, "用户不存在")
+	}
+	return s.repo.User().UpdateProfile(ctx, user)
+}
+
+func (s *userService) UpdateAvatar(ctx context.Context, id uint64, avatar string) (err error) {
+	ctx, span := otelx.Start(ctx, "UserService.UpdateAvatar")
+	defer func() { otelx.End(span, err) }()
+	exist, err := s.repo.User().FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if exist == nil {
+		return errors.WithMsg(errors.NotFound, "用户不存在")
+	}
+	return s.repo.User().UpdateAvatar(ctx, id, avatar)
 }
 
 // ResetPassword 重置用户密码
-func (s *userService) ResetPassword(ctx context.Context, id uint64, newPassword string) error {
+func (s *userService) ResetPassword(ctx context.Context, id uint64, newPassword string) (err error) {
+	ctx, span := otelx.Start(ctx, "UserService.ResetPassword")
+	defer func() { otelx.End(span, err) }()
 	user, err := s.repo.User().FindByID(ctx, id)
 	if err != nil {
 		return err
@@ -120,7 +266,43 @@ func (s *userService) ResetPassword(ctx context.Context, id uint64, newPassword
 	return s.repo.User().Update(ctx, user)
 }
 
-func (s *userService) AssignRoles(ctx context.Context, userID uint64, roleIds []uint64) error {
+// GetBackendSetting 获取用户后台设置，用户未保存过设置时返回默认值
+func (s *userService) GetBackendSetting(ctx context.Context, id uint64) (*types.BackendSetting, error) {
+	user, err := s.repo.User().FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.WithMsg(errors.NotFound, "用户不存在")
+	}
+	if user.BackendSetting.IsEmpty() {
+		return s.defaultSetting, nil
+	}
+	return user.BackendSetting, nil
+}
+
+// UpdateBackendSetting 以 JSON 合并补丁的方式部分更新用户后台设置
+func (s *userService) UpdateBackendSetting(ctx context.Context, id uint64, patch []byte) (setting *types.BackendSetting, err error) {
+	ctx, span := otelx.Start(ctx, "UserService.UpdateBackendSetting")
+	defer func() { otelx.End(span, err) }()
+	current, err := s.GetBackendSetting(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := current.Merge(patch)
+	if err != nil {
+		return nil, errors.WithMsg(errors.ValidationFailed, err.Error())
+	}
+	if err := s.repo.User().UpdateBackendSetting(ctx, id, merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+func (s *userService) AssignRoles(ctx context.Context, userID uint64, roleIds []uint64) (err error) {
+	ctx, span := otelx.Start(ctx, "UserService.AssignRoles")
+	defer func() { otelx.End(span, err) }()
+
 	return s.repo.Transaction(func(r Repository) error {
 		// 删除原有的用户-角色关系
 		if err := r.UserRole().DeleteByUserID(ctx, userID); err != nil {
@@ -139,20 +321,21 @@ func (s *userService) AssignRoles(ctx context.Context, userID uint64, roleIds []
 }
 
 func (s *userService) Login(ctx context.Context, username, password string) (accessToken, refreshToken string, err error) {
-	user, err := s.repo.User().FindByUsername(ctx, username)
+	ctx, span := otelx.Start(ctx, "UserService.Login")
+	defer func() { otelx.End(span, err) }()
+
+	identity, err := authenticate(ctx, s.logger, s.authenticators, username, password)
 	if err != nil {
-		// 记录错误日志
-		s.logger.Error("查询用户失败", zap.Error(err))
+		s.logger.WithContext(ctx).Warn("登录认证失败", zap.String("username", username), zap.Error(err))
 		return "", "", err
 	}
-	if user == nil {
-		return "", "", errors.WithMsg(errors.NotFound, "用户不存在")
-	}
 
-	// 验证密码
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		s.logger.Warn("密码错误", zap.Error(err))
-		return "", "", errors.WithMsg(errors.Unauthorized, "密码错误")
+	user := identity.User
+	if user == nil {
+		// 外部认证器认证成功，关联或自动创建本地用户
+		if user, err = s.provisionExternalUser(ctx, identity); err != nil {
+			return "", "", err
+		}
 	}
 
 	// 生成 token
@@ -170,11 +353,230 @@ func (s *userService) Login(ctx context.Context, username, password string) (acc
 	return accessToken, refreshToken, nil
 }
 
+// provisionExternalUser 按外部身份查找已关联的本地用户，首次登录时关联或自动创建用户，并按组映射分配角色
+func (s *userService) provisionExternalUser(ctx context.Context, identity *Identity) (*model.User, error) {
+	bound, err := s.repo.UserIdentity().FindBySubject(ctx, identity.Source, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		user    *model.User
+		created bool
+	)
+	if bound != nil {
+		if user, err = s.repo.User().FindByID(ctx, bound.UserID); err != nil {
+			return nil, err
+		}
+	} else {
+		err = s.repo.Transaction(func(r Repository) error {
+			user, created, err = s.linkExternalUser(ctx, r, identity)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if identity.Source == AuthenticatorLDAP && (created || s.ldapConfig.SyncRoles) {
+		if err := s.syncExternalRoles(ctx, user.ID, mapGroupRoles(s.ldapConfig, identity.Groups)); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// linkExternalUser 将外部身份关联到同名的无密码用户，不存在时创建用户
+func (s *userService) linkExternalUser(ctx context.Context, r Repository, identity *Identity) (user *model.User, created bool, err error) {
+	user, err = r.User().FindByUsernameWithDeleted(ctx, identity.Username)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("查询用户失败", zap.Error(err))
+		return nil, false, err
+	}
+	if user != nil && user.DeletedAt.Valid {
+		return nil, false, errors.WithMsg(errors.Unauthorized, "用户已被删除")
+	}
+
+	if user == nil {
+		// 外部用户不设置本地密码，只能通过外部认证器登录
+		user = &model.User{
+			Username: identity.Username,
+			Nickname: identity.Nickname,
+			Email:    identity.Email,
+			Phone:    identity.Phone,
+			Status:   1,
+			Remark:   "由 " + identity.Source + " 认证自动创建",
+		}
+		if err := r.User().Create(ctx, user); err != nil {
+			return nil, false, err
+		}
+		created = true
+		s.logger.WithContext(ctx).Info("自动创建外部认证用户", zap.String("username", user.Username), zap.String("source", identity.Source))
+	} else {
+		// 同名本地账号不允许被外部身份接管
+		if user.Password != "" {
+			return nil, false, errors.WithMsg(errors.Unauthorized, "用户名已被本地账号占用")
+		}
+		// 已关联其他外部身份的用户同样不允许接管，避免不同认证源的同名账号互相登录
+		linked, err := r.UserIdentity().ExistsByUserID(ctx, user.ID)
+		if err != nil {
+			return nil, false, err
+		}
+		if linked {
+			return nil, false, errors.WithMsg(errors.Unauthorized, "用户名已被其他外部账号占用")
+		}
+	}
+
+	if err := r.UserIdentity().Create(ctx, &model.UserIdentity{
+		UserID:   user.ID,
+		Provider: identity.Source,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}); err != nil {
+		return nil, false, err
+	}
+	return user, created, nil
+}
+
+// syncExternalRoles 按角色编码重新分配用户角色，不存在的编码会被忽略
+func (s *userService) syncExternalRoles(ctx context.Context, userID uint64, codes []string) error {
+	roleIds := make([]uint64, 0, len(codes))
+	if len(codes) > 0 {
+		roles, err := s.repo.Role().FindByCodes(ctx, codes...)
+		if err != nil {
+			return err
+		}
+		for _, role := range roles {
+			roleIds = append(roleIds, role.ID)
+		}
+		if len(roles) < len(codes) {
+			s.logger.WithContext(ctx).Warn("部分映射角色不存在", zap.Strings("codes", codes), zap.Int("found", len(roles)))
+		}
+	}
+	return s.AssignRoles(ctx, userID, roleIds)
+}
+
+func (s *userService) Impersonate(ctx context.Context, impersonatorID, userID uint64) (accessToken string, expiresAt time.Time, err error) {
+	ctx, span := otelx.Start(ctx, "UserService.Impersonate")
+	defer func() { otelx.End(span, err) }()
+
+	if impersonatorID == userID {
+		return "", time.Time{}, errors.WithMsg(errors.InvalidParam, "不能模拟自己")
+	}
+	impersonator, err := s.repo.User().FindByID(ctx, impersonatorID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if impersonator == nil {
+		return "", time.Time{}, errors.WithMsg(errors.NotFound, "用户不存在")
+	}
+	user, err := s.repo.User().FindByID(ctx, userID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if user == nil {
+		return "", time.Time{}, errors.WithMsg(errors.NotFound, "用户不存在")
+	}
+	if user.Status != 1 {
+		return "", time.Time{}, errors.WithMsg(errors.Forbidden, "账号已停用")
+	}
+
+	// 非超级管理员不能模拟超级管理员，避免借助模拟提升权限
+	targetIsAdmin, err := s.isSuperAdmin(ctx, user.ID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if targetIsAdmin {
+		isAdmin, err := s.isSuperAdmin(ctx, impersonator.ID)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		if !isAdmin {
+			return "", time.Time{}, errors.WithMsg(errors.Forbidden, "不能模拟超级管理员")
+		}
+	}
+
+	accessToken, expiresAt, err = s.jwt.GenerateImpersonationToken(ctx, impersonator.ID, impersonator.Username, user.ID, user.Username)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	s.logger.WithContext(ctx).Info("管理员模拟登录用户",
+		zap.Uint64("impersonator_id", impersonator.ID),
+		zap.String("impersonator_name", impersonator.Username),
+		zap.Uint64("user_id", user.ID),
+		zap.String("username", user.Username),
+	)
+	return accessToken, expiresAt, nil
+}
+
+// isSuperAdmin 判断用户是否拥有超级管理员角色
+func (s *userService) isSuperAdmin(ctx context.Context, userID uint64) (bool, error) {
+	roles, err := s.repo.UserRole().FindRolesByUserID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, role := range roles {
+		if role.Code == "SuperAdmin" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *userService) ExitImpersonation(ctx context.Context, token string) (accessToken, refreshToken string, err error) {
+	ctx, span := otelx.Start(ctx, "UserService.ExitImpersonation")
+	defer func() { otelx.End(span, err) }()
+
+	claims, err := s.jwt.ParseToken(ctx, token, false)
+	if err != nil {
+		return "", "", errors.WithMsg(errors.TokenInvalid, "token已过期或非法token")
+	}
+	if claims.ImpersonatorID == 0 {
+		return "", "", errors.WithMsg(errors.InvalidParam, "当前未处于模拟登录状态")
+	}
+	// 只凭模拟令牌不能换回管理员令牌，必须存在发起模拟时写入的服务端记录
+	if err := s.jwt.EndImpersonation(ctx, claims); err != nil {
+		if err == jwtx.ErrImpersonationEnded {
+			return "", "", errors.WithMsg(errors.TokenInvalid, "模拟登录已结束或已过期")
+		}
+		return "", "", err
+	}
+	if err := s.jwt.AddToBlacklist(ctx, token, claims); err != nil {
+		return "", "", err
+	}
+
+	impersonator, err := s.repo.User().FindByID(ctx, claims.ImpersonatorID)
+	if err != nil {
+		return "", "", err
+	}
+	if impersonator == nil {
+		return "", "", errors.WithMsg(errors.NotFound, "用户不存在")
+	}
+	if impersonator.Status != 1 {
+		return "", "", errors.WithMsg(errors.Forbidden, "账号已停用")
+	}
+	s.logger.WithContext(ctx).Info("管理员退出模拟登录",
+		zap.Uint64("impersonator_id", impersonator.ID),
+		zap.Uint64("user_id", claims.UserID),
+	)
+	return s.jwt.GenerateToken(impersonator.ID, impersonator.Username)
+}
+
 func (s *userService) RefreshToken(ctx context.Context, refreshToken string) (newAccessToken, newRefreshToken string, err error) {
+	ctx, span := otelx.Start(ctx, "UserService.RefreshToken")
+	defer func() { otelx.End(span, err) }()
+
 	return s.jwt.RefreshToken(ctx, refreshToken)
 }
 
-func (s *userService) Logout(ctx context.Context, token string) error {
+func (s *userService) AccessExpire() time.Duration {
+	return s.jwt.AccessExpire()
+}
+
+func (s *userService) Logout(ctx context.Context, token string) (err error) {
+	ctx, span := otelx.Start(ctx, "UserService.Logout")
+	defer func() { otelx.End(span, err) }()
+
 	// 解析 token
 	claims, err := s.jwt.ParseToken(ctx, token, false)
 	if err != nil {
@@ -185,7 +587,10 @@ func (s *userService) Logout(ctx context.Context, token string) error {
 	return s.jwt.AddToBlacklist(ctx, token, claims)
 }
 
-func (s *userService) GetUserRoles(ctx context.Context, userID uint64) ([]*model.Role, error) {
+func (s *userService) GetUserRoles(ctx context.Context, userID uint64) (roles []*model.Role, err error) {
+	ctx, span := otelx.Start(ctx, "UserService.GetUserRoles")
+	defer func() { otelx.End(span, err) }()
+
 	// 检查用户是否存在
 	user, err := s.repo.User().FindByID(ctx, userID)
 	if err != nil {
@@ -198,3 +603,9 @@ func (s *userService) GetUserRoles(ctx context.Context, userID uint64) ([]*model
 	// 获取用户的角色列表
 	return s.repo.UserRole().FindRolesByUserID(ctx, userID)
 }
+
+func (s *userService) RecordOperation(ctx context.Context, log *model.UserOperationLog) (err error) {
+	ctx, span := otelx.Start(ctx, "UserService.RecordOperation")
+	defer func() { otelx.End(span, err) }()
+	return s.repo.UserOperationLog().Create(ctx, log)
+}
@@ -0,0 +1,457 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/mail"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wxlbd/gin-casbin-admin/internal/dto"
+	"github.com/wxlbd/gin-casbin-admin/internal/model"
+	"github.com/wxlbd/gin-casbin-admin/pkg/errors"
+	"github.com/wxlbd/gin-casbin-admin/pkg/otelx"
+	"github.com/wxlbd/gin-casbin-admin/pkg/sheetx"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	userImportReportPrefix = "user:import:report:"
+	userImportReportExpire = 30 * time.Minute
+
+	// maxUserImportRows 单次导入的最大行数。导入在请求内同步完成，每行一次 bcrypt 哈希（约 50-100ms），
+	// 按 CPU 核数并行后仍需在 server.write_timeout（默认 60s）内返回，行数更多时请分批导入
+	maxUserImportRows = 500
+)
+
+// userImportHeaders 导入文件支持的表头，与导出文件的中英文表头一致，便于导出后修改再导入
+var userImportHeaders = map[string][]string{
+	"username": {"用户名", "username"},
+	"password": {"密码", "password"},
+	"nickname": {"昵称", "nickname"},
+	"phone":    {"手机", "手机号", "phone"},
+	"email":    {"邮箱", "email"},
+	"status":   {"状态", "status"},
+	"roles":    {"角色", "roles"},
+	"remark":   {"备注", "remark"},
+}
+
+// userImportRow 导入文件中的一行
+type userImportRow struct {
+	row       int
+	cells     []string
+	user      *model.User
+	roleCodes []string
+	roleIDs   []uint64
+	errs      []string
+}
+
+func (s *userService) GetRoleCodes(ctx context.Context, users []*model.User) (map[uint64][]string, error) {
+	userIDs := make([]uint64, 0, len(users))
+	for _, u := range users {
+		userIDs = append(userIDs, u.ID)
+	}
+	userRoles, err := s.repo.UserRole().FindByUserIDs(ctx, userIDs...)
+	if err != nil {
+		return nil, err
+	}
+	if len(userRoles) == 0 {
+		return nil, nil
+	}
+	roleIDs := make([]uint64, 0, len(userRoles))
+	for _, ur := range userRoles {
+		roleIDs = append(roleIDs, ur.RoleID)
+	}
+	roles, err := s.repo.Role().FindByIDs(ctx, roleIDs)
+	if err != nil {
+		return nil, err
+	}
+	codes := make(map[uint64]string, len(roles))
+	for _, role := range roles {
+		codes[role.ID] = role.Code
+	}
+	result := make(map[uint64][]string, len(users))
+	for _, ur := range userRoles {
+		if code, ok := codes[ur.RoleID]; ok {
+			result[ur.UserID] = append(result[ur.UserID], code)
+		}
+	}
+	return result, nil
+}
+
+func (s *userService) Import(ctx context.Context, operatorID uint64, format sheetx.Format, r io.Reader, dryRun bool) (resp *dto.UserImportResponse, err error) {
+	ctx, span := otelx.Start(ctx, "UserService.Import")
+	defer func() { otelx.End(span, err) }()
+	rows, err := sheetx.ReadAll(r, format)
+	if err != nil {
+		return nil, errors.WithMsg(errors.InvalidParam, "文件解析失败")
+	}
+	if len(rows) == 0 {
+		return nil, errors.WithMsg(errors.InvalidParam, "文件内容为空")
+	}
+	columns := parseUserImportHeader(rows[0])
+	if _, ok := columns["username"]; !ok {
+		return nil, errors.WithMsg(errors.InvalidParam, "缺少用户名列")
+	}
+	if _, ok := columns["password"]; !ok {
+		return nil, errors.WithMsg(errors.InvalidParam, "缺少密码列")
+	}
+
+	records := make([]*userImportRow, 0, len(rows)-1)
+	for i, cells := range rows[1:] {
+		if isBlankRow(cells) {
+			continue
+		}
+		records = append(records, parseUserImportRow(i+2, columns, cells))
+	}
+	if len(records) > maxUserImportRows {
+		return nil, errors.WithMsg(errors.InvalidParam, fmt.Sprintf("单次最多导入 %d 个用户", maxUserImportRows))
+	}
+	grants, err := s.importRoleGrants(ctx, operatorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateUserImport(ctx, records, grants); err != nil {
+		return nil, err
+	}
+
+	resp = &dto.UserImportResponse{DryRun: dryRun, Total: len(records), Errors: []*dto.UserImportError{}}
+	valid := make([]*userImportRow, 0, len(records))
+	for _, rec := range records {
+		if len(rec.errs) > 0 {
+			resp.Errors = append(resp.Errors, &dto.UserImportError{
+				Row:      rec.row,
+				Username: rec.user.Username,
+				Message:  strings.Join(rec.errs, "；"),
+			})
+			continue
+		}
+		valid = append(valid, rec)
+	}
+	resp.Success, resp.Failed = len(valid), len(resp.Errors)
+
+	if !dryRun && len(valid) > 0 {
+		if err := s.createImportedUsers(ctx, operatorID, valid); err != nil {
+			return nil, err
+		}
+		s.logger.WithContext(ctx).Info("批量导入用户", zap.Uint64("operator_id", operatorID), zap.Int("count", len(valid)))
+	}
+
+	if resp.Failed > 0 {
+		if resp.ReportID, err = s.saveImportReport(ctx, operatorID, format, rows[0], columns["password"], records); err != nil {
+			s.logger.WithContext(ctx).Error("保存导入错误报告失败", zap.Error(err))
+		}
+	}
+	return resp, nil
+}
+
+// userImportGrants 导入人可以在角色列中分配的角色
+type userImportGrants struct {
+	// assignRoles 拥有分配角色权限，否则填写了角色的行导入失败
+	assignRoles bool
+	// superAdmin 导入人是超级管理员，只有超级管理员可以分配超级管理员角色
+	superAdmin bool
+}
+
+// importRoleGrants 按导入人的角色判断角色列的权限，与单独分配角色一样需要 system:user:set:roles
+func (s *userService) importRoleGrants(ctx context.Context, operatorID uint64) (userImportGrants, error) {
+	var grants userImportGrants
+	roles, err := s.repo.UserRole().FindRolesByUserID(ctx, operatorID)
+	if err != nil {
+		return grants, err
+	}
+	path, method := convertMenuToAPI("system:user:set:roles")
+	for _, role := range roles {
+		if role.Code == superAdminRoleCode {
+			return userImportGrants{assignRoles: true, superAdmin: true}, nil
+		}
+		if grants.assignRoles {
+			continue
+		}
+		if grants.assignRoles, err = s.enforcer.Enforce(role.Code, path, method); err != nil {
+			return grants, err
+		}
+	}
+	return grants, nil
+}
+
+// validateUserImport 校验用户名在文件内和数据库中唯一，并按角色编码解析角色
+func (s *userService) validateUserImport(ctx context.Context, records []*userImportRow, grants userImportGrants) error {
+	usernames := make([]string, 0, len(records))
+	seen := make(map[string]int, len(records))
+	var codes []string
+	for _, rec := range records {
+		if rec.user.Username == "" {
+			continue
+		}
+		if row, ok := seen[rec.user.Username]; ok {
+			rec.errs = append(rec.errs, fmt.Sprintf("用户名与第 %d 行重复", row))
+			continue
+		}
+		seen[rec.user.Username] = rec.row
+		usernames = append(usernames, rec.user.Username)
+		codes = append(codes, rec.roleCodes...)
+	}
+
+	existing := make(map[string]bool)
+	if len(usernames) > 0 {
+		users, err := s.repo.User().FindByUsernames(ctx, usernames...)
+		if err != nil {
+			return err
+		}
+		for _, u := range users {
+			existing[u.Username] = true
+		}
+	}
+	roleIDs := make(map[string]uint64)
+	if len(codes) > 0 {
+		roles, err := s.repo.Role().FindByCodes(ctx, codes...)
+		if err != nil {
+			return err
+		}
+		for _, role := range roles {
+			roleIDs[role.Code] = role.ID
+		}
+	}
+
+	for _, rec := range records {
+		if existing[rec.user.Username] {
+			rec.errs = append(rec.errs, "用户名已存在")
+		}
+		if len(rec.roleCodes) > 0 && !grants.assignRoles {
+			rec.errs = append(rec.errs, "没有分配角色的权限")
+			continue
+		}
+		for _, code := range rec.roleCodes {
+			if code == superAdminRoleCode && !grants.superAdmin {
+				rec.errs = append(rec.errs, "只有超级管理员可以分配超级管理员角色")
+				continue
+			}
+			id, ok := roleIDs[code]
+			if !ok {
+				rec.errs = append(rec.errs, "角色不存在："+code)
+				continue
+			}
+			if !slices.Contains(rec.roleIDs, id) {
+				rec.roleIDs = append(rec.roleIDs, id)
+			}
+		}
+	}
+	return nil
+}
+
+// createImportedUsers 在事务中创建用户并分配角色
+func (s *userService) createImportedUsers(ctx context.Context, operatorID uint64, records []*userImportRow) error {
+	// 密码哈希耗时较长，放在事务外按 CPU 核数并行完成
+	if err := hashImportPasswords(records); err != nil {
+		return err
+	}
+	for _, rec := range records {
+		rec.user.CreatedBy = operatorID
+	}
+	return s.repo.Transaction(func(r Repository) error {
+		var userRoles []*model.UserRoles
+		for _, rec := range records {
+			if err := r.User().Create(ctx, rec.user); err != nil {
+				return err
+			}
+			for _, roleID := range rec.roleIDs {
+				userRoles = append(userRoles, &model.UserRoles{UserID: rec.user.ID, RoleID: roleID})
+			}
+		}
+		if len(userRoles) == 0 {
+			return nil
+		}
+		return r.UserRole().Create(ctx, userRoles...)
+	})
+}
+
+// hashImportPasswords 并行计算导入用户的密码哈希
+func hashImportPasswords(records []*userImportRow) error {
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	for _, rec := range records {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			hashed, err := bcrypt.GenerateFromPassword([]byte(rec.user.Password), bcrypt.DefaultCost)
+			if err != nil {
+				errOnce.Do(func() { firstErr = err })
+				return
+			}
+			rec.user.Password = string(hashed)
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// saveImportReport 将失败的行连同错误原因写入与上传文件相同格式的报告，仅导入人可以下载。
+// 报告保存在 Redis 中并可下载，passwordCol 列的明文密码不写入报告
+func (s *userService) saveImportReport(ctx context.Context, operatorID uint64, format sheetx.Format, header []string, passwordCol int, records []*userImportRow) (string, error) {
+	var buf bytes.Buffer
+	w, err := sheetx.NewWriter(&buf, format)
+	if err != nil {
+		return "", err
+	}
+	if err := w.Write(append(toAnySlice(header), "错误信息")); err != nil {
+		w.Abort()
+		return "", err
+	}
+	for _, rec := range records {
+		if len(rec.errs) == 0 {
+			continue
+		}
+		cells := make([]string, len(header))
+		copy(cells, rec.cells)
+		if passwordCol < len(cells) {
+			cells[passwordCol] = ""
+		}
+		if err := w.Write(append(toAnySlice(cells), strings.Join(rec.errs, "；"))); err != nil {
+			w.Abort()
+			return "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	id, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	key := userImportReportPrefix + strconv.FormatUint(operatorID, 10) + ":" + id
+	if err := s.redis.HSet(ctx, key, "format", string(format), "data", buf.Bytes()).Err(); err != nil {
+		return "", err
+	}
+	return id, s.redis.Expire(ctx, key, userImportReportExpire).Err()
+}
+
+func (s *userService) ImportReport(ctx context.Context, operatorID uint64, id string) (sheetx.Format, []byte, error) {
+	key := userImportReportPrefix + strconv.FormatUint(operatorID, 10) + ":" + id
+	report, err := s.redis.HGetAll(ctx, key).Result()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(report) == 0 {
+		return "", nil, errors.WithMsg(errors.NotFound, "导入报告不存在或已过期")
+	}
+	return sheetx.Format(report["format"]), []byte(report["data"]), nil
+}
+
+// parseUserImportHeader 按表头名称定位各字段所在列
+func parseUserImportHeader(header []string) map[string]int {
+	columns := make(map[string]int)
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		for field, names := range userImportHeaders {
+			for _, name := range names {
+				if h == name {
+					columns[field] = i
+				}
+			}
+		}
+	}
+	return columns
+}
+
+// parseUserImportRow 解析并校验单行数据，不涉及数据库的校验错误记录在 errs 中
+func parseUserImportRow(row int, columns map[string]int, cells []string) *userImportRow {
+	get := func(field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(cells) {
+			return ""
+		}
+		return strings.TrimSpace(cells[i])
+	}
+	rec := &userImportRow{
+		row:   row,
+		cells: cells,
+		user: &model.User{
+			Username: get("username"),
+			Password: get("password"),
+			Nickname: get("nickname"),
+			Phone:    get("phone"),
+			Email:    get("email"),
+			Remark:   get("remark"),
+			Status:   1,
+		},
+	}
+	u := rec.user
+
+	switch {
+	case u.Username == "":
+		rec.errs = append(rec.errs, "用户名不能为空")
+	case len(u.Username) > 64:
+		rec.errs = append(rec.errs, "用户名长度不能超过64")
+	}
+	// bcrypt 最多使用密码的前 72 字节
+	switch {
+	case u.Password == "":
+		rec.errs = append(rec.errs, "密码不能为空")
+	case len(u.Password) < 6 || len(u.Password) > 72:
+		rec.errs = append(rec.errs, "密码长度需为6-72位")
+	}
+	if len(u.Nickname) > 128 {
+		rec.errs = append(rec.errs, "昵称长度不能超过128")
+	}
+	if len(u.Phone) > 16 {
+		rec.errs = append(rec.errs, "手机号长度不能超过16")
+	}
+	if u.Email != "" {
+		if addr, err := mail.ParseAddress(u.Email); err != nil || addr.Address != u.Email || len(u.Email) > 128 {
+			rec.errs = append(rec.errs, "邮箱格式错误")
+		}
+	}
+	if len(u.Remark) > 255 {
+		rec.errs = append(rec.errs, "备注长度不能超过255")
+	}
+	switch get("status") {
+	case "", "1", "正常", "启用":
+	case "2", "停用", "禁用":
+		u.Status = 2
+	default:
+		rec.errs = append(rec.errs, "状态无效，可选值：正常、停用")
+	}
+	rec.roleCodes = splitRoleCodes(get("roles"))
+	return rec
+}
+
+// splitRoleCodes 拆分以逗号、分号或空白分隔的角色编码
+func splitRoleCodes(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '，' || r == ';' || r == '；' || r == '|' || r == ' ' || r == '\t' || r == '\n'
+	})
+}
+
+func isBlankRow(cells []string) bool {
+	for _, c := range cells {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func toAnySlice(cells []string) []any {
+	row := make([]any, len(cells))
+	for i, c := range cells {
+		row[i] = c
+	}
+	return row
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/wxlbd/gin-casbin-admin/pkg/sheetx"
+)
+
+func TestParseUserImportRow(t *testing.T) {
+	columns := parseUserImportHeader([]string{" 用户名 ", "Password", "邮箱", "状态", "角色"})
+	if len(columns) != 5 {
+		t.Fatalf("parseUserImportHeader() = %v", columns)
+	}
+
+	tests := []struct {
+		name      string
+		cells     []string
+		wantErrs  int
+		wantRoles []string
+		status    int8
+	}{
+		{name: "valid", cells: []string{"alice", "secret1", "alice@example.com", "停用", "Admin, Auditor；Guest"}, wantRoles: []string{"Admin", "Auditor", "Guest"}, status: 2},
+		{name: "short cells", cells: []string{"bob", "secret1"}, status: 1},
+		{name: "missing fields", cells: []string{"", "", "", ""}, wantErrs: 2, status: 1},
+		{name: "invalid values", cells: []string{"carol", "123", "Carol <carol@example.com>", "unknown"}, wantErrs: 3, status: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := parseUserImportRow(2, columns, tt.cells)
+			if len(rec.errs) != tt.wantErrs {
+				t.Errorf("errs = %v, want %d errors", rec.errs, tt.wantErrs)
+			}
+			if !slices.Equal(rec.roleCodes, tt.wantRoles) {
+				t.Errorf("roleCodes = %v, want %v", rec.roleCodes, tt.wantRoles)
+			}
+			if rec.user.Status != tt.status {
+				t.Errorf("status = %d, want %d", rec.user.Status, tt.status)
+			}
+		})
+	}
+}
+
+func TestSaveImportReportOmitsPassword(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	s := &userService{redis: client}
+	ctx := context.Background()
+
+	header := []string{"username", "password"}
+	columns := parseUserImportHeader(header)
+	records := []*userImportRow{parseUserImportRow(2, columns, []string{"alice", "123"})}
+	id, err := s.saveImportReport(ctx, 1, sheetx.FormatCSV, header, columns["password"], records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	format, data, err := s.ImportReport(ctx, 1, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := sheetx.ReadAll(bytes.NewReader(data), format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[1][0] != "alice" || rows[1][1] != "" {
+		t.Errorf("report rows = %q, want password blanked", rows)
+	}
+}
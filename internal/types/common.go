@@ -1,10 +1,13 @@
 package types
 
 import (
+	"bytes"
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
+	"slices"
 )
 
 // BackendSetting 后台设置结构体
@@ -52,9 +55,13 @@ type BackendSetting struct {
 	} `json:"welcomePage"`
 }
 
-// Value 实现 driver.Valuer 接口
+// Value 实现 driver.Valuer 接口，以字符串写入，PostgreSQL 的 jsonb 列不接受 bytea 参数
 func (b BackendSetting) Value() (driver.Value, error) {
-	return json.Marshal(b)
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
 }
 
 // Scan 实现 sql.Scanner 接口
@@ -64,8 +71,8 @@ func (b *BackendSetting) Scan(src any) error {
 		return nil
 	}
 
-	jsonData, ok := src.([]byte)
-	if !ok {
+	jsonData, err := jsonBytes(src)
+	if err != nil {
 		return errors.New("invalid data type for BackendSetting")
 	}
 
@@ -105,6 +112,89 @@ func (b *BackendSetting) UnmarshalJSON(data []byte) error {
 	return json.Unmarshal(data, aux)
 }
 
+// IsEmpty 判断后台设置是否为空（未保存过）
+func (b *BackendSetting) IsEmpty() bool {
+	return b == nil || reflect.DeepEqual(*b, BackendSetting{})
+}
+
+// Merge 将 JSON 合并补丁（RFC 7386 语义）应用到当前设置上，返回合并后的新设置。
+// 补丁中值为 null 的字段会被重置为零值；未知字段或类型不匹配的字段会返回错误。
+func (b *BackendSetting) Merge(patch []byte) (*BackendSetting, error) {
+	var patchMap map[string]any
+	if err := json.Unmarshal(patch, &patchMap); err != nil || patchMap == nil {
+		return nil, errors.New("设置内容必须是 JSON 对象")
+	}
+
+	type Alias BackendSetting
+	base := map[string]any{}
+	if b != nil {
+		raw, err := json.Marshal((*Alias)(b))
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &base); err != nil {
+			return nil, err
+		}
+	}
+
+	merged, err := json.Marshal(mergePatch(base, patchMap))
+	if err != nil {
+		return nil, err
+	}
+
+	// 严格解码：拒绝未知字段，保证设置结构符合约定
+	var result BackendSetting
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode((*Alias)(&result)); err != nil {
+		return nil, fmt.Errorf("设置格式错误: %v", err)
+	}
+	if err := result.Validate(); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Validate 校验设置中枚举类字段的取值
+func (b *BackendSetting) Validate() error {
+	checks := []struct {
+		field   string
+		value   string
+		options []string
+	}{
+		{"app.layout", b.App.Layout, []string{"classic", "mixed", "columns", "banner"}},
+		{"app.colorMode", b.App.ColorMode, []string{"autoMode", "light", "dark"}},
+		{"tabbar.mode", b.Tabbar.Mode, []string{"rectangle", "card"}},
+	}
+	for _, c := range checks {
+		if c.value != "" && !slices.Contains(c.options, c.value) {
+			return fmt.Errorf("%s 必须是 %v 中的一个", c.field, c.options)
+		}
+	}
+	return nil
+}
+
+// mergePatch 递归合并 JSON 对象，patch 中的 null 表示删除该字段
+func mergePatch(target, patch map[string]any) map[string]any {
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+		patchChild, ok := value.(map[string]any)
+		if !ok {
+			target[key] = value
+			continue
+		}
+		targetChild, ok := target[key].(map[string]any)
+		if !ok {
+			targetChild = map[string]any{}
+		}
+		target[key] = mergePatch(targetChild, patchChild)
+	}
+	return target
+}
+
 // PageParam 分页请求参数
 type PageParam struct {
 	Page     int `json:"page" form:"pageNum"`
@@ -150,9 +240,9 @@ func (m *MenuMeta) Scan(value interface{}) error {
 		return nil
 	}
 
-	bytes, ok := value.([]byte)
-	if !ok {
-		return errors.New("failed to unmarshal JSON value: value is not a byte slice")
+	bytes, err := jsonBytes(value)
+	if err != nil {
+		return errors.New("failed to unmarshal JSON value: value is not a byte slice or string")
 	}
 
 	return json.Unmarshal(bytes, m)
@@ -160,5 +250,21 @@ func (m *MenuMeta) Scan(value interface{}) error {
 
 // Value 实现 driver.Valuer 接口，用于将结构体序列化为 JSON 存储到数据库
 func (m MenuMeta) Value() (driver.Value, error) {
-	return json.Marshal(m)
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// jsonBytes 读取数据库返回的 JSON 列，MySQL 返回 []byte，SQLite 的文本列和部分 PostgreSQL 驱动返回 string
+func jsonBytes(src any) ([]byte, error) {
+	switch v := src.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return nil, fmt.Errorf("unsupported type %T", src)
+	}
 }
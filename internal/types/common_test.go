@@ -0,0 +1,97 @@
+package types
+
+import "testing"
+
+func TestBackendSetting_Merge(t *testing.T) {
+	base := &BackendSetting{}
+	base.App.Layout = "classic"
+	base.App.ColorMode = "light"
+	base.Tabbar.Enable = true
+
+	tests := []struct {
+		name    string
+		patch   string
+		wantErr bool
+		check   func(t *testing.T, got *BackendSetting)
+	}{
+		{
+			name:  "partial update keeps other fields",
+			patch: `{"app":{"colorMode":"dark"}}`,
+			check: func(t *testing.T, got *BackendSetting) {
+				if got.App.ColorMode != "dark" || got.App.Layout != "classic" || !got.Tabbar.Enable {
+					t.Errorf("Merge() got = %+v", got)
+				}
+			},
+		},
+		{
+			name:  "null resets field",
+			patch: `{"app":{"layout":null}}`,
+			check: func(t *testing.T, got *BackendSetting) {
+				if got.App.Layout != "" || got.App.ColorMode != "light" {
+					t.Errorf("Merge() got = %+v", got)
+				}
+			},
+		},
+		{
+			name:    "unknown field",
+			patch:   `{"app":{"foo":1}}`,
+			wantErr: true,
+		},
+		{
+			name:    "wrong type",
+			patch:   `{"tabbar":{"enable":"yes"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid enum",
+			patch:   `{"app":{"colorMode":"purple"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "not an object",
+			patch:   `[1,2]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := base.Merge([]byte(tt.patch))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Merge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, got)
+			}
+		})
+	}
+	if base.App.ColorMode != "light" {
+		t.Errorf("Merge() must not modify the receiver")
+	}
+}
+
+func TestBackendSetting_ValueScan(t *testing.T) {
+	var setting BackendSetting
+	setting.App.ColorMode = "dark"
+	v, err := setting.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, ok := v.(string)
+	if !ok {
+		t.Fatalf("Value() = %T, want string", v)
+	}
+	// MySQL 返回 []byte，SQLite 返回 string
+	for _, src := range []any{s, []byte(s)} {
+		var got BackendSetting
+		if err := got.Scan(src); err != nil {
+			t.Fatalf("Scan(%T) error = %v", src, err)
+		}
+		if got.App.ColorMode != "dark" {
+			t.Errorf("Scan(%T) colorMode = %q", src, got.App.ColorMode)
+		}
+	}
+	var got BackendSetting
+	if err := got.Scan(1); err == nil {
+		t.Errorf("Scan(int) should fail")
+	}
+}
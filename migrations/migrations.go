@@ -0,0 +1,10 @@
+// Package migrations 内嵌各数据库方言的结构迁移文件，由 pkg/migrate 执行
+//
+// 每个方言一个目录，文件名为 <版本号>_<名称>.up.sql 和 <版本号>_<名称>.down.sql，
+// 新增迁移时需要为 mysql、postgres、sqlite 同时添加同一版本号的文件
+package migrations
+
+import "embed"
+
+//go:embed mysql postgres sqlite
+var FS embed.FS
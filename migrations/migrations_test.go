@@ -0,0 +1,73 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/wxlbd/gin-casbin-admin/pkg/migrate"
+	"gorm.io/gorm"
+)
+
+// TestDialectsInSync 各方言的迁移版本必须一致
+func TestDialectsInSync(t *testing.T) {
+	var want []int64
+	for _, dialect := range []string{"mysql", "postgres", "sqlite"} {
+		list, err := migrate.Load(FS, dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var versions []int64
+		for _, m := range list {
+			versions = append(versions, m.Version)
+		}
+		if want == nil {
+			want = versions
+			continue
+		}
+		if len(versions) != len(want) {
+			t.Fatalf("%s versions = %v, want %v", dialect, versions, want)
+		}
+		for i := range want {
+			if versions[i] != want[i] {
+				t.Fatalf("%s versions = %v, want %v", dialect, versions, want)
+			}
+		}
+	}
+}
+
+func TestSQLiteUpDown(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := migrate.New(db, FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range []string{"user", "role", "sys_menus", "dict_types", "dict_data", "casbin_rule"} {
+		if !db.Migrator().HasTable(table) {
+			t.Errorf("table %s not created", table)
+		}
+	}
+	// 初始迁移不跳过已存在的表，已有表结构的数据库需要先用 Force 建立基线
+	if err := m.Force(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(ctx); err == nil {
+		t.Error("Up() on existing schema should fail")
+	}
+	if err := m.Force(ctx, m.Latest()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.To(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	if db.Migrator().HasTable("user") {
+		t.Error("table user not dropped")
+	}
+}
@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS `user_roles`;
+DROP TABLE IF EXISTS `user_operation_log`;
+DROP TABLE IF EXISTS `user_identity`;
+DROP TABLE IF EXISTS `user_login_log`;
+DROP TABLE IF EXISTS `user`;
+DROP TABLE IF EXISTS `sys_menus`;
+DROP TABLE IF EXISTS `role_menus`;
+DROP TABLE IF EXISTS `role`;
+DROP TABLE IF EXISTS `oauth_consent`;
+DROP TABLE IF EXISTS `oauth_client`;
+DROP TABLE IF EXISTS `dict_types`;
+DROP TABLE IF EXISTS `dict_data`;
+DROP TABLE IF EXISTS `casbin_rule`;
+DROP TABLE IF EXISTS `attachment`;
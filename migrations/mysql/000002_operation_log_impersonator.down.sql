@@ -0,0 +1,4 @@
+ALTER TABLE `user_operation_log`
+  DROP KEY `user_operation_log_impersonator_id_index`,
+  DROP COLUMN `impersonator_name`,
+  DROP COLUMN `impersonator_id`;
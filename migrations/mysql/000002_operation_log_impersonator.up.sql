@@ -0,0 +1,6 @@
+-- 操作日志记录模拟登录期间由哪个管理员发起的操作
+
+ALTER TABLE `user_operation_log`
+  ADD COLUMN `impersonator_id` bigint(20) unsigned DEFAULT NULL COMMENT '模拟登录时发起模拟的管理员ID' AFTER `ip`,
+  ADD COLUMN `impersonator_name` varchar(20) COLLATE utf8mb4_unicode_ci DEFAULT NULL COMMENT '模拟登录时发起模拟的管理员用户名' AFTER `impersonator_id`,
+  ADD KEY `user_operation_log_impersonator_id_index` (`impersonator_id`);
@@ -0,0 +1,6 @@
+-- 操作日志记录模拟登录期间由哪个管理员发起的操作
+
+ALTER TABLE user_operation_log
+  ADD COLUMN impersonator_id bigint DEFAULT NULL,
+  ADD COLUMN impersonator_name varchar(20) DEFAULT NULL;
+CREATE INDEX user_operation_log_impersonator_id_index ON user_operation_log (impersonator_id);
@@ -0,0 +1,216 @@
+-- 初始表结构，字段与 MySQL 版本一致，自增主键使用 INTEGER PRIMARY KEY AUTOINCREMENT
+
+CREATE TABLE attachment (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  storage_mode varchar(20) NOT NULL DEFAULT 'local',
+  origin_name varchar(255) DEFAULT NULL,
+  object_name varchar(50) DEFAULT NULL,
+  hash varchar(64) DEFAULT NULL,
+  mime_type varchar(255) DEFAULT NULL,
+  storage_path varchar(100) DEFAULT NULL,
+  suffix varchar(20) DEFAULT NULL,
+  size_byte bigint DEFAULT NULL,
+  size_info varchar(50) DEFAULT NULL,
+  url varchar(255) DEFAULT NULL,
+  created_by bigint NOT NULL DEFAULT 0,
+  updated_by bigint NOT NULL DEFAULT 0,
+  created_at timestamp DEFAULT NULL,
+  updated_at timestamp DEFAULT NULL,
+  remark varchar(255) NOT NULL DEFAULT '',
+  CONSTRAINT attachment_hash_unique UNIQUE (hash)
+);
+CREATE INDEX attachment_storage_path_index ON attachment (storage_path);
+
+CREATE TABLE casbin_rule (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  ptype varchar(100) DEFAULT NULL,
+  v0 varchar(100) DEFAULT NULL,
+  v1 varchar(100) DEFAULT NULL,
+  v2 varchar(100) DEFAULT NULL,
+  v3 varchar(100) DEFAULT NULL,
+  v4 varchar(100) DEFAULT NULL,
+  v5 varchar(100) DEFAULT NULL,
+  CONSTRAINT idx_casbin_rule UNIQUE (ptype, v0, v1, v2, v3, v4, v5)
+);
+
+CREATE TABLE dict_data (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  type_code varchar(30) NOT NULL,
+  label varchar(20) NOT NULL,
+  value varchar(255) NOT NULL,
+  status integer NOT NULL DEFAULT 1,
+  sort integer NOT NULL DEFAULT 0,
+  remark varchar(255) NOT NULL DEFAULT '',
+  created_by bigint NOT NULL DEFAULT 0,
+  updated_by bigint NOT NULL DEFAULT 0,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamp DEFAULT NULL,
+  deleted_at timestamp DEFAULT NULL,
+  CONSTRAINT uk_type_code_value UNIQUE (type_code, value)
+);
+
+CREATE TABLE dict_types (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  code varchar(30) NOT NULL,
+  name varchar(20) NOT NULL,
+  status integer NOT NULL DEFAULT 1,
+  sort integer NOT NULL DEFAULT 0,
+  remark varchar(255) NOT NULL DEFAULT '',
+  created_by bigint NOT NULL DEFAULT 0,
+  updated_by bigint NOT NULL DEFAULT 0,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamp DEFAULT NULL,
+  deleted_at timestamp DEFAULT NULL,
+  CONSTRAINT uk_code UNIQUE (code)
+);
+
+CREATE TABLE oauth_client (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  client_id varchar(64) NOT NULL,
+  client_secret varchar(128) NOT NULL,
+  name varchar(64) NOT NULL,
+  redirect_uris varchar(1024) NOT NULL DEFAULT '',
+  grant_types varchar(128) NOT NULL DEFAULT '',
+  scopes text,
+  skip_consent boolean NOT NULL DEFAULT 0,
+  status smallint NOT NULL DEFAULT 1,
+  remark varchar(255) NOT NULL DEFAULT '',
+  created_by bigint NOT NULL DEFAULT 0,
+  updated_by bigint NOT NULL DEFAULT 0,
+  created_at timestamp DEFAULT NULL,
+  updated_at timestamp DEFAULT NULL,
+  CONSTRAINT idx_oauth_client_client_id UNIQUE (client_id)
+);
+
+CREATE TABLE oauth_consent (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  user_id bigint NOT NULL,
+  client_id varchar(64) NOT NULL,
+  scopes text,
+  created_at timestamp DEFAULT NULL,
+  updated_at timestamp DEFAULT NULL,
+  CONSTRAINT idx_oauth_consent_user_client UNIQUE (user_id, client_id)
+);
+
+CREATE TABLE role (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  name varchar(30) NOT NULL,
+  code varchar(100) NOT NULL,
+  status smallint NOT NULL DEFAULT 1,
+  sort smallint NOT NULL DEFAULT 0,
+  created_by bigint NOT NULL DEFAULT 0,
+  updated_by bigint NOT NULL DEFAULT 0,
+  created_at timestamp DEFAULT NULL,
+  updated_at timestamp DEFAULT NULL,
+  remark varchar(255) NOT NULL DEFAULT '',
+  deleted_at timestamp DEFAULT NULL,
+  CONSTRAINT role_code_unique UNIQUE (code)
+);
+
+CREATE TABLE role_menus (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  role_id bigint NOT NULL,
+  menu_id bigint NOT NULL,
+  created_at timestamp DEFAULT NULL,
+  updated_at timestamp DEFAULT NULL
+);
+
+CREATE TABLE sys_menus (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  parent_id bigint DEFAULT 0,
+  menu_type smallint NOT NULL DEFAULT 1,
+  title varchar(50) NOT NULL,
+  name varchar(50) DEFAULT NULL,
+  path varchar(200) DEFAULT '',
+  component varchar(255) DEFAULT NULL,
+  rank integer DEFAULT 99,
+  redirect varchar(255) DEFAULT NULL,
+  icon varchar(100) DEFAULT NULL,
+  extra_icon varchar(100) DEFAULT NULL,
+  enter_transition varchar(50) DEFAULT NULL,
+  leave_transition varchar(50) DEFAULT NULL,
+  active_path varchar(255) DEFAULT NULL,
+  auths varchar(500) DEFAULT NULL,
+  frame_src varchar(500) DEFAULT NULL,
+  frame_loading boolean DEFAULT 1,
+  keep_alive boolean DEFAULT 0,
+  hidden_tag boolean DEFAULT 0,
+  fixed_tag boolean DEFAULT 0,
+  show_link boolean DEFAULT 1,
+  show_parent boolean DEFAULT 0,
+  status smallint DEFAULT 1,
+  created_by bigint NOT NULL DEFAULT 0,
+  updated_by bigint NOT NULL DEFAULT 0,
+  created_at timestamp DEFAULT NULL,
+  updated_at timestamp DEFAULT NULL,
+  deleted_at timestamp DEFAULT NULL
+);
+
+CREATE TABLE "user" (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  username varchar(20) NOT NULL,
+  password varchar(100) NOT NULL,
+  user_type varchar(3) NOT NULL DEFAULT '100',
+  nickname varchar(30) NOT NULL DEFAULT '',
+  phone varchar(11) NOT NULL DEFAULT '',
+  email varchar(50) NOT NULL DEFAULT '',
+  avatar varchar(255) NOT NULL DEFAULT '',
+  signed varchar(255) NOT NULL DEFAULT '',
+  status smallint NOT NULL DEFAULT 1,
+  login_ip varchar(45) NOT NULL DEFAULT '127.0.0.1',
+  login_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  backend_setting text DEFAULT NULL,
+  created_by bigint NOT NULL DEFAULT 0,
+  updated_by bigint NOT NULL DEFAULT 0,
+  created_at timestamp DEFAULT NULL,
+  updated_at timestamp DEFAULT NULL,
+  remark varchar(255) NOT NULL DEFAULT '',
+  deleted_at timestamp DEFAULT NULL,
+  CONSTRAINT user_username_unique UNIQUE (username)
+);
+
+CREATE TABLE user_login_log (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  username varchar(20) NOT NULL,
+  ip varchar(45) DEFAULT NULL,
+  os varchar(255) DEFAULT NULL,
+  browser varchar(255) DEFAULT NULL,
+  status smallint NOT NULL DEFAULT 1,
+  message varchar(50) DEFAULT NULL,
+  login_time timestamp NOT NULL,
+  remark varchar(255) DEFAULT NULL
+);
+CREATE INDEX user_login_log_username_index ON user_login_log (username);
+
+CREATE TABLE user_identity (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  user_id bigint NOT NULL,
+  provider varchar(255) NOT NULL,
+  subject varchar(255) NOT NULL,
+  email varchar(128) NOT NULL DEFAULT '',
+  created_at timestamp DEFAULT NULL,
+  updated_at timestamp DEFAULT NULL,
+  CONSTRAINT idx_user_identity_provider_subject UNIQUE (provider, subject)
+);
+CREATE INDEX idx_user_identity_user_id ON user_identity (user_id);
+
+CREATE TABLE user_operation_log (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  username varchar(20) NOT NULL,
+  method varchar(20) NOT NULL,
+  router varchar(500) NOT NULL,
+  service_name varchar(30) NOT NULL,
+  ip varchar(45) DEFAULT NULL,
+  created_at timestamp DEFAULT NULL,
+  updated_at timestamp DEFAULT NULL,
+  remark varchar(255) DEFAULT NULL
+);
+CREATE INDEX user_operation_log_username_index ON user_operation_log (username);
+
+CREATE TABLE user_roles (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  user_id bigint NOT NULL,
+  role_id bigint NOT NULL,
+  created_at timestamp DEFAULT NULL,
+  updated_at timestamp DEFAULT NULL
+);
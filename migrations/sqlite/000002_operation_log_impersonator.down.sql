@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS user_operation_log_impersonator_id_index;
+ALTER TABLE user_operation_log DROP COLUMN impersonator_name;
+ALTER TABLE user_operation_log DROP COLUMN impersonator_id;
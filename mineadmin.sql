@@ -94,17 +94,20 @@ CREATE TABLE `dict_data` (
   `status` int(11) NOT NULL DEFAULT '1' COMMENT '字典状态:1-正常,2-禁用',
   `sort` int(11) NOT NULL COMMENT '排序',
   `remark` varchar(255) CHARACTER SET utf8mb4 NOT NULL COMMENT '备注',
+  `created_by` bigint(20) NOT NULL DEFAULT '0' COMMENT '创建者',
+  `updated_by` bigint(20) NOT NULL DEFAULT '0' COMMENT '更新者',
   `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   `updated_at` timestamp NULL DEFAULT NULL,
-  `deleted_at` int(11) DEFAULT NULL,
-  PRIMARY KEY (`id`)
+  `deleted_at` datetime DEFAULT NULL COMMENT '删除时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_type_code_value` (`type_code`,`value`)
 ) ENGINE=InnoDB AUTO_INCREMENT=3 DEFAULT CHARSET=utf8 COMMENT='字典数据表';
 
 -- ----------------------------
 -- Records of dict_data
 -- ----------------------------
 BEGIN;
-INSERT INTO `dict_data` (`id`, `type_code`, `label`, `value`, `status`, `sort`, `remark`, `created_at`, `updated_at`, `deleted_at`) VALUES (1, 'gender', '男', '1', 1, 0, '备注', '2025-02-08 02:05:26', '2025-02-08 10:05:27', 0);
+INSERT INTO `dict_data` (`id`, `type_code`, `label`, `value`, `status`, `sort`, `remark`, `created_at`, `updated_at`, `deleted_at`) VALUES (1, 'gender', '男', '1', 1, 0, '备注', '2025-02-08 02:05:26', '2025-02-08 10:05:27', NULL);
 COMMIT;
 
 -- ----------------------------
@@ -118,20 +121,61 @@ CREATE TABLE `dict_types` (
   `status` int(11) NOT NULL DEFAULT '1' COMMENT '字典状态:1-正常,2-禁用',
   `sort` int(11) NOT NULL COMMENT '排序',
   `remark` varchar(255) NOT NULL COMMENT '备注',
+  `created_by` bigint(20) NOT NULL DEFAULT '0' COMMENT '创建者',
+  `updated_by` bigint(20) NOT NULL DEFAULT '0' COMMENT '更新者',
   `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   `updated_at` timestamp NULL DEFAULT NULL,
-  `deleted_at` int(11) DEFAULT NULL,
-  PRIMARY KEY (`id`)
+  `deleted_at` datetime DEFAULT NULL COMMENT '删除时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_code` (`code`)
 ) ENGINE=InnoDB AUTO_INCREMENT=4 DEFAULT CHARSET=utf8mb4 COMMENT='字典类型表';
 
 -- ----------------------------
 -- Records of dict_types
 -- ----------------------------
 BEGIN;
-INSERT INTO `dict_types` (`id`, `code`, `name`, `status`, `sort`, `remark`, `created_at`, `updated_at`, `deleted_at`) VALUES (1, 'gender', '性别', 1, 1, '备注', '2025-02-08 01:08:22', '2025-02-08 09:08:23', 0);
-INSERT INTO `dict_types` (`id`, `code`, `name`, `status`, `sort`, `remark`, `created_at`, `updated_at`, `deleted_at`) VALUES (3, 'byteOrder', '字节序', 1, 4, '', '2025-02-08 02:06:53', '2025-02-08 10:06:53', 0);
+INSERT INTO `dict_types` (`id`, `code`, `name`, `status`, `sort`, `remark`, `created_at`, `updated_at`, `deleted_at`) VALUES (1, 'gender', '性别', 1, 1, '备注', '2025-02-08 01:08:22', '2025-02-08 09:08:23', NULL);
+INSERT INTO `dict_types` (`id`, `code`, `name`, `status`, `sort`, `remark`, `created_at`, `updated_at`, `deleted_at`) VALUES (3, 'byteOrder', '字节序', 1, 4, '', '2025-02-08 02:06:53', '2025-02-08 10:06:53', NULL);
 COMMIT;
 
+-- ----------------------------
+-- Table structure for oauth_client
+-- ----------------------------
+DROP TABLE IF EXISTS `oauth_client`;
+CREATE TABLE `oauth_client` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `client_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '客户端标识',
+  `client_secret` varchar(128) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '客户端密钥哈希',
+  `name` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '应用名称',
+  `redirect_uris` varchar(1024) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' COMMENT '回调地址,空格分隔',
+  `grant_types` varchar(128) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' COMMENT '授权类型,空格分隔',
+  `scopes` text COLLATE utf8mb4_unicode_ci COMMENT '允许的权限范围(菜单权限标识),空格分隔',
+  `skip_consent` tinyint(1) NOT NULL DEFAULT '0' COMMENT '是否跳过授权确认',
+  `status` tinyint(4) NOT NULL DEFAULT '1' COMMENT '状态:1=正常,2=停用',
+  `remark` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' COMMENT '备注',
+  `created_by` bigint(20) NOT NULL DEFAULT '0' COMMENT '创建者',
+  `updated_by` bigint(20) NOT NULL DEFAULT '0' COMMENT '更新者',
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_oauth_client_client_id` (`client_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='OAuth2客户端应用表';
+
+-- ----------------------------
+-- Table structure for oauth_consent
+-- ----------------------------
+DROP TABLE IF EXISTS `oauth_consent`;
+CREATE TABLE `oauth_consent` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` bigint(20) unsigned NOT NULL COMMENT '用户id',
+  `client_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '客户端标识',
+  `scopes` text COLLATE utf8mb4_unicode_ci COMMENT '已授权的权限范围,空格分隔',
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_oauth_consent_user_client` (`user_id`,`client_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='OAuth2用户授权记录表';
+
 -- ----------------------------
 -- Table structure for role
 -- ----------------------------
@@ -147,6 +191,7 @@ CREATE TABLE `role` (
   `created_at` datetime DEFAULT NULL,
   `updated_at` datetime DEFAULT NULL,
   `remark` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' COMMENT '备注',
+  `deleted_at` datetime DEFAULT NULL COMMENT '删除时间',
   PRIMARY KEY (`id`),
   UNIQUE KEY `role_code_unique` (`code`)
 ) ENGINE=InnoDB AUTO_INCREMENT=21 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='角色信息表';
@@ -233,8 +278,11 @@ CREATE TABLE `sys_menus` (
   `show_link` tinyint(1) DEFAULT '1' COMMENT '是否显示',
   `show_parent` tinyint(1) DEFAULT '0' COMMENT '是否显示父级菜单',
   `status` tinyint(4) DEFAULT '1' COMMENT '菜单状态（0停用 1正常）',
+  `created_by` bigint(20) NOT NULL DEFAULT '0' COMMENT '创建者',
+  `updated_by` bigint(20) NOT NULL DEFAULT '0' COMMENT '更新者',
   `created_at` datetime DEFAULT NULL COMMENT '创建时间',
   `updated_at` datetime DEFAULT NULL COMMENT '更新时间',
+  `deleted_at` datetime DEFAULT NULL COMMENT '删除时间',
   PRIMARY KEY (`id`)
 ) ENGINE=InnoDB AUTO_INCREMENT=24 DEFAULT CHARSET=utf8mb4 COMMENT='菜单权限表';
 
@@ -290,6 +338,7 @@ CREATE TABLE `user` (
   `created_at` datetime DEFAULT NULL,
   `updated_at` datetime DEFAULT NULL,
   `remark` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' COMMENT '备注',
+  `deleted_at` datetime DEFAULT NULL COMMENT '删除时间',
   PRIMARY KEY (`id`),
   UNIQUE KEY `user_username_unique` (`username`)
 ) ENGINE=InnoDB AUTO_INCREMENT=3 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户信息表';
@@ -326,6 +375,23 @@ CREATE TABLE `user_login_log` (
 BEGIN;
 COMMIT;
 
+-- ----------------------------
+-- Table structure for user_identity
+-- ----------------------------
+DROP TABLE IF EXISTS `user_identity`;
+CREATE TABLE `user_identity` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` bigint(20) unsigned NOT NULL COMMENT '用户id',
+  `provider` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '身份提供方',
+  `subject` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '外部用户标识',
+  `email` varchar(128) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' COMMENT '外部邮箱',
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_user_identity_provider_subject` (`provider`(191),`subject`(191)),
+  KEY `idx_user_identity_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户外部身份关联表';
+
 -- ----------------------------
 -- Table structure for user_operation_log
 -- ----------------------------
@@ -337,11 +403,14 @@ CREATE TABLE `user_operation_log` (
   `router` varchar(500) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '请求路由',
   `service_name` varchar(30) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '业务名称',
   `ip` varchar(45) COLLATE utf8mb4_unicode_ci DEFAULT NULL COMMENT '请求IP地址',
+  `impersonator_id` bigint(20) unsigned DEFAULT NULL COMMENT '模拟登录时发起模拟的管理员ID',
+  `impersonator_name` varchar(20) COLLATE utf8mb4_unicode_ci DEFAULT NULL COMMENT '模拟登录时发起模拟的管理员用户名',
   `created_at` timestamp NULL DEFAULT NULL COMMENT '创建时间',
   `updated_at` timestamp NULL DEFAULT NULL COMMENT '更新时间',
   `remark` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL COMMENT '备注',
   PRIMARY KEY (`id`),
-  KEY `user_operation_log_username_index` (`username`)
+  KEY `user_operation_log_username_index` (`username`),
+  KEY `user_operation_log_impersonator_id_index` (`impersonator_id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='操作日志表';
 
 -- ----------------------------
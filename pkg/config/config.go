@@ -69,31 +69,56 @@ type Config struct {
 	Redis    RedisConfig    `mapstructure:"redis"`
 	Database DatabaseConfig `mapstructure:"database"`
 	Log      LogConfig      `mapstructure:"log"`
+	Setting  SettingConfig  `mapstructure:"setting"`
+	Mail     MailConfig     `mapstructure:"mail"`
+	// PasswordReset 找回密码配置
+	PasswordReset PasswordResetConfig `mapstructure:"password_reset"`
+	Auth          AuthConfig          `mapstructure:"auth"`
+	LDAP          LDAPConfig          `mapstructure:"ldap"`
+	OIDC          OIDCConfig          `mapstructure:"oidc"`
+	OAuth         OAuthConfig         `mapstructure:"oauth"`
+	Captcha       CaptchaConfig       `mapstructure:"captcha"`
+	Dict          DictConfig          `mapstructure:"dict"`
+	CORS          CORSConfig          `mapstructure:"cors"`
+	Tracing       TracingConfig       `mapstructure:"tracing"`
+	Metrics       MetricsConfig       `mapstructure:"metrics"`
 }
 
 type ServerConfig struct {
 	Port int    `mapstructure:"port"`
 	Mode string `mapstructure:"mode"`
 	Host string `mapstructure:"host"`
+	// ReadHeaderTimeout、ReadTimeout、WriteTimeout、IdleTimeout 对应 http.Server 的超时设置
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"`
+	ReadTimeout       time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout      time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout       time.Duration `mapstructure:"idle_timeout"`
+	// DrainDelay 收到停止信号后就绪检查先返回不可用，等待该时间让负载均衡摘除实例后再停止接收新连接
+	DrainDelay time.Duration `mapstructure:"drain_delay"`
+	// ShutdownTimeout 等待处理中的请求完成的最长时间，超时后强制关闭连接
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
 }
 
 type JWTConfig struct {
-	AccessSecret  string        `mapstructure:"access_secret"`
-	RefreshSecret string        `mapstructure:"refresh_secret"`
+	AccessSecret  string        `mapstructure:"access_secret" secret:"true"`
+	RefreshSecret string        `mapstructure:"refresh_secret" secret:"true"`
 	AccessExpire  time.Duration `mapstructure:"access_expire"`
 	RefreshExpire time.Duration `mapstructure:"refresh_expire"`
 	Issuer        string        `mapstructure:"issuer"`
+	// ImpersonateExpire 模拟登录令牌有效期
+	ImpersonateExpire time.Duration `mapstructure:"impersonate_expire"`
 }
 
 type RedisConfig struct {
 	Addr     string `mapstructure:"addr"`
-	Password string `mapstructure:"password"`
+	Password string `mapstructure:"password" secret:"true"`
 	DB       int    `mapstructure:"db"`
 }
 
 type DatabaseConfig struct {
 	Driver string `mapstructure:"driver"`
-	DSN    string `mapstructure:"dsn"`
+	// DSN 连接串，输出配置时仅隐藏其中的密码
+	DSN string `mapstructure:"dsn" secret:"dsn"`
 }
 
 type LogConfig struct {
@@ -106,19 +131,182 @@ type LogConfig struct {
 	Compress    bool   `mapstructure:"compress"`
 }
 
+type SettingConfig struct {
+	// BackendDefault 用户未保存后台设置时使用的默认值，结构与 types.BackendSetting 一致
+	BackendDefault map[string]any `mapstructure:"backend_default"`
+}
+
+type MailConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password" secret:"true"`
+	From     string `mapstructure:"from"`
+	// SSL 是否使用隐式 TLS（如 465 端口），否则在服务器支持时自动使用 STARTTLS
+	SSL bool `mapstructure:"ssl"`
+}
+
+type PasswordResetConfig struct {
+	// TokenExpire 重置令牌有效期
+	TokenExpire time.Duration `mapstructure:"token_expire"`
+	// ResetURL 前端重置密码页面地址，令牌以 token 参数附加在地址后
+	ResetURL string `mapstructure:"reset_url"`
+	// RateLimit 同一邮箱在 RateWindow 时间窗口内最多可请求的次数
+	RateLimit  int           `mapstructure:"rate_limit"`
+	RateWindow time.Duration `mapstructure:"rate_window"`
+}
+
+type AuthConfig struct {
+	// Authenticators 登录认证器链，按顺序依次尝试，可选 local、ldap，为空时仅使用 local
+	Authenticators []string `mapstructure:"authenticators"`
+}
+
+type LDAPConfig struct {
+	// URL 服务地址，如 ldap://127.0.0.1:389 或 ldaps://ldap.example.com:636
+	URL      string `mapstructure:"url"`
+	StartTLS bool   `mapstructure:"start_tls"`
+	// InsecureSkipVerify 跳过服务端证书校验，仅用于测试环境
+	InsecureSkipVerify bool          `mapstructure:"insecure_skip_verify"`
+	Timeout            time.Duration `mapstructure:"timeout"`
+	// BindDN、BindPassword 用于查找用户的服务账号，为空时匿名查询
+	BindDN       string `mapstructure:"bind_dn"`
+	BindPassword string `mapstructure:"bind_password" secret:"true"`
+	BaseDN       string `mapstructure:"base_dn"`
+	// UserFilter 用户查询过滤器，%s 会被替换为转义后的用户名，如 (uid=%s)、(sAMAccountName=%s)
+	UserFilter string              `mapstructure:"user_filter"`
+	Attributes LDAPAttributeConfig `mapstructure:"attributes"`
+	// GroupRoles 目录组到系统角色编码的映射
+	GroupRoles []LDAPGroupRoleConfig `mapstructure:"group_roles"`
+	// DefaultRoles 首次登录自动创建用户时未匹配到任何组映射时分配的角色编码
+	DefaultRoles []string `mapstructure:"default_roles"`
+	// SyncRoles 每次登录时按组映射重新同步用户角色
+	SyncRoles bool `mapstructure:"sync_roles"`
+}
+
+type LDAPAttributeConfig struct {
+	Username string `mapstructure:"username"`
+	Nickname string `mapstructure:"nickname"`
+	Email    string `mapstructure:"email"`
+	Phone    string `mapstructure:"phone"`
+	// Group 用户所属组属性，Active Directory 与 OpenLDAP(memberOf overlay) 均为 memberOf
+	Group string `mapstructure:"group"`
+}
+
+type LDAPGroupRoleConfig struct {
+	// Group 组的 DN，比较时忽略大小写
+	Group string   `mapstructure:"group"`
+	Roles []string `mapstructure:"roles"`
+}
+
+type OIDCConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Issuer 身份提供方地址，用于自动发现端点和校验 ID Token 的 iss
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret" secret:"true"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
+	// UsernameClaim 自动创建用户时作为用户名的声明，默认 preferred_username
+	UsernameClaim string `mapstructure:"username_claim"`
+	// LinkByEmail 首次登录时按已验证的邮箱关联已有用户
+	LinkByEmail bool `mapstructure:"link_by_email"`
+	// AutoCreate 未关联到已有用户时自动创建用户
+	AutoCreate   bool     `mapstructure:"auto_create"`
+	DefaultRoles []string `mapstructure:"default_roles"`
+	// StateExpire 登录流程 state 的有效期
+	StateExpire time.Duration `mapstructure:"state_expire"`
+	// SuccessRedirect 登录成功后跳转的前端地址，令牌以 URL 片段传递；为空时回调接口直接返回 JSON
+	SuccessRedirect string `mapstructure:"success_redirect"`
+}
+
+type OAuthConfig struct {
+	// CodeExpire 作为授权服务器签发的授权码有效期
+	CodeExpire time.Duration `mapstructure:"code_expire"`
+}
+
+type CaptchaConfig struct {
+	// Mode 登录验证码策略：always 始终需要（默认），failed 同一 IP 登录失败达到阈值后才需要，off 关闭
+	Mode string `mapstructure:"mode"`
+	// FailThreshold、FailWindow failed 模式下同一 IP 在时间窗口内失败多少次后需要验证码
+	FailThreshold int           `mapstructure:"fail_threshold"`
+	FailWindow    time.Duration `mapstructure:"fail_window"`
+	// Type 验证码类型：digit（默认）、string、math、audio、slider
+	Type   string        `mapstructure:"type"`
+	Width  int           `mapstructure:"width"`
+	Height int           `mapstructure:"height"`
+	Length int           `mapstructure:"length"`
+	Expire time.Duration `mapstructure:"expire"`
+	// NoiseCount、ShowLineOptions string/math 类型的干扰点数量和干扰线选项
+	NoiseCount      int `mapstructure:"noise_count"`
+	ShowLineOptions int `mapstructure:"show_line_options"`
+	// Source string 类型的候选字符
+	Source string `mapstructure:"source"`
+	// Language audio 类型的语音语言，如 en、zh
+	Language string `mapstructure:"language"`
+	// SliderTolerance slider 类型允许的横向偏差像素
+	SliderTolerance int `mapstructure:"slider_tolerance"`
+}
+
+type DictConfig struct {
+	// CacheExpire 字典数据在 Redis 中的缓存时间，修改字典时会主动清除
+	CacheExpire time.Duration `mapstructure:"cache_expire"`
+	// LocalCacheExpire 进程内缓存时间，多实例部署时其他实例最多延迟该时间看到修改，为 0 时不使用进程内缓存
+	LocalCacheExpire time.Duration `mapstructure:"local_cache_expire"`
+	// DeletePolicy 删除仍有字典数据的类型时的处理方式：refuse 拒绝删除（默认），cascade 同时删除其数据
+	DeletePolicy string `mapstructure:"delete_policy"`
+}
+
+type CORSConfig struct {
+	// AllowOrigins 允许跨域访问的来源，如 https://admin.example.com，为空或包含 * 时允许任意来源
+	AllowOrigins []string `mapstructure:"allow_origins"`
+}
+
+// TracingConfig 链路追踪配置
+type TracingConfig struct {
+	// Exporter span 导出方式：otlp 通过 OTLP/HTTP 发送到采集端，stdout 输出到标准输出，为空时不记录 span
+	Exporter string `mapstructure:"exporter"`
+	// Endpoint OTLP/HTTP 采集端地址，如 localhost:4318，为空时使用 OTEL_EXPORTER_OTLP_ENDPOINT 或默认地址
+	Endpoint string `mapstructure:"endpoint"`
+	// Insecure 使用 HTTP 而非 HTTPS 连接采集端
+	Insecure bool `mapstructure:"insecure"`
+	// ServiceName 上报的服务名，默认 gin-casbin-admin
+	ServiceName string `mapstructure:"service_name"`
+	// SampleRatio 新链路的采样比例，取值 0-1，未设置时全部采样，为 0 时不采样；上游请求已决定是否采样时沿用上游的决定
+	SampleRatio *float64 `mapstructure:"sample_ratio"`
+}
+
+// MetricsConfig 监控指标配置
+type MetricsConfig struct {
+	// AllowCIDRs 允许访问 /metrics 的网段，按连接的来源地址判断，不信任 X-Forwarded-For；
+	// 为空时只允许本机和内网地址（127.0.0.0/8、10.0.0.0/8、172.16.0.0/12、192.168.0.0/16、::1、fc00::/7）
+	AllowCIDRs []string `mapstructure:"allow_cidrs"`
+}
+
 func NewConfig(p string) (*Config, error) {
-	envConf := os.Getenv("APP_CONF")
-	if envConf == "" {
-		envConf = p
+	path := Path(p)
+	fmt.Fprintln(os.Stderr, "load conf file:", path)
+	return load(path)
+}
+
+// Path 返回实际使用的配置文件路径，环境变量 APP_CONF 优先于命令行参数。
+// 配置文件中的配置项可被环境变量覆盖，见 EnvPrefix
+func Path(p string) string {
+	if envConf := os.Getenv("APP_CONF"); envConf != "" {
+		return envConf
 	}
-	fmt.Println("load conf file:", envConf)
+	return p
+}
 
+func load(path string) (*Config, error) {
 	v := viper.New()
-	v.SetConfigFile(envConf)
+	v.SetConfigFile(path)
 
 	if err := v.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("failed to read config file: %v", err)
 	}
+	if err := bindEnv(v); err != nil {
+		return nil, err
+	}
 
 	var cfg Config
 	if err := v.Unmarshal(&cfg); err != nil {
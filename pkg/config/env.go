@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix 环境变量前缀，配置项 jwt.access_secret 对应环境变量 APP_JWT_ACCESS_SECRET，
+// 以 _FILE 结尾的同名环境变量（如 APP_JWT_ACCESS_SECRET_FILE）指定从文件读取该配置项，用于挂载的密钥文件
+const EnvPrefix = "APP"
+
+// bindEnv 为所有配置项绑定环境变量，并用 _FILE 环境变量指向的文件内容覆盖配置
+func bindEnv(v *viper.Viper) error {
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	for _, key := range envKeys(reflect.TypeOf(Config{}), "") {
+		if err := v.BindEnv(key); err != nil {
+			return err
+		}
+		name := EnvName(key)
+		path := os.Getenv(name + "_FILE")
+		if path == "" {
+			continue
+		}
+		if _, ok := os.LookupEnv(name); ok {
+			return fmt.Errorf("环境变量 %s 和 %s_FILE 不能同时设置", name, name)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("读取 %s_FILE 指定的文件失败: %w", name, err)
+		}
+		// 密钥文件通常以换行结尾
+		v.Set(key, strings.TrimRight(string(content), "\r\n"))
+	}
+	return nil
+}
+
+// EnvName 返回配置项对应的环境变量名
+func EnvName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// envKeys 返回可通过环境变量设置的配置项，map 和结构体切片无法用字符串表示，只能在配置文件中设置
+func envKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := prefix + f.Tag.Get("mapstructure")
+		switch {
+		case f.Type.Kind() == reflect.Struct:
+			keys = append(keys, envKeys(f.Type, key+".")...)
+		case f.Type.Kind() == reflect.Map,
+			f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.Struct:
+		default:
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
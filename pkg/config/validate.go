@@ -0,0 +1,100 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+)
+
+// Validate 校验配置取值，返回全部不合法的配置项
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.Port >= 0 && c.Server.Port <= 65535, "server.port 应在 0-65535 之间")
+	check(c.Server.ReadHeaderTimeout >= 0 && c.Server.ReadTimeout >= 0 && c.Server.WriteTimeout >= 0 && c.Server.IdleTimeout >= 0,
+		"server 的超时时间不能为负数")
+	check(c.Server.DrainDelay >= 0 && c.Server.ShutdownTimeout >= 0, "server.drain_delay、server.shutdown_timeout 不能为负数")
+
+	// errors.Join 会忽略其中的 nil
+	errs = append(errs, validateSecret("jwt.access_secret", c.JWT.AccessSecret), validateSecret("jwt.refresh_secret", c.JWT.RefreshSecret))
+	check(c.JWT.AccessSecret == "" || c.JWT.AccessSecret != c.JWT.RefreshSecret, "jwt.access_secret 和 jwt.refresh_secret 不能相同")
+	check(c.JWT.AccessExpire > 0, "jwt.access_expire 必须大于 0")
+	check(c.JWT.RefreshExpire >= c.JWT.AccessExpire, "jwt.refresh_expire 不能小于 jwt.access_expire")
+	check(c.JWT.ImpersonateExpire >= 0, "jwt.impersonate_expire 不能为负数")
+
+	check(oneOf(c.Database.Driver, "mysql", "postgres", "sqlite"), "database.driver 不支持 %q，可选 mysql、postgres、sqlite", c.Database.Driver)
+	check(c.Database.DSN != "", "database.dsn 不能为空")
+
+	check(oneOf(c.Log.LogLevel, "", "debug", "info", "warn", "error"), "log.log_level 不支持 %q", c.Log.LogLevel)
+
+	check(oneOf(strings.ToLower(c.Captcha.Mode), "", "always", "failed", "off"), "captcha.mode 不支持 %q", c.Captcha.Mode)
+	check(oneOf(strings.ToLower(c.Captcha.Type), "", "digit", "string", "math", "audio", "slider"), "captcha.type 不支持 %q", c.Captcha.Type)
+	check(c.Captcha.FailThreshold >= 0 && c.Captcha.FailWindow >= 0, "captcha.fail_threshold、captcha.fail_window 不能为负数")
+	check(c.Captcha.Width >= 0 && c.Captcha.Height >= 0 && c.Captcha.Length >= 0 && c.Captcha.Expire >= 0, "captcha 尺寸、长度和有效期不能为负数")
+
+	check(c.PasswordReset.RateLimit >= 0 && c.PasswordReset.RateWindow >= 0, "password_reset.rate_limit、password_reset.rate_window 不能为负数")
+
+	for _, origin := range c.CORS.AllowOrigins {
+		check(validOrigin(origin), "cors.allow_origins 中的 %q 不是合法的来源，应为 * 或 scheme://host[:port]", origin)
+	}
+
+	check(oneOf(c.Tracing.Exporter, "", "otlp", "stdout"), "tracing.exporter 不支持 %q，可选 otlp、stdout", c.Tracing.Exporter)
+	check(c.Tracing.SampleRatio == nil || *c.Tracing.SampleRatio >= 0 && *c.Tracing.SampleRatio <= 1, "tracing.sample_ratio 应在 0-1 之间")
+
+	for _, cidr := range c.Metrics.AllowCIDRs {
+		_, err := netip.ParsePrefix(cidr)
+		check(err == nil, "metrics.allow_cidrs 中的 %q 不是合法的网段，应为 10.0.0.0/8 的形式", cidr)
+	}
+	return errors.Join(errs...)
+}
+
+// minSecretLength HS256 签名密钥的最小长度
+const minSecretLength = 32
+
+// exampleSecrets 示例配置中的密钥，不能用于实际部署
+var exampleSecrets = map[string]bool{
+	"your-access-secret-key-should-be-very-long-and-secure":  true,
+	"your-refresh-secret-key-should-be-very-long-and-secure": true,
+}
+
+// validateSecret 校验签名密钥未缺失、不是示例值且长度足够
+func validateSecret(key, secret string) error {
+	env := EnvName(key)
+	switch {
+	case secret == "":
+		return fmt.Errorf("%s 不能为空，可在配置文件或环境变量 %s、%s_FILE 中设置", key, env, env)
+	case exampleSecrets[secret]:
+		return fmt.Errorf("%s 仍为示例配置中的值，请更换为随机生成的密钥", key)
+	case len(secret) < minSecretLength:
+		return fmt.Errorf("%s 长度不能少于 %d 个字符", key, minSecretLength)
+	}
+	return nil
+}
+
+func oneOf(value string, options ...string) bool {
+	for _, option := range options {
+		if value == option {
+			return true
+		}
+	}
+	return false
+}
+
+// validOrigin 来源只包含协议、主机和端口，不带路径
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && (u.Path == "" || u.Path == "/") && u.RawQuery == "" && u.User == nil
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// Watcher 在配置文件修改或进程收到 SIGHUP 信号时重新加载配置。
+// 新配置校验通过后通知订阅者，订阅者只应用可在运行时安全修改的配置：
+// 日志级别、令牌有效期、验证码、找回密码频率限制和跨域来源，其余配置修改需要重启服务后生效
+type Watcher struct {
+	path    string
+	logger  *zap.Logger
+	current atomic.Pointer[Config]
+
+	// mu 保证同一时间只有一次重新加载，订阅者按注册顺序在锁内依次调用
+	mu          sync.Mutex
+	subscribers []func(prev, next *Config)
+
+	signals  chan os.Signal
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewWatcher(path string, cfg *Config, logger *zap.Logger) *Watcher {
+	w := &Watcher{
+		path:    path,
+		logger:  logger,
+		signals: make(chan os.Signal, 1),
+		done:    make(chan struct{}),
+	}
+	w.current.Store(cfg)
+	return w
+}
+
+// Current 返回当前生效的配置，返回的配置不会被修改
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Subscribe 注册配置变更回调，回调中不能再调用 Subscribe 或 Reload
+func (w *Watcher) Subscribe(fn func(prev, next *Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Start 开始监听配置文件变化和 SIGHUP 信号
+func (w *Watcher) Start() {
+	v := viper.New()
+	v.SetConfigFile(w.path)
+	v.OnConfigChange(func(e fsnotify.Event) {
+		w.reload("file changed")
+	})
+	v.WatchConfig()
+
+	signal.Notify(w.signals, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-w.signals:
+				w.reload("SIGHUP")
+			case <-w.done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止监听 SIGHUP 信号，viper 的文件监听无法停止，停止后文件变化同样不再生效
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() {
+		signal.Stop(w.signals)
+		close(w.done)
+	})
+}
+
+func (w *Watcher) reload(reason string) {
+	select {
+	case <-w.done:
+		return
+	default:
+	}
+	if err := w.Reload(); err != nil {
+		w.logger.Error("配置重新加载失败，继续使用当前配置", zap.String("reason", reason), zap.Error(err))
+	}
+}
+
+// Reload 重新读取配置文件，校验失败时保留当前配置并返回错误
+func (w *Watcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	next, err := load(w.path)
+	if err != nil {
+		return err
+	}
+	if err := next.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	prev := w.current.Load()
+	if reflect.DeepEqual(prev, next) {
+		return nil
+	}
+	if sections := restartRequired(prev, next); len(sections) > 0 {
+		w.logger.Warn("以下配置的修改需要重启服务后生效", zap.Strings("sections", sections))
+	}
+	w.current.Store(next)
+	for _, fn := range w.subscribers {
+		fn(prev, next)
+	}
+	w.logger.Info("配置已重新加载", zap.String("path", w.path))
+	return nil
+}
+
+// withoutReloadable 清空可在运行时生效的配置项
+func withoutReloadable(c Config) Config {
+	c.Log.LogLevel = ""
+	c.JWT.AccessExpire, c.JWT.RefreshExpire, c.JWT.ImpersonateExpire = 0, 0, 0
+	c.Captcha = CaptchaConfig{}
+	c.PasswordReset.RateLimit, c.PasswordReset.RateWindow = 0, 0
+	c.CORS = CORSConfig{}
+	return c
+}
+
+// restartRequired 返回除可热更新的配置项外仍有修改的配置段
+func restartRequired(prev, next *Config) []string {
+	a, b := reflect.ValueOf(withoutReloadable(*prev)), reflect.ValueOf(withoutReloadable(*next))
+	var sections []string
+	for i := 0; i < a.NumField(); i++ {
+		if !reflect.DeepEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+			sections = append(sections, a.Type().Field(i).Tag.Get("mapstructure"))
+		}
+	}
+	return sections
+}
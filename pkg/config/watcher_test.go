@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const testConfig = `
+jwt:
+  access_secret: test-access-secret-0123456789abcdef
+  refresh_secret: test-refresh-secret-0123456789abcdef
+  access_expire: %ACCESS%
+  refresh_expire: 24h
+log:
+  log_level: info
+captcha:
+  mode: %MODE%
+database:
+  driver: sqlite
+  dsn: %DSN%
+cors:
+  allow_origins: ["https://admin.example.com"]
+`
+
+func writeConfig(t *testing.T, path, access, mode, dsn string) {
+	t.Helper()
+	content := strings.NewReplacer("%ACCESS%", access, "%MODE%", mode, "%DSN%", dsn).Replace(testConfig)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcherReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "1h", "always", "a.db")
+	cfg, err := NewConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	w := NewWatcher(path, cfg, zap.NewNop())
+	var notified []*Config
+	w.Subscribe(func(prev, next *Config) {
+		if prev != cfg {
+			t.Errorf("subscriber should receive the previous config")
+		}
+		notified = append(notified, next)
+	})
+
+	// 未修改时不通知订阅者
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if len(notified) != 0 {
+		t.Fatalf("unchanged reload notified %d times", len(notified))
+	}
+
+	writeConfig(t, path, "2h", "failed", "a.db")
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if len(notified) != 1 || notified[0].JWT.AccessExpire != 2*time.Hour || notified[0].Captcha.Mode != "failed" {
+		t.Fatalf("reload notified %+v", notified)
+	}
+	if w.Current() != notified[0] || cfg.JWT.AccessExpire != time.Hour {
+		t.Errorf("reload should replace the current config without modifying the previous one")
+	}
+
+	// 校验失败时保留当前配置
+	writeConfig(t, path, "2h", "sometimes", "a.db")
+	if err := w.Reload(); err == nil || !strings.Contains(err.Error(), "captcha.mode") {
+		t.Errorf("Reload() with invalid captcha mode = %v", err)
+	}
+	if len(notified) != 1 || w.Current().Captcha.Mode != "failed" {
+		t.Errorf("invalid reload should keep the current config")
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	prev := &Config{Database: DatabaseConfig{DSN: "a.db"}, JWT: JWTConfig{AccessExpire: time.Hour}}
+	next := &Config{Database: DatabaseConfig{DSN: "b.db"}, JWT: JWTConfig{AccessExpire: 2 * time.Hour}, CORS: CORSConfig{AllowOrigins: []string{"*"}}}
+	if got := restartRequired(prev, next); !reflect.DeepEqual(got, []string{"database"}) {
+		t.Errorf("restartRequired() = %v, want [database]", got)
+	}
+	next.JWT.AccessSecret = "changed"
+	if got := restartRequired(prev, next); !reflect.DeepEqual(got, []string{"jwt", "database"}) {
+		t.Errorf("restartRequired() = %v, want [jwt database]", got)
+	}
+}
+
+func TestValidateOrigins(t *testing.T) {
+	for origin, want := range map[string]bool{
+		"*":                              true,
+		"https://admin.example.com":      true,
+		"http://localhost:3000":          true,
+		"https://admin.example.com/":     true,
+		"admin.example.com":              false,
+		"https://admin.example.com/path": false,
+		"ftp://example.com":              false,
+	} {
+		if got := validOrigin(origin); got != want {
+			t.Errorf("validOrigin(%q) = %v, want %v", origin, got, want)
+		}
+	}
+}
@@ -8,14 +8,15 @@ const (
 	Success ErrorCode = 0
 
 	// 通用错误 (10000-19999)
-	Unknown       ErrorCode = 10000 // 未知错误
-	InvalidParam  ErrorCode = 10001 // 参数错误
-	Unauthorized  ErrorCode = 10002 // 未授权
-	Forbidden     ErrorCode = 10003 // 禁止访问
-	NotFound      ErrorCode = 10004 // 资源不存在
-	AlreadyExists ErrorCode = 10005 // 资源已存在
-	ServerError   ErrorCode = 10006 // 服务器错误
-	DatabaseError ErrorCode = 10007 // 数据库错误
+	Unknown         ErrorCode = 10000 // 未知错误
+	InvalidParam    ErrorCode = 10001 // 参数错误
+	Unauthorized    ErrorCode = 10002 // 未授权
+	Forbidden       ErrorCode = 10003 // 禁止访问
+	NotFound        ErrorCode = 10004 // 资源不存在
+	AlreadyExists   ErrorCode = 10005 // 资源已存在
+	ServerError     ErrorCode = 10006 // 服务器错误
+	DatabaseError   ErrorCode = 10007 // 数据库错误
+	TooManyRequests ErrorCode = 10008 // 请求过于频繁
 
 	// 业务错误 (20000-99999)
 	// 按错误类型分类，而不是按模块分类
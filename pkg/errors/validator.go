@@ -15,6 +15,7 @@ var ValidateErrorMsg = map[string]string{
 	"max":      "不能大于 %s",
 	"len":      "长度必须是 %s",
 	"oneof":    "必须是 [%s] 中的一个",
+	"nefield":  "不能与 %s 相同",
 }
 
 // ParseValidateError 解析验证错误
@@ -0,0 +1,216 @@
+// Package exportx 将列表数据按批流式导出为 CSV、XLSX 或 JSON Lines
+package exportx
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wxlbd/gin-casbin-admin/pkg/sheetx"
+)
+
+type Format string
+
+const (
+	FormatCSV   Format = Format(sheetx.FormatCSV)
+	FormatXLSX  Format = Format(sheetx.FormatXLSX)
+	FormatJSONL Format = "jsonl"
+
+	// DefaultLang 未指定语言或语言不受支持时使用的表头语言
+	DefaultLang = "zh"
+	// DefaultBatchSize 每批查询的行数
+	DefaultBatchSize = 100
+)
+
+// ParseFormat 解析导出格式，空值默认为 XLSX
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case "", FormatXLSX:
+		return FormatXLSX, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatJSONL, "ndjson":
+		return FormatJSONL, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q", s)
+	}
+}
+
+// ContentType 格式对应的 MIME 类型
+func (f Format) ContentType() string {
+	if f == FormatJSONL {
+		return "application/x-ndjson; charset=utf-8"
+	}
+	return sheetx.Format(f).ContentType()
+}
+
+// Column 导出列
+type Column[T any] struct {
+	// Key 列标识，用于列选择和 JSON Lines 的字段名
+	Key string
+	// Headers 各语言的表头，键为语言标签如 zh、en
+	Headers map[string]string
+	Value   func(T) any
+}
+
+// Header 获取指定语言的表头，依次尝试完整语言标签、主语言、默认语言，都没有时使用 Key
+func (c Column[T]) Header(lang string) string {
+	lang = strings.ToLower(lang)
+	if h, ok := c.Headers[lang]; ok {
+		return h
+	}
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		if h, ok := c.Headers[lang[:i]]; ok {
+			return h
+		}
+	}
+	if h, ok := c.Headers[DefaultLang]; ok {
+		return h
+	}
+	return c.Key
+}
+
+// Select 按 keys 的顺序选择列，keys 为空时返回全部列
+func Select[T any](columns []Column[T], keys []string) ([]Column[T], error) {
+	if len(keys) == 0 {
+		return columns, nil
+	}
+	selected := make([]Column[T], 0, len(keys))
+	for _, key := range keys {
+		found := false
+		for _, col := range columns {
+			if col.Key == key {
+				selected = append(selected, col)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q", key)
+		}
+	}
+	return selected, nil
+}
+
+// FetchFunc 按页获取数据，page 从 1 开始，返回的行数少于 size 时视为最后一页
+type FetchFunc[T any] func(ctx context.Context, page, size int) ([]T, error)
+
+// Options 导出选项
+type Options struct {
+	Format Format
+	// Lang 表头语言
+	Lang string
+	// BatchSize 每批获取的行数，默认为 DefaultBatchSize
+	BatchSize int
+}
+
+// Export 分批获取数据并逐行写出，内存中最多只保留一批数据。CSV 和 XLSX 中的字符串单元格
+// 会经 sheetx.EscapeFormula 转义，JSON Lines 保持原值
+func Export[T any](ctx context.Context, w io.Writer, opts Options, columns []Column[T], fetch FetchFunc[T]) error {
+	size := opts.BatchSize
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+	rw, err := newRowWriter(w, opts.Format, columns, opts.Lang)
+	if err != nil {
+		return err
+	}
+	// 客户端断开或查询失败时中止写入，释放 XLSX 的临时文件
+	done := false
+	defer func() {
+		if !done {
+			rw.Abort()
+		}
+	}()
+	row := make([]any, len(columns))
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		items, err := fetch(ctx, page, size)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			for i, col := range columns {
+				row[i] = col.Value(item)
+			}
+			if err := rw.Write(row); err != nil {
+				return err
+			}
+		}
+		if len(items) < size {
+			break
+		}
+	}
+	done = true
+	return rw.Close()
+}
+
+func newRowWriter[T any](w io.Writer, format Format, columns []Column[T], lang string) (sheetx.Writer, error) {
+	if format == FormatJSONL {
+		keys := make([]string, len(columns))
+		for i, col := range columns {
+			keys[i] = col.Key
+		}
+		return newJSONLWriter(w, keys), nil
+	}
+	sw, err := sheetx.NewWriter(w, sheetx.Format(format))
+	if err != nil {
+		return nil, err
+	}
+	header := make([]any, len(columns))
+	for i, col := range columns {
+		header[i] = col.Header(lang)
+	}
+	if err := sw.Write(header); err != nil {
+		sw.Abort()
+		return nil, err
+	}
+	return sw, nil
+}
+
+// jsonlWriter 每行输出一个 JSON 对象，字段顺序与列顺序一致
+type jsonlWriter struct {
+	w    *bufio.Writer
+	keys [][]byte
+	buf  bytes.Buffer
+}
+
+func newJSONLWriter(w io.Writer, keys []string) *jsonlWriter {
+	jw := &jsonlWriter{w: bufio.NewWriter(w), keys: make([][]byte, len(keys))}
+	for i, key := range keys {
+		jw.keys[i], _ = json.Marshal(key)
+	}
+	return jw
+}
+
+func (j *jsonlWriter) Write(row []any) error {
+	j.buf.Reset()
+	j.buf.WriteByte('{')
+	for i, v := range row {
+		if i > 0 {
+			j.buf.WriteByte(',')
+		}
+		j.buf.Write(j.keys[i])
+		j.buf.WriteByte(':')
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		j.buf.Write(b)
+	}
+	j.buf.WriteString("}\n")
+	_, err := j.w.Write(j.buf.Bytes())
+	return err
+}
+
+func (j *jsonlWriter) Close() error {
+	return j.w.Flush()
+}
+
+func (j *jsonlWriter) Abort() {}
@@ -0,0 +1,158 @@
+package exportx
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+type item struct {
+	ID   int
+	Name string
+}
+
+var testColumns = []Column[*item]{
+	{Key: "id", Headers: map[string]string{"zh": "编号", "en": "ID"}, Value: func(i *item) any { return i.ID }},
+	{Key: "name", Headers: map[string]string{"zh": "名称", "en-gb": "Name (UK)"}, Value: func(i *item) any { return i.Name }},
+}
+
+// fetchItems 返回 total 条数据，并记录请求的页数
+func fetchItems(total int, pages *int) FetchFunc[*item] {
+	return func(_ context.Context, page, size int) ([]*item, error) {
+		*pages++
+		var items []*item
+		for i := (page-1)*size + 1; i <= total && i <= page*size; i++ {
+			items = append(items, &item{ID: i, Name: "n\"" + string(rune('a'+i-1))})
+		}
+		return items, nil
+	}
+}
+
+func TestColumnHeader(t *testing.T) {
+	tests := map[string]string{"": "编号", "en": "ID", "en-US": "ID", "fr": "编号"}
+	for lang, want := range tests {
+		if got := testColumns[0].Header(lang); got != want {
+			t.Errorf("Header(%q) = %q, want %q", lang, got, want)
+		}
+	}
+	if got := testColumns[1].Header("en-GB"); got != "Name (UK)" {
+		t.Errorf("Header(en-GB) = %q", got)
+	}
+	if got := (Column[*item]{Key: "raw"}).Header("en"); got != "raw" {
+		t.Errorf("Header() without headers = %q", got)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	cols, err := Select(testColumns, []string{"name", "id"})
+	if err != nil || len(cols) != 2 || cols[0].Key != "name" || cols[1].Key != "id" {
+		t.Fatalf("Select() = %v, %v", cols, err)
+	}
+	if cols, _ := Select(testColumns, nil); len(cols) != 2 {
+		t.Errorf("Select(nil) = %v", cols)
+	}
+	if _, err := Select(testColumns, []string{"missing"}); err == nil {
+		t.Error("Select() with unknown column should fail")
+	}
+}
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		lang   string
+		cols   []string
+		want   string
+	}{
+		{
+			name:   "jsonl keeps column order",
+			format: FormatJSONL,
+			cols:   []string{"name", "id"},
+			want:   "{\"name\":\"n\\\"a\",\"id\":1}\n{\"name\":\"n\\\"b\",\"id\":2}\n{\"name\":\"n\\\"c\",\"id\":3}\n",
+		},
+		{
+			name:   "csv with localized header",
+			format: FormatCSV,
+			lang:   "en",
+			want:   "\ufeffID,名称\n1,\"n\"\"a\"\n2,\"n\"\"b\"\n3,\"n\"\"c\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cols, _ := Select(testColumns, tt.cols)
+			var buf bytes.Buffer
+			pages := 0
+			err := Export(context.Background(), &buf, Options{Format: tt.format, Lang: tt.lang, BatchSize: 2}, cols, fetchItems(3, &pages))
+			if err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Export() = %q, want %q", buf.String(), tt.want)
+			}
+			if pages != 2 {
+				t.Errorf("fetched %d pages, want 2", pages)
+			}
+		})
+	}
+}
+
+func TestExportEscapesFormula(t *testing.T) {
+	fetch := func(_ context.Context, page, _ int) ([]*item, error) {
+		if page > 1 {
+			return nil, nil
+		}
+		return []*item{{ID: -1, Name: "=1+1"}, {ID: 2, Name: "@SUM(A1)"}}, nil
+	}
+	tests := map[Format]string{
+		FormatCSV:   "\ufeff编号,名称\n-1,'=1+1\n2,'@SUM(A1)\n",
+		FormatJSONL: "{\"id\":-1,\"name\":\"=1+1\"}\n{\"id\":2,\"name\":\"@SUM(A1)\"}\n",
+	}
+	for format, want := range tests {
+		var buf bytes.Buffer
+		if err := Export(context.Background(), &buf, Options{Format: format}, testColumns, fetch); err != nil {
+			t.Fatalf("Export(%s) error = %v", format, err)
+		}
+		if buf.String() != want {
+			t.Errorf("Export(%s) = %q, want %q", format, buf.String(), want)
+		}
+	}
+}
+
+func TestExportCanceled(t *testing.T) {
+	// excelize 的流式写入超过 16MB 后写入临时文件，中途取消时必须删除
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	name := strings.Repeat("x", 32000)
+	fetch := func(_ context.Context, page, size int) ([]*item, error) {
+		// 第 2 页后客户端断开
+		if page == 2 {
+			cancel()
+		}
+		items := make([]*item, size)
+		for i := range items {
+			items[i] = &item{ID: i, Name: name}
+		}
+		return items, nil
+	}
+
+	var buf bytes.Buffer
+	err := Export(ctx, &buf, Options{Format: FormatXLSX, BatchSize: 300}, testColumns, fetch)
+	if err != context.Canceled {
+		t.Fatalf("Export() error = %v, want %v", err, context.Canceled)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("canceled export wrote %d bytes", buf.Len())
+	}
+	entries, err := os.ReadDir(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("temp files left behind: %v", entries)
+	}
+}
@@ -12,6 +12,13 @@ const (
 	ERROR   = 500
 )
 
+const (
+	// RequestIDHeader 请求ID所在的请求头和响应头
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDKey 请求ID在 gin 上下文中的键
+	RequestIDKey = "request_id"
+)
+
 type ListData struct {
 	List  any   `json:"list"`
 	Total int64 `json:"total"`
@@ -21,14 +28,22 @@ type Response struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
 	Data    any    `json:"data,omitempty"`
+	// RequestID 请求ID，反馈问题时据此查找对应的日志
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// RequestID 返回请求ID中间件为当前请求设置的ID
+func RequestID(c *gin.Context) string {
+	return c.GetString(RequestIDKey)
 }
 
 // Success 成功响应
 func Success(c *gin.Context, data any) {
 	c.JSON(200, Response{
-		Code:    SUCCESS,
-		Message: "success",
-		Data:    data,
+		Code:      SUCCESS,
+		Message:   "success",
+		Data:      data,
+		RequestID: RequestID(c),
 	})
 }
 
@@ -39,8 +54,9 @@ func Error(c *gin.Context, businessCode int, message string, httpCode ...int) {
 		hc = httpCode[0]
 	}
 	c.JSON(hc, Response{
-		Code:    businessCode,
-		Message: message,
+		Code:      businessCode,
+		Message:   message,
+		RequestID: RequestID(c),
 	})
 }
 
@@ -0,0 +1,74 @@
+package gormmetrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/wxlbd/gin-casbin-admin/pkg/metrics"
+	"gorm.io/gorm"
+)
+
+const startKey = "gormmetrics:start"
+
+// Plugin 记录每条 SQL 的执行耗时，按操作类型、数据表和执行结果汇总到 metrics.DBQueryDuration
+type Plugin struct{}
+
+func New() *Plugin {
+	return &Plugin{}
+}
+
+func (p *Plugin) Name() string {
+	return "gormmetrics"
+}
+
+// Initialize 在各类操作的回调链首尾注册计时回调
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	processors := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("*").Register, cb.Create().After("*").Register},
+		{"query", cb.Query().Before("*").Register, cb.Query().After("*").Register},
+		{"update", cb.Update().Before("*").Register, cb.Update().After("*").Register},
+		{"delete", cb.Delete().Before("*").Register, cb.Delete().After("*").Register},
+		{"row", cb.Row().Before("*").Register, cb.Row().After("*").Register},
+		{"raw", cb.Raw().Before("*").Register, cb.Raw().After("*").Register},
+	}
+	for _, processor := range processors {
+		if err := processor.before("gormmetrics:before_"+processor.operation, before); err != nil {
+			return err
+		}
+		if err := processor.after("gormmetrics:after_"+processor.operation, after(processor.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func before(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+func after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		status := "ok"
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			status = "error"
+		}
+		metrics.DBQueryDuration.WithLabelValues(operation, table, status).Observe(time.Since(start).Seconds())
+	}
+}
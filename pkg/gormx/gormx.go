@@ -1,18 +1,23 @@
 package gormx
 
 import (
+	"strings"
 	"time"
 
 	"github.com/glebarez/sqlite"
 	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/gormmetrics"
+	"github.com/wxlbd/gin-casbin-admin/pkg/gormtrace"
 	"github.com/wxlbd/gin-casbin-admin/pkg/log"
 	"github.com/wxlbd/gin-casbin-admin/pkg/zapgorm2"
+	"go.uber.org/zap"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func NewDB(conf *config.Config, l *log.Logger) *gorm.DB {
+// NewDB 连接数据库，返回的 cleanup 关闭连接池
+func NewDB(conf *config.Config, l *log.Logger) (*gorm.DB, func()) {
 	var (
 		db  *gorm.DB
 		err error
@@ -23,18 +28,19 @@ func NewDB(conf *config.Config, l *log.Logger) *gorm.DB {
 	dsn := conf.Database.DSN
 
 	// GORM doc: https://gorm.io/docs/connecting_to_the_database.html
+	gormConfig := &gorm.Config{
+		Logger: logger,
+	}
 	switch driver {
 	case "mysql":
-		db, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
-			Logger: logger,
-		})
+		db, err = gorm.Open(mysql.Open(dsn), gormConfig)
 	case "postgres":
 		db, err = gorm.Open(postgres.New(postgres.Config{
 			DSN:                  dsn,
 			PreferSimpleProtocol: true, // disables implicit prepared statement usage
-		}), &gorm.Config{})
+		}), gormConfig)
 	case "sqlite":
-		db, err = gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+		db, err = gorm.Open(sqlite.Open(SQLiteDSN(dsn)), gormConfig)
 	default:
 		panic("unknown db driver")
 	}
@@ -42,6 +48,15 @@ func NewDB(conf *config.Config, l *log.Logger) *gorm.DB {
 		panic(err)
 	}
 	db = db.Debug()
+	if err := RegisterOperatorCallbacks(db); err != nil {
+		panic(err)
+	}
+	if err := db.Use(gormmetrics.New()); err != nil {
+		panic(err)
+	}
+	if err := db.Use(gormtrace.New()); err != nil {
+		panic(err)
+	}
 
 	// Connection Pool config
 	sqlDB, err := db.DB()
@@ -51,5 +66,23 @@ func NewDB(conf *config.Config, l *log.Logger) *gorm.DB {
 	sqlDB.SetMaxIdleConns(10)
 	sqlDB.SetMaxOpenConns(100)
 	sqlDB.SetConnMaxLifetime(time.Hour)
-	return db
+	cleanup := func() {
+		if err := sqlDB.Close(); err != nil {
+			l.Error("关闭数据库连接失败", zap.Error(err))
+		}
+	}
+	return db, cleanup
+}
+
+// SQLiteDSN 为未指定 _pragma 参数的 SQLite 连接串设置忙等待超时并开启 WAL，
+// 避免多个连接同时写入时直接返回 database is locked
+func SQLiteDSN(dsn string) string {
+	if strings.Contains(dsn, "_pragma=") || strings.Contains(dsn, ":memory:") {
+		return dsn
+	}
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	return dsn + sep + "_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
 }
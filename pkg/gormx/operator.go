@@ -0,0 +1,94 @@
+package gormx
+
+import (
+	"context"
+	"reflect"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	columnCreatedBy = "created_by"
+	columnUpdatedBy = "updated_by"
+)
+
+type operatorKey struct{}
+
+// WithOperator 将当前操作人ID存入上下文，写入数据时据此填充 created_by、updated_by
+func WithOperator(ctx context.Context, userID uint64) context.Context {
+	return context.WithValue(ctx, operatorKey{}, userID)
+}
+
+// OperatorFrom 获取上下文中的操作人ID
+func OperatorFrom(ctx context.Context) (uint64, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	id, ok := ctx.Value(operatorKey{}).(uint64)
+	return id, ok && id != 0
+}
+
+// RegisterOperatorCallbacks 注册回调，创建时填充未设置的 created_by 和 updated_by，更新时覆盖 updated_by 并保留 created_by，
+// 上下文中没有操作人或模型没有对应字段时不做处理
+func RegisterOperatorCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("gormx:operator_create", fillOperatorOnCreate); err != nil {
+		return err
+	}
+	return db.Callback().Update().Before("gorm:update").Register("gormx:operator_update", fillOperatorOnUpdate)
+}
+
+func fillOperatorOnCreate(db *gorm.DB) {
+	id, ok := OperatorFrom(db.Statement.Context)
+	if !ok || db.Statement.Schema == nil {
+		return
+	}
+	for _, column := range []string{columnCreatedBy, columnUpdatedBy} {
+		field := db.Statement.Schema.LookUpField(column)
+		if field == nil {
+			continue
+		}
+		rv := db.Statement.ReflectValue
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				setIfZero(db, field, reflect.Indirect(rv.Index(i)), id)
+			}
+		case reflect.Struct:
+			setIfZero(db, field, rv, id)
+		}
+	}
+}
+
+func fillOperatorOnUpdate(db *gorm.DB) {
+	stmt := db.Statement
+	// UpdateColumn 等跳过钩子的更新与 updated_at 一样不填充
+	if stmt.SkipHooks || stmt.Schema == nil {
+		return
+	}
+	// Save 和 Select("*") 会写入全部字段，创建人只在创建时写入
+	if stmt.Schema.LookUpField(columnCreatedBy) != nil {
+		stmt.Omits = append(stmt.Omits, columnCreatedBy)
+	}
+	id, ok := OperatorFrom(stmt.Context)
+	if !ok || stmt.Schema.LookUpField(columnUpdatedBy) == nil {
+		return
+	}
+	if len(stmt.Selects) > 0 && !slices.Contains(stmt.Selects, "*") {
+		stmt.Selects = append(stmt.Selects, columnUpdatedBy)
+	}
+	stmt.SetColumn(columnUpdatedBy, id, true)
+}
+
+func setIfZero(db *gorm.DB, field *schema.Field, rv reflect.Value, id uint64) {
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+	if _, zero := field.ValueOf(db.Statement.Context, rv); !zero {
+		return
+	}
+	if err := field.Set(db.Statement.Context, rv, id); err != nil {
+		_ = db.AddError(err)
+	}
+}
@@ -0,0 +1,83 @@
+package gormx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+type operatorRecord struct {
+	ID        uint64
+	Name      string
+	CreatedBy uint64
+	UpdatedBy int64
+}
+
+func newOperatorTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterOperatorCallbacks(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&operatorRecord{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestOperatorCallbacks(t *testing.T) {
+	db := newOperatorTestDB(t)
+	ctx := WithOperator(context.Background(), 7)
+
+	records := []*operatorRecord{{Name: "a"}, {Name: "b", CreatedBy: 3}}
+	if err := db.WithContext(ctx).Create(records).Error; err != nil {
+		t.Fatal(err)
+	}
+	if records[0].CreatedBy != 7 || records[0].UpdatedBy != 7 {
+		t.Errorf("record a = %+v, want created_by/updated_by 7", records[0])
+	}
+	if records[1].CreatedBy != 3 {
+		t.Errorf("record b created_by = %d, explicit value should be kept", records[1].CreatedBy)
+	}
+
+	ctx = WithOperator(context.Background(), 9)
+	if err := db.WithContext(ctx).Model(&operatorRecord{}).Where("id = ?", records[0].ID).Update("name", "c").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.WithContext(ctx).Model(records[1]).Select("name").Updates(&operatorRecord{Name: "d"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	// Save 写入全部字段时不应清空创建人
+	if err := db.WithContext(ctx).Save(&operatorRecord{ID: records[0].ID, Name: "c"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	var got []*operatorRecord
+	if err := db.Order("id").Find(&got).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range got {
+		if r.UpdatedBy != 9 {
+			t.Errorf("record %s updated_by = %d, want 9", r.Name, r.UpdatedBy)
+		}
+	}
+	if got[0].CreatedBy != 7 {
+		t.Errorf("created_by changed on update: %d", got[0].CreatedBy)
+	}
+
+	// 没有操作人时不修改
+	if err := db.Model(&operatorRecord{}).Where("id = ?", records[0].ID).Update("name", "e").Error; err != nil {
+		t.Fatal(err)
+	}
+	var r operatorRecord
+	if err := db.First(&r, records[0].ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if r.UpdatedBy != 9 {
+		t.Errorf("updated_by = %d without operator, want unchanged 9", r.UpdatedBy)
+	}
+}
@@ -1,6 +1,9 @@
 package sid
 
 import (
+	"hash/fnv"
+	"os"
+
 	"github.com/pkg/errors"
 	"github.com/sony/sonyflake"
 	"github.com/wxlbd/gin-casbin-admin/pkg/helper/convert"
@@ -12,6 +15,10 @@ type Sid struct {
 
 func NewSid() *Sid {
 	sf := sonyflake.NewSonyflake(sonyflake.Settings{})
+	if sf == nil {
+		// 默认使用私有 IPv4 地址的低 16 位作为机器ID，没有私有地址的主机改用主机名生成
+		sf = sonyflake.NewSonyflake(sonyflake.Settings{MachineID: hostnameMachineID})
+	}
 	if sf == nil {
 		panic("sonyflake not created")
 	}
@@ -32,3 +39,14 @@ func (s Sid) GenUint64() (uint64, error) {
 	// 生成分布式ID
 	return s.sf.NextID()
 }
+
+// hostnameMachineID 取主机名哈希值的低 16 位作为机器ID
+func hostnameMachineID() (uint16, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return 0, err
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(hostname))
+	return uint16(h.Sum32()), nil
+}
@@ -2,47 +2,144 @@ package http
 
 import (
 	"context"
-	"github.com/gin-gonic/gin"
-	"log"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
+	"sync/atomic"
 	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultReadHeaderTimeout = 10 * time.Second
+	defaultReadTimeout       = 30 * time.Second
+	defaultWriteTimeout      = 60 * time.Second
+	defaultIdleTimeout       = 120 * time.Second
+	defaultShutdownTimeout   = 30 * time.Second
 )
 
-func Run(r *gin.Engine, addr string) {
+// State 服务运行状态，开始停机后就绪检查返回不可用，使负载均衡在连接关闭前摘除流量
+type State struct {
+	draining atomic.Bool
+}
+
+func NewState() *State {
+	return &State{}
+}
+
+// Drain 标记服务开始停机
+func (s *State) Drain() {
+	s.draining.Store(true)
+}
+
+// Draining 服务是否正在停机
+func (s *State) Draining() bool {
+	return s.draining.Load()
+}
+
+// Server 支持优雅停机的 HTTP 服务
+type Server struct {
+	srv    *http.Server
+	config config.ServerConfig
+	logger *log.Logger
+	state  *State
+}
+
+func NewServer(cfg *config.Config, logger *log.Logger, handler *gin.Engine, state *State) *Server {
+	c := cfg.Server
+	if c.ReadHeaderTimeout <= 0 {
+		c.ReadHeaderTimeout = defaultReadHeaderTimeout
+	}
+	if c.ReadTimeout <= 0 {
+		c.ReadTimeout = defaultReadTimeout
+	}
+	if c.WriteTimeout <= 0 {
+		c.WriteTimeout = defaultWriteTimeout
+	}
+	if c.IdleTimeout <= 0 {
+		c.IdleTimeout = defaultIdleTimeout
+	}
+	if c.ShutdownTimeout <= 0 {
+		c.ShutdownTimeout = defaultShutdownTimeout
+	}
+	return &Server{
+		srv: &http.Server{
+			Addr:              fmt.Sprintf("%s:%d", c.Host, c.Port),
+			Handler:           handler,
+			ReadHeaderTimeout: c.ReadHeaderTimeout,
+			ReadTimeout:       c.ReadTimeout,
+			WriteTimeout:      c.WriteTimeout,
+			IdleTimeout:       c.IdleTimeout,
+		},
+		config: c,
+		logger: logger,
+		state:  state,
+	}
+}
 
-	srv := &http.Server{
-		Addr:    addr,
-		Handler: r,
+// Run 监听配置的地址并提供服务，直到 ctx 取消后优雅停机
+func (s *Server) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		return err
 	}
+	return s.Serve(ctx, ln)
+}
 
-	// Initializing the server in a goroutine so that
-	// it won't block the graceful shutdown handling below
+// Serve 在 ln 上提供服务并阻塞到 ctx 取消，随后依次：将就绪检查切换为不可用，
+// 等待 DrainDelay 让负载均衡摘除实例，停止接收新连接并在 ShutdownTimeout 内等待处理中的请求完成
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	errCh := make(chan error, 1)
 	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("listen: %s\n", err)
-		}
+		errCh <- s.srv.Serve(ln)
 	}()
+	s.logger.Info("server start", zap.String("host", "http://"+ln.Addr().String()))
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.state.Drain()
+	s.logger.Info("开始停止服务，就绪检查已切换为不可用", zap.Duration("drain_delay", s.config.DrainDelay))
+	time.Sleep(s.config.DrainDelay)
 
-	// Wait for interrupt signal to gracefully shutdown the server with
-	// a timeout of 5 seconds.
-	quit := make(chan os.Signal, 1)
-	// kill (no param) default send syscall.SIGTERM
-	// kill -2 is syscall.SIGINT
-	// kill -9 is syscall.SIGKILL but can't be catch, so don't need add it
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	log.Println("Shutting down server...")
-
-	// The context is used to inform the server it has 5 seconds to finish
-	// the request it is currently handling
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
 	defer cancel()
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown: ", err)
+	if err := s.srv.Shutdown(shutdownCtx); err != nil {
+		// 超时后仍未完成的请求被强制中断
+		_ = s.srv.Close()
+		return fmt.Errorf("graceful shutdown: %w", err)
 	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	s.logger.Info("HTTP 服务已停止")
+	return nil
+}
+
+// streamWriter 每次写入前顺延连接的写超时
+type streamWriter struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	timeout time.Duration
+}
+
+// StreamWriter 包装流式下载的响应，每次写入前把写超时顺延 timeout。服务端的 WriteTimeout
+// 从读取请求开始计算，会截断耗时较长的大文件下载；顺延后只要持续有数据写出就不会超时，
+// 客户端长时间不读取数据时仍会中断。底层连接不支持设置超时时保持服务端的超时设置
+func StreamWriter(w http.ResponseWriter, timeout time.Duration) io.Writer {
+	return &streamWriter{w: w, rc: http.NewResponseController(w), timeout: timeout}
+}
 
-	log.Println("Server exiting")
+func (s *streamWriter) Write(p []byte) (int, error) {
+	_ = s.rc.SetWriteDeadline(time.Now().Add(s.timeout))
+	return s.w.Write(p)
 }
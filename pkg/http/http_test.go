@@ -0,0 +1,115 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"go.uber.org/zap"
+)
+
+func TestServerGracefulShutdown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	started := make(chan struct{})
+	r := gin.New()
+	r.GET("/slow", func(c *gin.Context) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		c.String(http.StatusOK, "done")
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := NewState()
+	cfg := &config.Config{Server: config.ServerConfig{DrainDelay: 50 * time.Millisecond, ShutdownTimeout: 5 * time.Second}}
+	srv := NewServer(cfg, &log.Logger{Logger: zap.NewNop()}, r, state)
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- srv.Serve(ctx, ln)
+	}()
+
+	body := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/slow")
+		if err != nil {
+			body <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		body <- string(b)
+	}()
+	<-started
+	cancel()
+
+	// 停机开始后立即切换为未就绪，处理中的请求正常完成
+	time.Sleep(10 * time.Millisecond)
+	if !state.Draining() {
+		t.Errorf("state should be draining after shutdown starts")
+	}
+	if got := <-body; got != "done" {
+		t.Errorf("in-flight request got %q, want done", got)
+	}
+	if err := <-result; err != nil {
+		t.Fatalf("Serve() = %v", err)
+	}
+	if _, err := net.DialTimeout("tcp", ln.Addr().String(), time.Second); err == nil {
+		t.Errorf("listener should be closed after shutdown")
+	}
+}
+
+func TestStreamWriter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	chunk := make([]byte, 8<<10)
+	download := func(stream bool) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			var w io.Writer = c.Writer
+			if stream {
+				w = StreamWriter(c.Writer, time.Second)
+			}
+			// 总耗时超过服务端的 WriteTimeout
+			for range 5 {
+				time.Sleep(60 * time.Millisecond)
+				if _, err := w.Write(chunk); err != nil {
+					return
+				}
+			}
+		}
+	}
+	r := gin.New()
+	r.GET("/stream", download(true))
+	r.GET("/plain", download(false))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: r, WriteTimeout: 100 * time.Millisecond}
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	size := func(path string) int {
+		resp, err := http.Get("http://" + ln.Addr().String() + path)
+		if err != nil {
+			return 0
+		}
+		defer resp.Body.Close()
+		n, _ := io.Copy(io.Discard, resp.Body)
+		return int(n)
+	}
+	if n := size("/stream"); n != 5*len(chunk) {
+		t.Errorf("stream download got %d bytes, want %d", n, 5*len(chunk))
+	}
+	if n := size("/plain"); n == 5*len(chunk) {
+		t.Error("plain download should be cut off by WriteTimeout")
+	}
+}
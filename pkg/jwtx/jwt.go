@@ -2,13 +2,18 @@ package jwtx
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/metrics"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/redis/go-redis/v9"
@@ -17,21 +22,48 @@ import (
 type Claims struct {
 	UserID   uint64 `json:"user_id"`
 	Username string `json:"username"`
+	// ClientID 签发给 OAuth2 客户端的令牌所属客户端，管理后台自身的令牌为空
+	ClientID string `json:"client_id,omitempty"`
+	// Scope OAuth2 令牌的授权范围，多个以空格分隔
+	Scope string `json:"scope,omitempty"`
+	// ImpersonatorID、ImpersonatorName 模拟登录令牌中发起模拟的管理员，UserID 为被模拟的用户
+	ImpersonatorID   uint64 `json:"impersonator_id,omitempty"`
+	ImpersonatorName string `json:"impersonator_name,omitempty"`
+	// IssuedAtMilli 毫秒精度的签发时间，iat 只精确到秒，与吊销时间比较时使用
+	IssuedAtMilli int64 `json:"iat_ms,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// ErrImpersonationEnded 模拟令牌对应的服务端模拟记录不存在，模拟已结束或已过期
+var ErrImpersonationEnded = errors.New("impersonation session ended")
+
 type JWT struct {
-	config *config.JWTConfig
+	// config 令牌配置，配置重新加载时整体替换
+	config atomic.Pointer[config.JWTConfig]
 	redis  *redis.Client
 	// 添加互斥锁，用于并发控制
 	renewLock sync.Mutex
 }
 
-func New(cfg *config.Config, redis *redis.Client) *JWT {
-	return &JWT{
-		config: &cfg.JWT,
-		redis:  redis,
-	}
+func New(cfg *config.Config, watcher *config.Watcher, redis *redis.Client) *JWT {
+	j := &JWT{redis: redis}
+	c := cfg.JWT
+	j.config.Store(&c)
+	watcher.Subscribe(func(_, next *config.Config) {
+		j.Reload(next.JWT)
+	})
+	return j
+}
+
+// Reload 应用新配置中的令牌有效期，修改密钥或签发者会使已签发的令牌失效，需要重启服务后生效
+func (j *JWT) Reload(c config.JWTConfig) {
+	cur := *j.conf()
+	cur.AccessExpire, cur.RefreshExpire, cur.ImpersonateExpire = c.AccessExpire, c.RefreshExpire, c.ImpersonateExpire
+	j.config.Store(&cur)
+}
+
+func (j *JWT) conf() *config.JWTConfig {
+	return j.config.Load()
 }
 
 // GenerateToken 生成访问令牌（AccessToken）和刷新令牌（RefreshToken）。
@@ -46,34 +78,38 @@ func New(cfg *config.Config, redis *redis.Client) *JWT {
 //   - refreshToken: 生成的刷新令牌，用于获取新的访问令牌。
 //   - err: 可能发生的错误，如果生成令牌失败。
 func (j *JWT) GenerateToken(userID uint64, username string) (accessToken, refreshToken string, err error) {
+	c := j.conf()
+	now := time.Now()
 	// 生成 Access Token
 	accessClaims := Claims{
-		UserID:   userID,
-		Username: username,
+		UserID:        userID,
+		Username:      username,
+		IssuedAtMilli: now.UnixMilli(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.config.AccessExpire)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    j.config.Issuer,
+			ExpiresAt: jwt.NewNumericDate(now.Add(c.AccessExpire)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    c.Issuer,
 		},
 	}
 
-	accessToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims).SignedString([]byte(j.config.AccessSecret))
+	accessToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims).SignedString([]byte(c.AccessSecret))
 	if err != nil {
 		return "", "", err
 	}
 
 	// 生成 Refresh Token
 	refreshClaims := Claims{
-		UserID:   userID,
-		Username: username,
+		UserID:        userID,
+		Username:      username,
+		IssuedAtMilli: now.UnixMilli(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.config.RefreshExpire)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    j.config.Issuer,
+			ExpiresAt: jwt.NewNumericDate(now.Add(c.RefreshExpire)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    c.Issuer,
 		},
 	}
 
-	refreshToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims).SignedString([]byte(j.config.RefreshSecret))
+	refreshToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims).SignedString([]byte(c.RefreshSecret))
 	if err != nil {
 		return "", "", err
 	}
@@ -81,6 +117,105 @@ func (j *JWT) GenerateToken(userID uint64, username string) (accessToken, refres
 	return accessToken, refreshToken, nil
 }
 
+// GenerateOAuthToken 为 OAuth2 客户端签发访问令牌，有效期与访问令牌一致。
+// 客户端凭证模式下没有用户，userID 为 0、sub 为客户端标识。
+func (j *JWT) GenerateOAuthToken(userID uint64, username, clientID, scope string) (string, error) {
+	c := j.conf()
+	subject := clientID
+	if userID != 0 {
+		subject = strconv.FormatUint(userID, 10)
+	}
+	now := time.Now()
+	claims := Claims{
+		UserID:        userID,
+		Username:      username,
+		ClientID:      clientID,
+		Scope:         scope,
+		IssuedAtMilli: now.UnixMilli(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			Audience:  jwt.ClaimStrings{clientID},
+			ExpiresAt: jwt.NewNumericDate(now.Add(c.AccessExpire)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    c.Issuer,
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(c.AccessSecret))
+}
+
+// GenerateImpersonationToken 签发模拟登录访问令牌，令牌以被模拟用户身份访问，同时记录发起模拟的管理员。
+// 模拟令牌不签发刷新令牌，也不会自动续期，过期后需要重新发起模拟。
+// 令牌 ID 对应的模拟记录保存在 Redis 中，退出模拟时必须存在，见 EndImpersonation。
+func (j *JWT) GenerateImpersonationToken(ctx context.Context, impersonatorID uint64, impersonatorName string, userID uint64, username string) (token string, expiresAt time.Time, err error) {
+	c := j.conf()
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, err
+	}
+	id := hex.EncodeToString(b)
+	expire := j.ImpersonateExpire()
+	now := time.Now()
+	expiresAt = now.Add(expire)
+	claims := Claims{
+		UserID:           userID,
+		Username:         username,
+		ImpersonatorID:   impersonatorID,
+		ImpersonatorName: impersonatorName,
+		IssuedAtMilli:    now.UnixMilli(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    c.Issuer,
+		},
+	}
+	token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(c.AccessSecret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if err := j.redis.Set(ctx, j.getImpersonationKey(id), impersonatorID, expire).Err(); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// EndImpersonation 删除模拟令牌对应的服务端模拟记录，记录只能使用一次。
+// 记录不存在或不属于令牌中的管理员时返回 ErrImpersonationEnded
+func (j *JWT) EndImpersonation(ctx context.Context, claims *Claims) error {
+	if claims.ImpersonatorID == 0 || claims.ID == "" {
+		return ErrImpersonationEnded
+	}
+	impersonatorID, err := j.redis.GetDel(ctx, j.getImpersonationKey(claims.ID)).Uint64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return ErrImpersonationEnded
+		}
+		return err
+	}
+	if impersonatorID != claims.ImpersonatorID {
+		return ErrImpersonationEnded
+	}
+	return nil
+}
+
+// ImpersonateExpire 模拟登录令牌有效期，未配置时为 30 分钟且不超过访问令牌有效期
+func (j *JWT) ImpersonateExpire() time.Duration {
+	c := j.conf()
+	expire := c.ImpersonateExpire
+	if expire <= 0 {
+		expire = 30 * time.Minute
+	}
+	if c.AccessExpire > 0 && expire > c.AccessExpire {
+		expire = c.AccessExpire
+	}
+	return expire
+}
+
+// AccessExpire 访问令牌有效期
+func (j *JWT) AccessExpire() time.Duration {
+	return j.conf().AccessExpire
+}
+
 // ParseToken 解析JWT令牌并验证其有效性。
 // 该方法首先检查令牌是否在黑名单中（对于非刷新令牌而言），
 // 然后使用相应的密钥解析令牌，最后验证令牌的有效性。
@@ -95,6 +230,7 @@ func (j *JWT) GenerateToken(userID uint64, username string) (accessToken, refres
 //		error: 如果解析过程中发生错误或令牌无效，返回一个错误。
 //	  error: 如果解析过程中发生错误或令牌无效，返回一个错误。
 func (j *JWT) ParseToken(ctx context.Context, tokenString string, isRefreshToken bool) (*Claims, error) {
+	c := j.conf()
 	// 检查是否在黑名单中
 	if !isRefreshToken {
 		inBlacklist, err := j.IsInBlacklist(ctx, tokenString)
@@ -107,9 +243,9 @@ func (j *JWT) ParseToken(ctx context.Context, tokenString string, isRefreshToken
 	}
 
 	// 根据令牌类型选择相应的密钥
-	secret := j.config.AccessSecret
+	secret := c.AccessSecret
 	if isRefreshToken {
-		secret = j.config.RefreshSecret
+		secret = c.RefreshSecret
 	}
 
 	// 使用选择的密钥解析令牌
@@ -122,6 +258,21 @@ func (j *JWT) ParseToken(ctx context.Context, tokenString string, isRefreshToken
 
 	// 验证令牌的有效性
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		// 检查令牌是否已被吊销（如修改密码后其他会话的令牌）
+		revoked, err := j.isRevoked(ctx, claims.UserID, claims)
+		if err != nil {
+			return nil, err
+		}
+		// 模拟令牌同时受发起模拟的管理员的吊销影响
+		if !revoked && claims.ImpersonatorID != 0 {
+			revoked, err = j.isRevoked(ctx, claims.ImpersonatorID, claims)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if revoked {
+			return nil, errors.New("token has been revoked")
+		}
 		return claims, nil
 	}
 
@@ -158,7 +309,48 @@ func (j *JWT) getBlacklistKey(tokenStr string) string {
 
 // 生成续期记录的 key
 func (j *JWT) getRenewalKey(userID uint64) string {
-	return "token:renewal:" + string(userID)
+	return "token:renewal:" + strconv.FormatUint(userID, 10)
+}
+
+// 生成模拟记录的 key
+func (j *JWT) getImpersonationKey(id string) string {
+	return "token:impersonation:" + id
+}
+
+// 生成令牌吊销记录的 key
+func (j *JWT) getRevokedKey(userID uint64) string {
+	return "token:revoked:" + strconv.FormatUint(userID, 10)
+}
+
+// RevokeUserTokens 吊销指定用户在当前时间之前签发的所有令牌（包括访问令牌和刷新令牌）。
+// 吊销记录为毫秒时间戳，保存在 Redis 中，有效期与刷新令牌一致，过期后旧令牌本身也已失效。
+func (j *JWT) RevokeUserTokens(ctx context.Context, userID uint64) error {
+	return j.redis.Set(ctx, j.getRevokedKey(userID), time.Now().UnixMilli(), j.conf().RefreshExpire).Err()
+}
+
+// isRevoked 检查令牌是否签发于用户最近一次吊销操作之前，按毫秒比较，
+// 避免与吊销操作同一秒内为其他会话签发的令牌逃过吊销
+func (j *JWT) isRevoked(ctx context.Context, userID uint64, claims *Claims) (bool, error) {
+	revokedAt, err := j.redis.Get(ctx, j.getRevokedKey(userID)).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, nil
+		}
+		return false, err
+	}
+	// 旧版本以秒保存吊销时间
+	if revokedAt < 1e11 {
+		revokedAt *= 1000
+	}
+	issuedAt := claims.IssuedAtMilli
+	// 旧版本签发的令牌没有 iat_ms，按所在秒的起点计算
+	if issuedAt == 0 && claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.UnixMilli()
+	}
+	if issuedAt == 0 {
+		return true, nil
+	}
+	return issuedAt < revokedAt, nil
 }
 
 // AddToBlacklist 将指定的令牌添加到黑名单中。
@@ -225,6 +417,11 @@ func (j *JWT) IsInBlacklist(ctx context.Context, tokenStr string) (bool, error)
 //		err - 错误对象，如果在检查或续发令牌过程中遇到错误，则返回该错误。
 //	  err - 错误对象，如果在检查或续发令牌过程中遇到错误，则返回该错误。
 func (j *JWT) CheckAndRenewToken(ctx context.Context, tokenStr string, claims *Claims) (newAccessToken string, needRenew bool, err error) {
+	// 模拟令牌不续期，避免续期后丢失模拟标记
+	if claims.ImpersonatorID != 0 {
+		return "", false, nil
+	}
+
 	// 使用互斥锁确保并发安全
 	j.renewLock.Lock()
 	defer j.renewLock.Unlock()
@@ -233,7 +430,7 @@ func (j *JWT) CheckAndRenewToken(ctx context.Context, tokenStr string, claims *C
 	remainingTime := time.Until(claims.ExpiresAt.Time)
 
 	// 如果令牌剩余有效时间小于访问令牌总有效期的四分之一，则考虑续发新令牌
-	if remainingTime < j.config.AccessExpire/4 {
+	if remainingTime < j.conf().AccessExpire/4 {
 		// 获取续发令牌的键
 		renewalKey := j.getRenewalKey(claims.UserID)
 		// 检查当前令牌是否已经有过续发记录
@@ -266,6 +463,7 @@ func (j *JWT) CheckAndRenewToken(ctx context.Context, tokenStr string, claims *C
 
 			// 如果续发成功，则返回新的访问令牌和续发状态
 			if success == 1 {
+				metrics.TokenRenewals.Inc()
 				return newAccessToken, true, nil
 			}
 		}
@@ -286,21 +484,24 @@ func (j *JWT) CheckAndRenewToken(ctx context.Context, tokenStr string, claims *C
 //		生成的JWT令牌字符串和可能发生的错误。
 //	  生成的JWT令牌字符串和可能发生的错误。
 func (j *JWT) generateAccessToken(userID uint64, username string) (string, error) {
+	c := j.conf()
+	now := time.Now()
 	// 创建Claims结构体，包含用户ID、用户名和令牌的注册声明。
 	claims := Claims{
-		UserID:   userID,
-		Username: username,
+		UserID:        userID,
+		Username:      username,
+		IssuedAtMilli: now.UnixMilli(),
 		RegisteredClaims: jwt.RegisteredClaims{
 			// 设置令牌过期时间为当前时间加上配置的访问令牌过期时长。
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.config.AccessExpire)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(c.AccessExpire)),
 			// 设置令牌签发时间为当前时间。
-			IssuedAt: jwt.NewNumericDate(time.Now()),
+			IssuedAt: jwt.NewNumericDate(now),
 			// 设置令牌的签发者为配置中的Issuer。
-			Issuer: j.config.Issuer,
+			Issuer: c.Issuer,
 		},
 	}
 
 	// 使用HS256算法创建并签发JWT令牌，并返回签名后的令牌字符串。
 	// 如果签发过程中出现错误，也会返回相应的错误。
-	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(j.config.AccessSecret))
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(c.AccessSecret))
 }
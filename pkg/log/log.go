@@ -1,11 +1,13 @@
 package log
 
 import (
+	"context"
 	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -15,6 +17,8 @@ const LoggerKey = "zapLogger"
 
 type Logger struct {
 	*zap.Logger
+	// level 日志级别，可在运行时通过 SetLevel 修改
+	level zap.AtomicLevel
 }
 
 func NewLog(conf *config.LogConfig) *Logger {
@@ -25,21 +29,7 @@ func initZap(conf *config.LogConfig) *Logger {
 	// 日志地址 "out.log" 自定义
 	lp := conf.LogFileName
 	// 日志级别 DEBUG,ERROR, INFO
-	lv := conf.LogLevel
-	var level zapcore.Level
-	// debug<info<warn<error<fatal<panic
-	switch lv {
-	case "debug":
-		level = zap.DebugLevel
-	case "info":
-		level = zap.InfoLevel
-	case "warn":
-		level = zap.WarnLevel
-	case "error":
-		level = zap.ErrorLevel
-	default:
-		level = zap.InfoLevel
-	}
+	level := zap.NewAtomicLevelAt(parseLevel(conf.LogLevel))
 	hook := lumberjack.Logger{
 		Filename:   lp,              // 日志文件路径
 		MaxSize:    conf.MaxSize,    // 每个日志文件保存的最大尺寸 单位：M
@@ -85,9 +75,32 @@ func initZap(conf *config.LogConfig) *Logger {
 		level, // 日志级别
 	)
 	if conf.LogLevel == "debug" {
-		return &Logger{zap.New(core, zap.Development(), zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel))}
+		return &Logger{Logger: zap.New(core, zap.Development(), zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel)), level: level}
 	}
-	return &Logger{zap.New(core, zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel))}
+	return &Logger{Logger: zap.New(core, zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel)), level: level}
+}
+
+// parseLevel 解析日志级别 debug<info<warn<error，未知级别按 info 处理
+func parseLevel(lv string) zapcore.Level {
+	switch lv {
+	case "debug":
+		return zap.DebugLevel
+	case "warn":
+		return zap.WarnLevel
+	case "error":
+		return zap.ErrorLevel
+	default:
+		return zap.InfoLevel
+	}
+}
+
+// SetLevel 修改日志级别，对由该 Logger 派生的所有 Logger 生效
+func (l *Logger) SetLevel(lv string) {
+	// 未通过 NewLog 创建的 Logger（如测试中使用的 Nop）没有可修改的级别
+	if l.level == (zap.AtomicLevel{}) {
+		return
+	}
+	l.level.SetLevel(parseLevel(lv))
 }
 
 // 自定义时间编码器
@@ -98,18 +111,39 @@ func timeEncoder(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
 
 // NewContext 给指定的context添加字段
 func (l *Logger) NewContext(ctx *gin.Context, fields ...zapcore.Field) {
-	ctx.Set(LoggerKey, l.WithContext(ctx).With(fields...))
+	ctx.Set(LoggerKey, l.fromContext(ctx).With(fields...))
 }
 
-// WithContext 从指定的context返回一个zap实例
-func (l *Logger) WithContext(ctx *gin.Context) *Logger {
+// WithContext 从指定的context返回一个zap实例，ctx 可以是 *gin.Context 或由其派生的上下文，
+// 请求处于链路追踪中时附带 trace_id 和 span_id
+func (l *Logger) WithContext(ctx context.Context) *Logger {
 	if ctx == nil {
 		return l
 	}
-	zl, _ := ctx.Get(LoggerKey)
-	ctxLogger, ok := zl.(*zap.Logger)
-	if ok {
-		return &Logger{ctxLogger}
+	zl := l.fromContext(ctx)
+	spanCtx := ctx
+	if c, ok := ctx.(*gin.Context); ok {
+		spanCtx = nil
+		if c.Request != nil {
+			spanCtx = c.Request.Context()
+		}
+	}
+	if spanCtx != nil {
+		if sc := trace.SpanContextFromContext(spanCtx); sc.IsValid() {
+			zl = zl.With(zap.String("trace_id", sc.TraceID().String()), zap.String("span_id", sc.SpanID().String()))
+		}
+	}
+	if zl == l.Logger {
+		return l
+	}
+	return &Logger{Logger: zl, level: l.level}
+}
+
+// fromContext 返回 NewContext 存入的 zap 实例，没有时返回 l 本身。
+// *gin.Context 的 Value 按字符串键读取上下文中的值，派生的上下文同样可以取到
+func (l *Logger) fromContext(ctx context.Context) *zap.Logger {
+	if ctxLogger, ok := ctx.Value(LoggerKey).(*zap.Logger); ok {
+		return ctxLogger
 	}
-	return l
+	return l.Logger
 }
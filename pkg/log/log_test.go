@@ -0,0 +1,48 @@
+package log
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithContextTraceID(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	l := &Logger{Logger: zap.New(core)}
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/", nil)
+	l.WithContext(c).Info("without span")
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa},
+	})
+	c.Request = c.Request.WithContext(trace.ContextWithSpanContext(c.Request.Context(), sc))
+	l.NewContext(c, zap.String("user", "admin"))
+	l.WithContext(c).Info("with span")
+	// 服务层由 *gin.Context 派生的上下文同样取到请求的日志实例
+	l.WithContext(context.WithValue(c, testKey{}, 1)).Info("derived")
+
+	entries := logs.All()
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries", len(entries))
+	}
+	if _, ok := entries[0].ContextMap()["trace_id"]; ok {
+		t.Errorf("log without span should not have trace_id")
+	}
+	fields := entries[1].ContextMap()
+	if fields["trace_id"] != sc.TraceID().String() || fields["span_id"] != sc.SpanID().String() || fields["user"] != "admin" {
+		t.Errorf("fields = %v", fields)
+	}
+	if entries[2].ContextMap()["user"] != "admin" {
+		t.Errorf("derived context fields = %v", entries[2].ContextMap())
+	}
+}
+
+type testKey struct{}
@@ -0,0 +1,106 @@
+package mailx
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"go.uber.org/zap"
+)
+
+// Message 邮件内容
+type Message struct {
+	To      []string
+	Subject string
+	// Body 邮件正文，HTML 为 true 时按 text/html 发送
+	Body string
+	HTML bool
+}
+
+// Mailer 邮件发送接口，便于替换为不同的发送实现
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// ErrNotConfigured 未配置 SMTP 服务器时 release 模式下发送邮件返回的错误
+var ErrNotConfigured = errors.New("mail server is not configured")
+
+// New 根据配置创建邮件发送器。未配置 SMTP 服务器时，开发模式下使用日志发送器；
+// release 模式下邮件正文可能包含重置令牌等凭据，不能写入日志，发送时返回 ErrNotConfigured
+func New(cfg *config.Config, logger *log.Logger) Mailer {
+	if cfg.Mail.Host == "" {
+		if cfg.Server.Mode == "release" {
+			return disabledMailer{}
+		}
+		return NewLogMailer(logger)
+	}
+	return NewSMTPMailer(&cfg.Mail)
+}
+
+type disabledMailer struct{}
+
+func (disabledMailer) Send(context.Context, *Message) error {
+	return ErrNotConfigured
+}
+
+type logMailer struct {
+	logger *log.Logger
+}
+
+// NewLogMailer 创建仅将邮件输出到日志的发送器，用于本地开发和测试环境。
+// 正文只在 Debug 级别输出，避免重置链接等凭据出现在常规日志中
+func NewLogMailer(logger *log.Logger) Mailer {
+	return &logMailer{logger: logger}
+}
+
+func (m *logMailer) Send(ctx context.Context, msg *Message) error {
+	logger := m.logger.WithContext(ctx)
+	logger.Info("邮件未配置SMTP服务器，仅输出到日志",
+		zap.Strings("to", msg.To),
+		zap.String("subject", msg.Subject),
+	)
+	logger.Debug("邮件正文", zap.String("body", msg.Body))
+	return nil
+}
+
+// buildMessage 按 RFC 5322 构造邮件原文，主题和正文使用 UTF-8 编码
+func buildMessage(from string, msg *Message) []byte {
+	contentType := "text/plain"
+	if msg.HTML {
+		contentType = "text/html"
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: %s; charset=UTF-8\r\n", contentType)
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(msg.Body))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+	return buf.Bytes()
+}
+
+// envelopeAddress 从 "名称 <地址>" 形式中提取邮箱地址
+func envelopeAddress(addr string) (string, error) {
+	parsed, err := mail.ParseAddress(addr)
+	if err != nil {
+		return "", fmt.Errorf("invalid mail address %q: %w", addr, err)
+	}
+	return parsed.Address, nil
+}
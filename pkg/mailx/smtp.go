@@ -0,0 +1,105 @@
+package mailx
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+)
+
+// defaultSendTimeout 上下文没有截止时间时单封邮件的最长发送时间，避免 SMTP 服务器无响应时一直阻塞
+const defaultSendTimeout = 30 * time.Second
+
+type smtpMailer struct {
+	config  *config.MailConfig
+	timeout time.Duration
+}
+
+// NewSMTPMailer 创建基于 SMTP 协议的邮件发送器
+func NewSMTPMailer(cfg *config.MailConfig) Mailer {
+	return &smtpMailer{config: cfg, timeout: defaultSendTimeout}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg *Message) error {
+	if len(msg.To) == 0 {
+		return errors.New("mail recipient is empty")
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+	}
+	from, err := envelopeAddress(m.config.From)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("dial smtp server failed: %w", err)
+	}
+	// 使用上下文截止时间限制整个会话的耗时
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+
+	tlsConfig := &tls.Config{ServerName: m.config.Host}
+	if m.config.SSL {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("create smtp client failed: %w", err)
+	}
+	defer client.Close()
+
+	if !m.config.SSL {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("smtp starttls failed: %w", err)
+			}
+		}
+	}
+
+	if m.config.Username != "" {
+		auth := smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	for _, to := range msg.To {
+		rcpt, err := envelopeAddress(to)
+		if err != nil {
+			return err
+		}
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("smtp RCPT TO %s failed: %w", rcpt, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(buildMessage(m.config.From, msg)); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("write mail body failed: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp send failed: %w", err)
+	}
+	return client.Quit()
+}
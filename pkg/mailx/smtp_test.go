@@ -0,0 +1,206 @@
+package mailx
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"go.uber.org/zap"
+)
+
+// fakeSMTPServer 一个只实现最小命令集的本地 SMTP 服务器，记录收到的信封和邮件原文
+type fakeSMTPServer struct {
+	listener net.Listener
+	from     string
+	rcpts    []string
+	data     string
+	done     chan struct{}
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeSMTPServer{listener: l, done: make(chan struct{})}
+	go s.serve()
+	t.Cleanup(func() { _ = l.Close() })
+	return s
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	reply("220 localhost ESMTP fake")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			s.from = parsePath(line[len("MAIL FROM:"):])
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.rcpts = append(s.rcpts, parsePath(line[len("RCPT TO:"):]))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var sb strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				sb.WriteString(l)
+			}
+			s.data = sb.String()
+			reply("250 OK queued")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// parsePath 从 "<addr> PARAM=..." 中提取地址
+func parsePath(arg string) string {
+	arg = strings.TrimSpace(arg)
+	if i := strings.IndexByte(arg, '>'); i >= 0 {
+		arg = arg[:i]
+	}
+	return strings.TrimPrefix(arg, "<")
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	mailer := NewSMTPMailer(&config.MailConfig{
+		Host: "127.0.0.1",
+		Port: server.port(),
+		From: "Admin <noreply@example.com>",
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := mailer.Send(ctx, &Message{
+		To:      []string{"alice@example.com"},
+		Subject: "重置密码",
+		Body:    "reset link: http://localhost/reset?token=abc",
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	<-server.done
+
+	if server.from != "noreply@example.com" {
+		t.Errorf("MAIL FROM = %q", server.from)
+	}
+	if len(server.rcpts) != 1 || server.rcpts[0] != "alice@example.com" {
+		t.Errorf("RCPT TO = %v", server.rcpts)
+	}
+	header, body, ok := strings.Cut(server.data, "\r\n\r\n")
+	if !ok {
+		t.Fatalf("mail data has no header separator: %q", server.data)
+	}
+	if !strings.Contains(header, "Subject: =?UTF-8?b?") {
+		t.Errorf("subject is not encoded: %q", header)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(body, "\r\n", ""))
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if !strings.Contains(string(decoded), "token=abc") {
+		t.Errorf("body = %q", decoded)
+	}
+}
+
+func TestSMTPMailer_SendDialError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	_ = l.Close()
+
+	mailer := NewSMTPMailer(&config.MailConfig{Host: "127.0.0.1", Port: port, From: "noreply@example.com"})
+	err = mailer.Send(context.Background(), &Message{To: []string{"alice@example.com"}, Subject: "x", Body: "y"})
+	if err == nil || !strings.Contains(err.Error(), "dial") {
+		t.Errorf("Send() error = %v, want dial error", err)
+	}
+}
+
+func TestSMTPMailer_SendDefaultTimeout(t *testing.T) {
+	// 接受连接后不发送问候语，模拟无响应的服务器
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if conn, err := l.Accept(); err == nil {
+			accepted <- conn
+		}
+	}()
+	defer func() {
+		select {
+		case conn := <-accepted:
+			_ = conn.Close()
+		default:
+		}
+	}()
+
+	mailer := &smtpMailer{
+		config:  &config.MailConfig{Host: "127.0.0.1", Port: l.Addr().(*net.TCPAddr).Port, From: "noreply@example.com"},
+		timeout: 100 * time.Millisecond,
+	}
+	start := time.Now()
+	err = mailer.Send(context.Background(), &Message{To: []string{"alice@example.com"}, Subject: "x", Body: "y"})
+	if err == nil {
+		t.Fatal("Send() should time out")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Send() took %v, want about the default timeout", elapsed)
+	}
+}
+
+func TestNew(t *testing.T) {
+	logger := &log.Logger{Logger: zap.NewNop()}
+	dev := New(&config.Config{}, logger)
+	if err := dev.Send(context.Background(), &Message{To: []string{"alice@example.com"}}); err != nil {
+		t.Errorf("log mailer Send() error = %v", err)
+	}
+	release := New(&config.Config{Server: config.ServerConfig{Mode: "release"}}, logger)
+	if err := release.Send(context.Background(), &Message{To: []string{"alice@example.com"}}); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("release Send() error = %v, want ErrNotConfigured", err)
+	}
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "admin"
+
+// Registry 应用指标的注册表，除下列指标外还包含 Go 运行时和进程指标
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	// HTTPRequests 按路由模板、状态码和权限标识统计的请求数
+	HTTPRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP 请求数",
+	}, []string{"method", "route", "status", "permission"})
+
+	// HTTPRequestDuration 按路由模板、状态码和权限标识统计的请求耗时
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP 请求耗时",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status", "permission"})
+
+	// DBQueryDuration 按操作类型和数据表统计的 SQL 执行耗时
+	DBQueryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "数据库 SQL 执行耗时",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table", "status"})
+
+	// CasbinDecisions 权限校验结果，result 为 allow 或 deny
+	CasbinDecisions = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "casbin",
+		Name:      "decisions_total",
+		Help:      "Casbin 权限校验次数",
+	}, []string{"result", "permission"})
+
+	// Logins 登录结果，result 为 success 或 failure
+	Logins = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "logins_total",
+		Help:      "登录次数",
+	}, []string{"method", "result"})
+
+	// TokenRenewals 访问令牌临近过期时自动续发的次数
+	TokenRenewals = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "token_renewals_total",
+		Help:      "访问令牌自动续发次数",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler 以 Prometheus 文本格式输出 Registry 中的指标
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// RecordLogin 记录一次登录结果，method 为登录方式，如 password、oidc
+func RecordLogin(method string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	Logins.WithLabelValues(method, result).Inc()
+}
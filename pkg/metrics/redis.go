@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	redisHits = prometheus.NewDesc(prometheus.BuildFQName(namespace, "redis_pool", "hits_total"),
+		"连接池中取到空闲连接的次数", nil, nil)
+	redisMisses = prometheus.NewDesc(prometheus.BuildFQName(namespace, "redis_pool", "misses_total"),
+		"连接池中没有空闲连接的次数", nil, nil)
+	redisTimeouts = prometheus.NewDesc(prometheus.BuildFQName(namespace, "redis_pool", "timeouts_total"),
+		"等待连接池连接超时的次数", nil, nil)
+	redisTotalConns = prometheus.NewDesc(prometheus.BuildFQName(namespace, "redis_pool", "connections"),
+		"连接池中的连接数", nil, nil)
+	redisIdleConns = prometheus.NewDesc(prometheus.BuildFQName(namespace, "redis_pool", "idle_connections"),
+		"连接池中的空闲连接数", nil, nil)
+	redisStaleConns = prometheus.NewDesc(prometheus.BuildFQName(namespace, "redis_pool", "stale_connections_total"),
+		"从连接池中移除的失效连接数", nil, nil)
+)
+
+// RedisCollector 采集时读取 Redis 客户端的连接池统计
+type RedisCollector struct {
+	client *redis.Client
+}
+
+func NewRedisCollector(client *redis.Client) *RedisCollector {
+	return &RedisCollector{client: client}
+}
+
+func (c *RedisCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- redisHits
+	ch <- redisMisses
+	ch <- redisTimeouts
+	ch <- redisTotalConns
+	ch <- redisIdleConns
+	ch <- redisStaleConns
+}
+
+func (c *RedisCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.PoolStats()
+	ch <- prometheus.MustNewConstMetric(redisHits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(redisMisses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(redisTimeouts, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(redisTotalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(redisIdleConns, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(redisStaleConns, prometheus.CounterValue, float64(stats.StaleConns))
+}
@@ -0,0 +1,404 @@
+// Package migrate 按版本号执行数据库结构迁移，迁移文件按数据库方言分目录存放，执行记录保存在迁移历史表中
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultTable 迁移历史表名
+const DefaultTable = "schema_migrations"
+
+// fileNamePattern 迁移文件名，如 000001_init_schema.up.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration 一个版本的迁移
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Record 迁移历史表中的一条记录
+type Record struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// Status 迁移的执行状态
+type Status struct {
+	*Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Load 读取 fsys 中 dialect 目录下的迁移文件，按版本号升序返回，每个版本必须同时有 up 和 down 文件
+func Load(fsys fs.FS, dialect string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, dialect)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations for %s: %w", dialect, err)
+	}
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		if version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dialect, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has different names %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", m.Version, m.Name)
+		}
+		migrations = append(migrations, m)
+	}
+	slices.SortFunc(migrations, func(a, b *Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return migrations, nil
+}
+
+// Migrator 迁移执行器
+type Migrator struct {
+	db         *gorm.DB
+	table      string
+	migrations []*Migration
+}
+
+// New 按 db 的方言从 fsys 加载迁移文件
+func New(db *gorm.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys, db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, table: DefaultTable, migrations: migrations}, nil
+}
+
+// Migrations 已加载的迁移，按版本号升序
+func (m *Migrator) Migrations() []*Migration {
+	return m.migrations
+}
+
+// Latest 最新的迁移版本，没有迁移时为 0
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version 当前已执行的最高版本，未执行过任何迁移时为 0
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	applied, err := m.applied(m.db.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	var version int64
+	for v := range applied {
+		version = max(version, v)
+	}
+	return version, nil
+}
+
+// Status 所有迁移的执行状态
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	applied, err := m.applied(m.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := &Status{Migration: mig}
+		if r, ok := applied[mig.Version]; ok {
+			s.Applied, s.AppliedAt = true, r.AppliedAt
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+// Up 执行所有未执行的迁移，返回本次执行的迁移
+func (m *Migrator) Up(ctx context.Context) ([]*Migration, error) {
+	return m.To(ctx, m.Latest())
+}
+
+// Down 按版本号从高到低回滚 steps 个已执行的迁移
+func (m *Migrator) Down(ctx context.Context, steps int) (done []*Migration, err error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("steps must be positive")
+	}
+	err = m.withLock(ctx, func(db *gorm.DB) error {
+		applied, err := m.applied(db)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if err := m.run(db, mig, false); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// To 迁移到指定版本：执行不高于该版本的未执行迁移，回滚高于该版本的已执行迁移，version 为 0 时回滚全部
+func (m *Migrator) To(ctx context.Context, version int64) (done []*Migration, err error) {
+	if version != 0 && !slices.ContainsFunc(m.migrations, func(mig *Migration) bool { return mig.Version == version }) {
+		return nil, fmt.Errorf("migration version %d not found", version)
+	}
+	err = m.withLock(ctx, func(db *gorm.DB) error {
+		applied, err := m.applied(db)
+		if err != nil {
+			return err
+		}
+		for v := range applied {
+			if v > version && !slices.ContainsFunc(m.migrations, func(mig *Migration) bool { return mig.Version == v }) {
+				return fmt.Errorf("applied migration %d has no migration file, cannot roll back", v)
+			}
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok || mig.Version <= version {
+				continue
+			}
+			if err := m.run(db, mig, false); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok || mig.Version > version {
+				continue
+			}
+			if err := m.run(db, mig, true); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Force 只修改迁移历史而不执行迁移：不高于 version 的迁移记为已执行，高于 version 的记录被删除，
+// version 为 0 时清空历史。用于为已有表结构的数据库建立基线，或在迁移中途失败并手动修复后修正记录
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != 0 && !slices.ContainsFunc(m.migrations, func(mig *Migration) bool { return mig.Version == version }) {
+		return fmt.Errorf("migration version %d not found", version)
+	}
+	return m.withLock(ctx, func(db *gorm.DB) error {
+		applied, err := m.applied(db)
+		if err != nil {
+			return err
+		}
+		return db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Table(m.table).Where("version > ?", version).Delete(&Record{}).Error; err != nil {
+				return err
+			}
+			for _, mig := range m.migrations {
+				if _, ok := applied[mig.Version]; ok || mig.Version > version {
+					continue
+				}
+				if err := tx.Table(m.table).Create(&Record{Version: mig.Version, Name: mig.Name, AppliedAt: time.Now()}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// withLock 持有迁移锁执行 fn，避免多个实例同时执行迁移，fn 中的数据库操作必须使用传入的 db。
+// MySQL 使用 GET_LOCK、PostgreSQL 使用 pg_advisory_lock，均为会话级锁，因此 fn 固定在加锁的连接上执行；
+// SQLite 没有会话级锁，改为在一个写事务中执行 fn，其他进程的写入在事务结束前等待
+func (m *Migrator) withLock(ctx context.Context, fn func(db *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		db := conn.Session(&gorm.Session{NewDB: true})
+		// 释放锁不受 ctx 取消影响，否则持有锁的连接会被放回连接池
+		release := db.WithContext(context.WithoutCancel(ctx))
+		switch m.db.Dialector.Name() {
+		case "mysql":
+			// 锁名在整个 MySQL 实例内可见，加上库名区分同一实例上的不同数据库
+			var acquired int64
+			if err := db.Raw("SELECT GET_LOCK(CONCAT(DATABASE(), '.', ?), -1)", m.table).Scan(&acquired).Error; err != nil {
+				return fmt.Errorf("acquire migration lock: %w", err)
+			}
+			if acquired != 1 {
+				return fmt.Errorf("acquire migration lock: GET_LOCK returned %d", acquired)
+			}
+			defer release.Exec("SELECT RELEASE_LOCK(CONCAT(DATABASE(), '.', ?))", m.table)
+			return fn(db)
+		case "postgres":
+			if err := db.Exec("SELECT pg_advisory_lock(hashtext(?))", m.table).Error; err != nil {
+				return fmt.Errorf("acquire migration lock: %w", err)
+			}
+			defer release.Exec("SELECT pg_advisory_unlock(hashtext(?))", m.table)
+			return fn(db)
+		case "sqlite":
+			if err := db.Table(m.table).AutoMigrate(&Record{}); err != nil {
+				return err
+			}
+			var fnErr error
+			err := db.Transaction(func(tx *gorm.DB) error {
+				// 延迟事务在第一条写语句时才取得写锁，先写历史表加锁再读取迁移记录
+				if err := tx.Table(m.table).Where("1 = 0").Delete(&Record{}).Error; err != nil {
+					return fmt.Errorf("acquire migration lock: %w", err)
+				}
+				// 失败的迁移已回滚到各自的保存点，之前完成的迁移照常提交
+				fnErr = fn(tx.Session(&gorm.Session{NewDB: true}))
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			return fnErr
+		default:
+			return fn(db)
+		}
+	})
+}
+
+// run 在事务中执行一个迁移并更新历史表。MySQL 的 DDL 会隐式提交，失败时需要根据错误手动修复
+func (m *Migrator) run(db *gorm.DB, mig *Migration, up bool) error {
+	script := mig.Down
+	if up {
+		script = mig.Up
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range SplitStatements(script) {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		if up {
+			return tx.Table(m.table).Create(&Record{Version: mig.Version, Name: mig.Name, AppliedAt: time.Now()}).Error
+		}
+		return tx.Table(m.table).Where("version = ?", mig.Version).Delete(&Record{}).Error
+	})
+	if err != nil {
+		direction := "down"
+		if up {
+			direction = "up"
+		}
+		return fmt.Errorf("migration %d_%s %s: %w", mig.Version, mig.Name, direction, err)
+	}
+	return nil
+}
+
+func (m *Migrator) applied(db *gorm.DB) (map[int64]Record, error) {
+	if err := db.Table(m.table).AutoMigrate(&Record{}); err != nil {
+		return nil, err
+	}
+	var records []Record
+	if err := db.Table(m.table).Find(&records).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]Record, len(records))
+	for _, r := range records {
+		applied[r.Version] = r
+	}
+	return applied, nil
+}
+
+// SplitStatements 按分号拆分 SQL 脚本，忽略 -- 注释和空语句，引号内的分号不作为分隔符
+func SplitStatements(script string) []string {
+	var (
+		stmts   []string
+		current []rune
+		quote   rune
+	)
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			current = append(current, r)
+			if r == quote {
+				// 连续两个引号是转义
+				if i+1 < len(runes) && runes[i+1] == quote {
+					current = append(current, runes[i+1])
+					i++
+				} else {
+					quote = 0
+				}
+			} else if r == '\\' && i+1 < len(runes) {
+				current = append(current, runes[i+1])
+				i++
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+			current = append(current, r)
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			current = append(current, '\n')
+		case r == ';':
+			stmts = appendStatement(stmts, current)
+			current = current[:0]
+		default:
+			current = append(current, r)
+		}
+	}
+	return appendStatement(stmts, current)
+}
+
+func appendStatement(stmts []string, current []rune) []string {
+	start, end := 0, len(current)
+	for start < end && isSpace(current[start]) {
+		start++
+	}
+	for end > start && isSpace(current[end-1]) {
+		end--
+	}
+	if start == end {
+		return stmts
+	}
+	return append(stmts, string(current[start:end]))
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
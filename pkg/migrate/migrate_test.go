@@ -0,0 +1,192 @@
+package migrate
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- 注释; 不拆分
+CREATE TABLE a (name varchar(10) DEFAULT 'x;y');
+INSERT INTO a VALUES ('it''s; ok');;
+
+DROP TABLE b`
+	want := []string{
+		"CREATE TABLE a (name varchar(10) DEFAULT 'x;y')",
+		"INSERT INTO a VALUES ('it''s; ok')",
+		"DROP TABLE b",
+	}
+	if got := SplitStatements(script); !slices.Equal(got, want) {
+		t.Errorf("SplitStatements() = %q, want %q", got, want)
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sqlite/000001_a.up.sql":   {Data: []byte("CREATE TABLE a (id integer);")},
+		"sqlite/000001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"sqlite/000002_b.up.sql":   {Data: []byte("CREATE TABLE b (id integer); CREATE INDEX idx_b ON b (id);")},
+		"sqlite/000002_b.down.sql": {Data: []byte("DROP TABLE b;")},
+		"sqlite/000003_c.up.sql":   {Data: []byte("ALTER TABLE a ADD COLUMN name text;")},
+		"sqlite/000003_c.down.sql": {Data: []byte("ALTER TABLE a DROP COLUMN name;")},
+	}
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := New(db, fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	version := func() int64 {
+		t.Helper()
+		v, err := m.Version(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	if done, err := m.To(ctx, 2); err != nil || len(done) != 2 || version() != 2 {
+		t.Fatalf("To(2) = %d migrations, %v, version %d", len(done), err, version())
+	}
+	if done, err := m.Up(ctx); err != nil || len(done) != 1 || version() != 3 {
+		t.Fatalf("Up() = %d migrations, %v, version %d", len(done), err, version())
+	}
+	if !db.Migrator().HasColumn("a", "name") {
+		t.Error("column a.name not created")
+	}
+	if done, err := m.Down(ctx, 2); err != nil || len(done) != 2 || done[0].Version != 3 || version() != 1 {
+		t.Fatalf("Down(2) = %v, %v, version %d", done, err, version())
+	}
+	if db.Migrator().HasTable("b") {
+		t.Error("table b not dropped")
+	}
+
+	status, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) != 3 || !status[0].Applied || status[1].Applied || status[2].Applied {
+		t.Errorf("status = %+v", status)
+	}
+	if _, err := m.To(ctx, 9); err == nil {
+		t.Error("To(9) should fail for unknown version")
+	}
+	if _, err := m.To(ctx, 0); err != nil || version() != 0 {
+		t.Errorf("To(0) = %v, version %d", err, version())
+	}
+}
+
+func TestMigratorForce(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sqlite/000001_a.up.sql":   {Data: []byte("CREATE TABLE a (id integer);")},
+		"sqlite/000001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"sqlite/000002_b.up.sql":   {Data: []byte("CREATE TABLE b (id integer);")},
+		"sqlite/000002_b.down.sql": {Data: []byte("DROP TABLE b;")},
+	}
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := New(db, fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// 已有表结构但没有迁移历史时，up 不能跳过已存在的表
+	if err := db.Exec("CREATE TABLE a (id integer)").Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(ctx); err == nil {
+		t.Fatal("Up() on existing schema should fail")
+	}
+	if err := m.Force(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if done, err := m.Up(ctx); err != nil || len(done) != 1 || done[0].Version != 2 {
+		t.Fatalf("Up() after Force(1) = %v, %v", done, err)
+	}
+
+	// 回退记录不删除表
+	if err := m.Force(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := m.Version(ctx); err != nil || v != 1 {
+		t.Errorf("Version() = %d, %v, want 1", v, err)
+	}
+	if !db.Migrator().HasTable("b") {
+		t.Error("Force() should not drop tables")
+	}
+	if err := m.Force(ctx, 9); err == nil {
+		t.Error("Force(9) should fail for unknown version")
+	}
+}
+
+func TestMigratorLock(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sqlite/000001_a.up.sql":   {Data: []byte("CREATE TABLE a (id integer);")},
+		"sqlite/000001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+	}
+	// 两个实例连接同一个数据库文件
+	dsn := filepath.Join(t.TempDir(), "lock.db") + "?_pragma=busy_timeout(5000)"
+	open := func() *Migrator {
+		t.Helper()
+		db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := New(db, fsys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	first, second := open(), open()
+	ctx := context.Background()
+
+	type result struct {
+		done []*Migration
+		err  error
+	}
+	ch := make(chan result, 1)
+	err := first.withLock(ctx, func(db *gorm.DB) error {
+		go func() {
+			done, err := second.Up(ctx)
+			ch <- result{done, err}
+		}()
+		// 持有锁期间另一个实例的迁移等待
+		select {
+		case r := <-ch:
+			t.Fatalf("Up() finished while lock held: %v, %v", r.done, r.err)
+		case <-time.After(200 * time.Millisecond):
+		}
+		return first.run(db, first.migrations[0], true)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 锁释放后另一个实例看到迁移已执行，不再重复执行
+	if r := <-ch; r.err != nil || len(r.done) != 0 {
+		t.Errorf("Up() after lock released = %v, %v", r.done, r.err)
+	}
+}
+
+func TestLoadRequiresDown(t *testing.T) {
+	fsys := fstest.MapFS{
+		"mysql/000001_a.up.sql": {Data: []byte("CREATE TABLE a (id int);")},
+	}
+	if _, err := Load(fsys, "mysql"); err == nil {
+		t.Error("Load() should fail without down file")
+	}
+}
@@ -0,0 +1,130 @@
+package oidcx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"golang.org/x/oauth2"
+)
+
+// Claims ID Token 中登录所需的声明
+type Claims struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	// Username 由配置的 username_claim 取得
+	Username string
+}
+
+// Client OIDC 授权码 + PKCE 登录客户端
+type Client struct {
+	config *config.OIDCConfig
+
+	// 首次使用时才进行端点发现，避免身份提供方不可用时影响服务启动
+	mu       sync.Mutex
+	oauth2   *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+func New(cfg *config.Config) *Client {
+	return &Client{config: &cfg.OIDC}
+}
+
+// Enabled 是否启用 OIDC 登录
+func (c *Client) Enabled() bool {
+	return c.config.Enabled
+}
+
+// Issuer 身份提供方标识，用于区分外部身份来源
+func (c *Client) Issuer() string {
+	return c.config.Issuer
+}
+
+// GenerateVerifier 生成 PKCE code_verifier
+func GenerateVerifier() string {
+	return oauth2.GenerateVerifier()
+}
+
+// AuthCodeURL 构造跳转到身份提供方的授权地址
+func (c *Client) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	if err := c.discover(ctx); err != nil {
+		return "", err
+	}
+	return c.oauth2.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Exchange 使用授权码换取令牌，并校验 ID Token 的签名、iss、aud、exp 和 nonce
+func (c *Client) Exchange(ctx context.Context, code, verifier, nonce string) (*Claims, error) {
+	if err := c.discover(ctx); err != nil {
+		return nil, err
+	}
+	token, err := c.oauth2.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("exchange authorization code failed: %w", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("id_token missing in token response")
+	}
+	idToken, err := c.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("verify id_token failed: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("id_token nonce mismatch")
+	}
+
+	var raw map[string]any
+	if err := idToken.Claims(&raw); err != nil {
+		return nil, fmt.Errorf("decode id_token claims failed: %w", err)
+	}
+	claims := &Claims{
+		Issuer:  idToken.Issuer,
+		Subject: idToken.Subject,
+	}
+	claims.Email, _ = raw["email"].(string)
+	claims.EmailVerified, _ = raw["email_verified"].(bool)
+	claims.Name, _ = raw["name"].(string)
+	usernameClaim := c.config.UsernameClaim
+	if usernameClaim == "" {
+		usernameClaim = "preferred_username"
+	}
+	claims.Username, _ = raw[usernameClaim].(string)
+	return claims, nil
+}
+
+func (c *Client) discover(ctx context.Context) error {
+	if !c.config.Enabled {
+		return errors.New("oidc login is disabled")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.oauth2 != nil {
+		return nil
+	}
+
+	// 公钥集合会保存该上下文用于后续刷新，不能随请求结束而取消
+	provider, err := oidc.NewProvider(context.WithoutCancel(ctx), c.config.Issuer)
+	if err != nil {
+		return fmt.Errorf("discover oidc provider failed: %w", err)
+	}
+	scopes := c.config.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{oidc.ScopeOpenID, "profile", "email"}
+	}
+	c.verifier = provider.Verifier(&oidc.Config{ClientID: c.config.ClientID})
+	c.oauth2 = &oauth2.Config{
+		ClientID:     c.config.ClientID,
+		ClientSecret: c.config.ClientSecret,
+		RedirectURL:  c.config.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       scopes,
+	}
+	return nil
+}
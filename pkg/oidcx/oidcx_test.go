@@ -0,0 +1,194 @@
+package oidcx
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+)
+
+// mockProvider 进程内 OIDC 身份提供方，仅实现发现、JWKS 和令牌端点
+type mockProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu sync.Mutex
+	// codes 授权码对应的 PKCE challenge 和 nonce
+	codes map[string][2]string
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	p := &mockProvider{key: key, codes: make(map[string][2]string)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{
+			"issuer":                                p.server.URL,
+			"authorization_endpoint":                p.server.URL + "/authorize",
+			"token_endpoint":                        p.server.URL + "/token",
+			"jwks_uri":                              p.server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"kid": "test",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", p.handleToken)
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// authorize 模拟用户在身份提供方完成登录，返回授权码
+func (p *mockProvider) authorize(t *testing.T, authURL string) string {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse auth url: %v", err)
+	}
+	q := u.Query()
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		t.Fatalf("auth url missing PKCE challenge: %s", authURL)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	code := "code-" + q.Get("state")
+	p.codes[code] = [2]string{q.Get("code_challenge"), q.Get("nonce")}
+	return code
+}
+
+func (p *mockProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	p.mu.Lock()
+	entry, ok := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	p.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != entry[0] {
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(w, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	clientID, _, _ := r.BasicAuth()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":                p.server.URL,
+		"sub":                "user-123",
+		"aud":                clientID,
+		"exp":                time.Now().Add(time.Minute).Unix(),
+		"iat":                time.Now().Unix(),
+		"nonce":              entry[1],
+		"email":              "alice@example.com",
+		"email_verified":     true,
+		"name":               "Alice",
+		"preferred_username": "alice",
+	})
+	token.Header["kid"] = "test"
+	idToken, _ := token.SignedString(p.key)
+	writeJSON(w, map[string]any{
+		"access_token": "at",
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     idToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestClient(p *mockProvider) *Client {
+	return New(&config.Config{OIDC: config.OIDCConfig{
+		Enabled:      true,
+		Issuer:       p.server.URL,
+		ClientID:     "admin",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/api/auth/oidc/callback",
+	}})
+}
+
+func TestClient_Exchange(t *testing.T) {
+	p := newMockProvider(t)
+	client := newTestClient(p)
+	ctx := context.Background()
+
+	verifier := GenerateVerifier()
+	authURL, err := client.AuthCodeURL(ctx, "state1", "nonce1", verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+	code := p.authorize(t, authURL)
+
+	claims, err := client.Exchange(ctx, code, verifier, "nonce1")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if claims.Subject != "user-123" || claims.Issuer != p.server.URL || claims.Username != "alice" ||
+		claims.Email != "alice@example.com" || !claims.EmailVerified {
+		t.Errorf("claims = %+v", claims)
+	}
+}
+
+func TestClient_ExchangeRejected(t *testing.T) {
+	p := newMockProvider(t)
+	client := newTestClient(p)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		verifier func(v string) string
+		nonce    string
+		wantErr  string
+	}{
+		{name: "wrong verifier", verifier: func(string) string { return GenerateVerifier() }, nonce: "nonce", wantErr: "exchange"},
+		{name: "wrong nonce", verifier: func(v string) string { return v }, nonce: "other", wantErr: "nonce"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := GenerateVerifier()
+			authURL, err := client.AuthCodeURL(ctx, tt.name, "nonce", verifier)
+			if err != nil {
+				t.Fatalf("AuthCodeURL() error = %v", err)
+			}
+			code := p.authorize(t, authURL)
+			_, err = client.Exchange(ctx, code, tt.verifier(verifier), tt.nonce)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Exchange() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_Disabled(t *testing.T) {
+	client := New(&config.Config{})
+	if _, err := client.AuthCodeURL(context.Background(), "s", "n", "v"); err == nil {
+		t.Fatal("AuthCodeURL() expected error when disabled")
+	}
+}
@@ -0,0 +1,101 @@
+package otelx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/version"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// instrumentationName 应用自身创建的 span 使用的 Tracer 名称
+	instrumentationName = "github.com/wxlbd/gin-casbin-admin"
+	defaultServiceName  = "gin-casbin-admin"
+)
+
+// New 按配置创建全局 TracerProvider，并设置 W3C Trace Context 和 Baggage 传播。
+// 未配置导出方式时只设置传播，不记录 span。返回的 shutdown 在退出前导出剩余的 span
+func New(cfg *config.Config) (shutdown func(context.Context) error, err error) {
+	c := cfg.Tracing
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch c.Exporter {
+	case "":
+		return func(context.Context) error { return nil }, nil
+	case "otlp":
+		opts := []otlptracehttp.Option{}
+		if c.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(c.Endpoint))
+		}
+		if c.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		// 只创建客户端，不会连接采集端
+		exporter, err = otlptracehttp.New(context.Background(), opts...)
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", c.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create %s trace exporter: %w", c.Exporter, err)
+	}
+	tp := NewTracerProvider(c, sdktrace.WithBatcher(exporter))
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// NewTracerProvider 创建带服务信息和采样策略的 TracerProvider，未设置采样比例时全部采样。测试中可传入
+// sdktrace.WithSyncer(stdouttrace.New(stdouttrace.WithWriter(&buf))) 直接检查导出的 span
+func NewTracerProvider(c config.TracingConfig, opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	name := c.ServiceName
+	if name == "" {
+		name = defaultServiceName
+	}
+	ratio := 1.0
+	if c.SampleRatio != nil {
+		ratio = *c.SampleRatio
+	}
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(name),
+		semconv.ServiceVersion(version.Get().Version),
+	)
+	opts = append([]sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	}, opts...)
+	return sdktrace.NewTracerProvider(opts...)
+}
+
+// ServiceName 配置的服务名，用于 HTTP 服务端 span
+func ServiceName(cfg *config.Config) string {
+	if cfg.Tracing.ServiceName != "" {
+		return cfg.Tracing.ServiceName
+	}
+	return defaultServiceName
+}
+
+// Start 使用全局 TracerProvider 创建 span，name 一般为 "类型.方法"
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End 结束 span，err 不为空时记录错误并将 span 标记为失败
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
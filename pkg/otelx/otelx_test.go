@@ -0,0 +1,41 @@
+package otelx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+)
+
+func TestNewTracerProviderSampleRatio(t *testing.T) {
+	zero, half := 0.0, 0.5
+	tests := []struct {
+		name  string
+		ratio *float64
+		want  bool
+	}{
+		{"unset samples all", nil, true},
+		{"zero samples none", &zero, false},
+		{"ratio", &half, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp := NewTracerProvider(config.TracingConfig{SampleRatio: tt.ratio})
+			defer tp.Shutdown(context.Background())
+			sampled := 0
+			for i := 0; i < 100; i++ {
+				_, span := tp.Tracer("test").Start(context.Background(), "span")
+				if span.SpanContext().IsSampled() {
+					sampled++
+				}
+				span.End()
+			}
+			if got := sampled > 0; got != tt.want {
+				t.Errorf("sampled %d of 100 spans, want sampled = %v", sampled, tt.want)
+			}
+			if tt.ratio == nil && sampled != 100 {
+				t.Errorf("sampled %d of 100 spans, want all", sampled)
+			}
+		})
+	}
+}
@@ -6,11 +6,16 @@ import (
 	"time"
 
 	"github.com/wxlbd/gin-casbin-admin/pkg/config"
+	"github.com/wxlbd/gin-casbin-admin/pkg/log"
+	"github.com/wxlbd/gin-casbin-admin/pkg/metrics"
+	"go.uber.org/zap"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
-func New(cfg *config.Config) (*redis.Client, error) {
+// New 连接 Redis，返回的 cleanup 关闭连接池
+func New(cfg *config.Config, logger *log.Logger) (*redis.Client, func(), error) {
 	client := redis.NewClient(&redis.Options{
 		Addr:         cfg.Redis.Addr,
 		Password:     cfg.Redis.Password,
@@ -27,8 +32,28 @@ func New(cfg *config.Config) (*redis.Client, error) {
 	defer cancel()
 
 	if err := client.Ping(ctx).Err(); err != nil {
-		return nil, fmt.Errorf("redis connection failed: %w", err)
+		_ = client.Close()
+		return nil, nil, fmt.Errorf("redis connection failed: %w", err)
 	}
 
-	return client, nil
+	// 为每条命令创建 span
+	if err := redisotel.InstrumentTracing(client); err != nil {
+		_ = client.Close()
+		return nil, nil, fmt.Errorf("instrument redis tracing: %w", err)
+	}
+
+	// 连接池统计在每次抓取指标时读取
+	collector := metrics.NewRedisCollector(client)
+	if err := metrics.Registry.Register(collector); err != nil {
+		_ = client.Close()
+		return nil, nil, fmt.Errorf("register redis metrics: %w", err)
+	}
+
+	cleanup := func() {
+		metrics.Registry.Unregister(collector)
+		if err := client.Close(); err != nil {
+			logger.Error("关闭 Redis 连接失败", zap.Error(err))
+		}
+	}
+	return client, cleanup, nil
 }
@@ -0,0 +1,201 @@
+// Package sheetx 读写 CSV 和 XLSX 表格文件，用于批量导入导出
+package sheetx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+
+	// sheetName XLSX 文件写入的工作表名称
+	sheetName = "Sheet1"
+)
+
+// utf8BOM 写入 CSV 开头，保证 Excel 正确识别 UTF-8 中文
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// EscapeFormula 以 =、+、-、@ 或制表符、回车开头的文本会被 Excel 当作公式执行，
+// 在前面加单引号使其按文本显示。写入器会对字符串单元格自动转义
+func EscapeFormula(s string) string {
+	if s == "" {
+		return s
+	}
+	switch s[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + s
+	}
+	return s
+}
+
+// ParseFormat 解析格式名称，空值默认为 XLSX
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimPrefix(s, "."))) {
+	case "", FormatXLSX:
+		return FormatXLSX, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q", s)
+	}
+}
+
+// FormatFromFilename 根据文件扩展名判断格式
+func FormatFromFilename(name string) (Format, error) {
+	ext := filepath.Ext(name)
+	if ext == "" {
+		return "", fmt.Errorf("unsupported file %q", name)
+	}
+	return ParseFormat(ext)
+}
+
+// ContentType 格式对应的 MIME 类型
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+// ReadAll 读取第一个工作表的全部行，CSV 会去除 UTF-8 BOM，各行列数可以不同
+func ReadAll(r io.Reader, format Format) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		br := bufio.NewReader(r)
+		if b, err := br.Peek(len(utf8BOM)); err == nil && bytes.Equal(b, utf8BOM) {
+			_, _ = br.Discard(len(utf8BOM))
+		}
+		reader := csv.NewReader(br)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		return reader.ReadAll()
+	case FormatXLSX:
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, nil
+		}
+		return f.GetRows(sheets[0])
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// Writer 逐行写入表格
+type Writer interface {
+	Write(row []any) error
+	// Close 完成写入，XLSX 在此时输出到底层 io.Writer
+	Close() error
+	// Abort 放弃写入并释放资源，不再向底层 io.Writer 输出。写入中途出错时代替 Close 调用，
+	// 否则 XLSX 流式写入产生的临时文件不会被删除
+	Abort()
+}
+
+// NewWriter 创建指定格式的表格写入器
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		if _, err := w.Write(utf8BOM); err != nil {
+			return nil, err
+		}
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatXLSX:
+		f := excelize.NewFile()
+		sw, err := f.NewStreamWriter(sheetName)
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		return &xlsxWriter{out: w, file: f, sw: sw, row: 1}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+type csvWriter struct {
+	w    *csv.Writer
+	buf  []string
+	rows int
+}
+
+func (c *csvWriter) Write(row []any) error {
+	c.buf = c.buf[:0]
+	for _, v := range row {
+		if v == nil {
+			c.buf = append(c.buf, "")
+			continue
+		}
+		if s, ok := v.(string); ok {
+			c.buf = append(c.buf, EscapeFormula(s))
+			continue
+		}
+		c.buf = append(c.buf, fmt.Sprint(v))
+	}
+	if err := c.w.Write(c.buf); err != nil {
+		return err
+	}
+	// 定期刷新，避免大量数据堆积在缓冲区
+	if c.rows++; c.rows%100 == 0 {
+		c.w.Flush()
+		return c.w.Error()
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Abort() {}
+
+type xlsxWriter struct {
+	out  io.Writer
+	file *excelize.File
+	sw   *excelize.StreamWriter
+	row  int
+}
+
+func (x *xlsxWriter) Write(row []any) error {
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	x.row++
+	escaped := make([]any, len(row))
+	for i, v := range row {
+		if s, ok := v.(string); ok {
+			v = EscapeFormula(s)
+		}
+		escaped[i] = v
+	}
+	return x.sw.SetRow(cell, escaped)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	if err := x.sw.Flush(); err != nil {
+		return err
+	}
+	_, err := x.file.WriteTo(x.out)
+	return err
+}
+
+func (x *xlsxWriter) Abort() {
+	_ = x.file.Close()
+}
@@ -0,0 +1,67 @@
+package sheetx
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+func TestWriteAndReadAll(t *testing.T) {
+	rows := [][]any{
+		{"用户名", "昵称", "状态"},
+		{"alice", "Alice, \"A\"", 1},
+		{"bob", nil, 2},
+		{"=HYPERLINK(\"http://x\")", "@SUM(A1)", -3},
+	}
+	want := [][]string{
+		{"用户名", "昵称", "状态"},
+		{"alice", "Alice, \"A\"", "1"},
+		{"bob", "", "2"},
+		{"'=HYPERLINK(\"http://x\")", "'@SUM(A1)", "-3"},
+	}
+	for _, format := range []Format{FormatCSV, FormatXLSX} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, format)
+			if err != nil {
+				t.Fatalf("NewWriter() error = %v", err)
+			}
+			for _, row := range rows {
+				if err := w.Write(row); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			got, err := ReadAll(&buf, format)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("ReadAll() rows = %v", got)
+			}
+			for i := range want {
+				// XLSX 读取时会省略行尾空单元格
+				if !slices.Equal(got[i], want[i]) && !slices.Equal(append(got[i], ""), want[i]) {
+					t.Errorf("row %d = %q, want %q", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFormatFromFilename(t *testing.T) {
+	tests := map[string]Format{"users.CSV": FormatCSV, "users.xlsx": FormatXLSX}
+	for name, want := range tests {
+		if got, err := FormatFromFilename(name); err != nil || got != want {
+			t.Errorf("FormatFromFilename(%q) = %q, %v", name, got, err)
+		}
+	}
+	for _, name := range []string{"users", "users.xls", "users.txt"} {
+		if _, err := FormatFromFilename(name); err == nil {
+			t.Errorf("FormatFromFilename(%q) should fail", name)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Package version 记录构建信息，发布时通过 -ldflags 注入，例如：
+//
+//	go build -ldflags "-X github.com/wxlbd/gin-casbin-admin/pkg/version.Version=v1.0.0 \
+//	  -X github.com/wxlbd/gin-casbin-admin/pkg/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/wxlbd/gin-casbin-admin/pkg/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/server
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Info 构建信息
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get 返回构建信息，未注入提交时使用 Go 工具链在构建时记录的 VCS 版本
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+	if info.Commit == "" {
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			for _, s := range bi.Settings {
+				if s.Key == "vcs.revision" {
+					info.Commit = s.Value
+				}
+			}
+		}
+	}
+	return info
+}
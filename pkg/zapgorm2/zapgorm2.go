@@ -9,7 +9,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	gormlogger "gorm.io/gorm/logger"
 )
@@ -101,13 +100,10 @@ var gormPackage = filepath.Join("gorm.io", "gorm")
 
 func (l Logger) logger(ctx context.Context) *zap.Logger {
 	logger := l.ZapLogger
+	// 请求日志实例由 log.Logger.NewContext 存入 *gin.Context，其 Value 按字符串键读取，
+	// 服务层由 *gin.Context 派生的上下文同样可以取到，SQL 日志因此带有请求ID等字段
 	if ctx != nil {
-		if c, ok := ctx.(*gin.Context); ok {
-			ctx = c.Request.Context()
-		}
-		zl := ctx.Value(ctxLoggerKey)
-		ctxLogger, ok := zl.(*zap.Logger)
-		if ok {
+		if ctxLogger, ok := ctx.Value(ctxLoggerKey).(*zap.Logger); ok {
 			logger = ctxLogger
 		}
 	}